@@ -40,6 +40,7 @@ func RootCmd() *cobra.Command {
 
 	cmd.Flags().String("analyzers", "", "filename or url of the analyzers to use")
 	cmd.Flags().Bool("debug", false, "enable debug logging")
+	cmd.Flags().Int("analyzer-concurrency", 0, "the maximum number of analyzers to run at the same time. 0 uses the number of CPUs")
 
 	viper.BindPFlags(cmd.Flags())
 
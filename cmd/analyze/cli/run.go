@@ -48,7 +48,7 @@ func runAnalyzers(v *viper.Viper, bundlePath string) error {
 		specContent = string(body)
 	}
 
-	analyzeResults, err := analyzer.DownloadAndAnalyze(bundlePath, specContent)
+	analyzeResults, err := analyzer.DownloadAndAnalyzeWithConcurrency(bundlePath, specContent, v.GetInt("analyzer-concurrency"))
 	if err != nil {
 		return errors.Wrap(err, "failed to download and analyze bundle")
 	}
@@ -60,6 +60,10 @@ func runAnalyzers(v *viper.Viper, bundlePath string) error {
 			fmt.Printf("Warn: %s\n %s\n", analyzeResult.Title, analyzeResult.Message)
 		} else if analyzeResult.IsFail {
 			fmt.Printf("Fail: %s\n %s\n", analyzeResult.Title, analyzeResult.Message)
+		} else if analyzeResult.IsError {
+			fmt.Printf("Error: %s\n %s\n", analyzeResult.Title, analyzeResult.Message)
+		} else if analyzeResult.IsSkipped {
+			fmt.Printf("Skipped: %s\n %s\n", analyzeResult.Title, analyzeResult.Message)
 		}
 	}
 
@@ -47,6 +47,9 @@ that a cluster meets the requirements to run an application.`,
 	cmd.Flags().String("since", "", "force pod logs collectors to return logs newer than a relative duration like 5s, 2m, or 3h.")
 	cmd.Flags().StringP("output", "o", "", "specify the output file path for the preflight checks")
 	cmd.Flags().Bool("debug", false, "enable debug logging")
+	cmd.Flags().Int("concurrency", 1, "the maximum number of in-cluster collectors to run at the same time")
+	cmd.Flags().Duration("collector-timeout", 0, "the maximum amount of time each in-cluster collector is allowed to run, e.g. 30s or 5m. 0 means no limit")
+	cmd.Flags().Int("analyzer-concurrency", 0, "the maximum number of analyzers to run at the same time. 0 uses the number of CPUs")
 
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 
@@ -259,6 +259,9 @@ func collectInCluster(preflightSpec *troubleshootv1beta2.Preflight, progressCh c
 		IgnorePermissionErrors: v.GetBool("collect-without-permissions"),
 		ProgressChan:           progressCh,
 		KubernetesRestConfig:   restConfig,
+		Concurrency:            v.GetInt("concurrency"),
+		CollectorTimeout:       v.GetDuration("collector-timeout"),
+		AnalyzerConcurrency:    v.GetInt("analyzer-concurrency"),
 	}
 
 	if v.GetString("since") != "" || v.GetString("since-time") != "" {
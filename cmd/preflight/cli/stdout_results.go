@@ -45,15 +45,19 @@ func showStdoutResultsJSON(preflightName string, analyzeResults []*analyzerunner
 		Strict  bool   `json:"strict,omitempty"`
 	}
 	type Output struct {
-		Pass []ResultOutput `json:"pass,omitempty"`
-		Warn []ResultOutput `json:"warn,omitempty"`
-		Fail []ResultOutput `json:"fail,omitempty"`
+		Pass    []ResultOutput `json:"pass,omitempty"`
+		Warn    []ResultOutput `json:"warn,omitempty"`
+		Fail    []ResultOutput `json:"fail,omitempty"`
+		Error   []ResultOutput `json:"error,omitempty"`
+		Skipped []ResultOutput `json:"skipped,omitempty"`
 	}
 
 	output := Output{
-		Pass: []ResultOutput{},
-		Warn: []ResultOutput{},
-		Fail: []ResultOutput{},
+		Pass:    []ResultOutput{},
+		Warn:    []ResultOutput{},
+		Fail:    []ResultOutput{},
+		Error:   []ResultOutput{},
+		Skipped: []ResultOutput{},
 	}
 
 	for _, analyzeResult := range analyzeResults {
@@ -73,6 +77,10 @@ func showStdoutResultsJSON(preflightName string, analyzeResults []*analyzerunner
 			output.Warn = append(output.Warn, resultOutput)
 		} else if analyzeResult.IsFail {
 			output.Fail = append(output.Fail, resultOutput)
+		} else if analyzeResult.IsError {
+			output.Error = append(output.Error, resultOutput)
+		} else if analyzeResult.IsSkipped {
+			output.Skipped = append(output.Skipped, resultOutput)
 		}
 	}
 
@@ -96,6 +104,12 @@ func outputResult(analyzeResult *analyzerunner.AnalyzeResult) bool {
 	} else if analyzeResult.IsFail {
 		fmt.Printf("   --- FAIL: %s\n", analyzeResult.Title)
 		fmt.Printf("      --- %s\n", analyzeResult.Message)
+	} else if analyzeResult.IsError {
+		fmt.Printf("   --- ERROR: %s\n", analyzeResult.Title)
+		fmt.Printf("      --- %s\n", analyzeResult.Message)
+	} else if analyzeResult.IsSkipped {
+		fmt.Printf("   --- SKIP: %s\n", analyzeResult.Title)
+		fmt.Printf("      --- %s\n", analyzeResult.Message)
 	}
 
 	if analyzeResult.Strict {
@@ -17,13 +17,16 @@ func uploadResults(uri string, analyzeResults []*analyzerunner.AnalyzeResult) er
 	}
 	for _, analyzeResult := range analyzeResults {
 		uploadPreflightResult := &preflight.UploadPreflightResult{
-			Strict:  analyzeResult.Strict,
-			IsFail:  analyzeResult.IsFail,
-			IsWarn:  analyzeResult.IsWarn,
-			IsPass:  analyzeResult.IsPass,
-			Title:   analyzeResult.Title,
-			Message: analyzeResult.Message,
-			URI:     analyzeResult.URI,
+			Strict:    analyzeResult.Strict,
+			IsFail:    analyzeResult.IsFail,
+			IsWarn:    analyzeResult.IsWarn,
+			IsPass:    analyzeResult.IsPass,
+			IsError:   analyzeResult.IsError,
+			IsSkipped: analyzeResult.IsSkipped,
+			Title:     analyzeResult.Title,
+			Message:   analyzeResult.Message,
+			URI:       analyzeResult.URI,
+			Error:     analyzeResult.Error,
 		}
 
 		uploadPreflightResults.Results = append(uploadPreflightResults.Results, uploadPreflightResult)
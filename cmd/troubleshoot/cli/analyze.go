@@ -26,6 +26,7 @@ func Analyze() *cobra.Command {
 			viper.BindPFlag("bundle", cmd.Flags().Lookup("bundle"))
 			viper.BindPFlag("output", cmd.Flags().Lookup("output"))
 			viper.BindPFlag("quiet", cmd.Flags().Lookup("quiet"))
+			viper.BindPFlag("analyzer-concurrency", cmd.Flags().Lookup("analyzer-concurrency"))
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
@@ -38,7 +39,7 @@ func Analyze() *cobra.Command {
 				return err
 			}
 
-			result, err := analyzer.DownloadAndAnalyze(v.GetString("bundle"), analyzerSpec)
+			result, err := analyzer.DownloadAndAnalyzeWithConcurrency(v.GetString("bundle"), analyzerSpec, v.GetInt("analyzer-concurrency"))
 			if err != nil {
 				return err
 			}
@@ -76,6 +77,7 @@ func Analyze() *cobra.Command {
 	cmd.Flags().String("compatibility", "", "output compatibility mode: support-bundle")
 	cmd.Flags().MarkHidden("compatibility")
 	cmd.Flags().Bool("quiet", false, "enable/disable error messaging and only show parseable output")
+	cmd.Flags().Int("analyzer-concurrency", 0, "the maximum number of analyzers to run at the same time. 0 uses the number of CPUs")
 
 	viper.BindPFlags(cmd.Flags())
 
@@ -144,6 +144,10 @@ func drawAnalyzersTable(analyzeResults []*analyzerunner.AnalyzeResult) {
 			title = fmt.Sprintf("⚠️  %s", title)
 		} else if analyzeResult.IsFail {
 			title = fmt.Sprintf("✘  %s", title)
+		} else if analyzeResult.IsError {
+			title = fmt.Sprintf("?  %s", title)
+		} else if analyzeResult.IsSkipped {
+			title = fmt.Sprintf("-  %s", title)
 		}
 		table.Rows = append(table.Rows, []string{
 			title,
@@ -167,6 +171,18 @@ func drawAnalyzersTable(analyzeResults []*analyzerunner.AnalyzeResult) {
 			} else {
 				table.RowStyles[i] = ui.NewStyle(ui.ColorRed, ui.ColorClear)
 			}
+		} else if analyzeResult.IsError {
+			if i == selectedResult {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorMagenta, ui.ColorClear, ui.ModifierReverse)
+			} else {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorMagenta, ui.ColorClear)
+			}
+		} else if analyzeResult.IsSkipped {
+			if i == selectedResult {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierReverse)
+			} else {
+				table.RowStyles[i] = ui.NewStyle(ui.ColorWhite, ui.ColorClear)
+			}
 		}
 	}
 
@@ -186,6 +202,10 @@ func drawDetails(analysisResult *analyzerunner.AnalyzeResult) {
 		title.TextStyle = ui.NewStyle(ui.ColorYellow, ui.ColorClear, ui.ModifierBold)
 	} else if analysisResult.IsFail {
 		title.TextStyle = ui.NewStyle(ui.ColorRed, ui.ColorClear, ui.ModifierBold)
+	} else if analysisResult.IsError {
+		title.TextStyle = ui.NewStyle(ui.ColorMagenta, ui.ColorClear, ui.ModifierBold)
+	} else if analysisResult.IsSkipped {
+		title.TextStyle = ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierBold)
 	}
 	height := estimateNumberOfLines(title.Text, termWidth/2)
 	title.SetRect(termWidth/2, currentTop, termWidth, currentTop+height)
@@ -252,6 +272,10 @@ func save(analyzeResults []*analyzerunner.AnalyzeResult) (string, error) {
 			result = "Check WARN\n"
 		} else if analyzeResult.IsFail {
 			result = "Check FAIL\n"
+		} else if analyzeResult.IsError {
+			result = "Check ERROR\n"
+		} else if analyzeResult.IsSkipped {
+			result = "Check SKIPPED\n"
 		}
 
 		result = result + fmt.Sprintf("Title: %s\n", analyzeResult.Title)
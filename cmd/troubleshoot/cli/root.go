@@ -51,6 +51,9 @@ from a server that can be used to assist when troubleshooting a Kubernetes clust
 	cmd.Flags().String("since", "", "force pod logs collectors to return logs newer than a relative duration like 5s, 2m, or 3h.")
 	cmd.Flags().StringP("output", "o", "", "specify the output file path for the support bundle")
 	cmd.Flags().Bool("debug", false, "enable debug logging")
+	cmd.Flags().Int("concurrency", 1, "the maximum number of collectors to run at the same time")
+	cmd.Flags().Duration("collector-timeout", 0, "the maximum amount of time each collector is allowed to run, e.g. 30s or 5m. 0 means no limit")
+	cmd.Flags().Int("analyzer-concurrency", 0, "the maximum number of analyzers to run at the same time. 0 uses the number of CPUs")
 
 	// hidden in favor of the `insecure-skip-tls-verify` flag
 	cmd.Flags().Bool("allow-insecure-connections", false, "when set, do not verify TLS certs when retrieving spec and reporting results")
@@ -184,6 +184,9 @@ func runTroubleshoot(v *viper.Viper, arg string) error {
 		OutputPath:                v.GetString("output"),
 		Redact:                    v.GetBool("redact"),
 		FromCLI:                   true,
+		Concurrency:               v.GetInt("concurrency"),
+		CollectorTimeout:          v.GetDuration("collector-timeout"),
+		AnalyzerConcurrency:       v.GetInt("analyzer-concurrency"),
 	}
 
 	nonInteractiveOutput := analysisOutput{}
@@ -3,7 +3,9 @@ package analyzer
 import (
 	"fmt"
 	"reflect"
+	"runtime"
 	"strconv"
+	"sync"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -15,13 +17,20 @@ type AnalyzeResult struct {
 	IsPass bool
 	IsFail bool
 	IsWarn bool
-	Strict bool
+	// IsError is set when the analyzer could not run, e.g. because it crashed or a file it needs
+	// was not collected. It is not a failed check.
+	IsError bool
+	// IsSkipped is set when the analyzer did not run because its runIf condition was not met.
+	IsSkipped bool
+	Strict    bool
 
 	Title   string
 	Message string
 	URI     string
 	IconKey string
 	IconURI string
+	// Error describes why the analyzer could not run when IsError is set.
+	Error string
 
 	InvolvedObject *corev1.ObjectReference
 }
@@ -68,460 +77,441 @@ func HostAnalyze(hostAnalyzer *troubleshootv1beta2.HostAnalyze, getFile getColle
 	return result
 }
 
+// HostAnalyzeAll runs the host analyzers one after the other. Analyzers run after the analyzers
+// they depend on, and are skipped if their runIf condition is not met. Results are returned in the
+// same order as the analyzers.
+func HostAnalyzeAll(hostAnalyzers []*troubleshootv1beta2.HostAnalyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents) [][]*AnalyzeResult {
+	metas := make([]*troubleshootv1beta2.AnalyzeMeta, len(hostAnalyzers))
+	for i, hostAnalyzer := range hostAnalyzers {
+		metas[i] = getHostAnalyzeMeta(hostAnalyzer)
+	}
+
+	graph := newAnalyzerGraph(metas)
+
+	results := make([][]*AnalyzeResult, len(hostAnalyzers))
+	// host analyzers report their errors as results
+	errs := make([]error, len(hostAnalyzers))
+	ran := make([]bool, len(hostAnalyzers))
+
+	var run func(i int)
+	run = func(i int) {
+		if ran[i] {
+			return
+		}
+		ran[i] = true
+
+		if graph.errs[i] != nil {
+			analyzer, _ := GetHostAnalyzer(hostAnalyzers[i])
+			results[i] = NewAnalyzeResultError(analyzer, graph.errs[i])
+			return
+		}
+
+		// analyzers with a broken dependency have an error, so this never follows a cycle
+		for _, dep := range graph.deps[i] {
+			run(dep)
+		}
+
+		skipReason, err := graph.skipReason(i, results, errs)
+		if err != nil {
+			analyzer, _ := GetHostAnalyzer(hostAnalyzers[i])
+			results[i] = NewAnalyzeResultError(analyzer, err)
+			return
+		}
+		if skipReason != "" {
+			results[i] = skippedHostAnalyzerResult(hostAnalyzers[i], skipReason)
+			return
+		}
+
+		results[i] = HostAnalyze(hostAnalyzers[i], getFile, findFiles)
+	}
+
+	for i := range hostAnalyzers {
+		run(i)
+	}
+
+	return results
+}
+
 func NewAnalyzeResultError(analyzer HostAnalyzer, err error) []*AnalyzeResult {
 	if analyzer != nil {
 		return []*AnalyzeResult{{
-			IsFail:  true,
+			IsError: true,
 			Title:   analyzer.Title(),
 			Message: fmt.Sprintf("Analyzer Failed: %v", err),
+			Error:   err.Error(),
 		}}
 	}
 	return []*AnalyzeResult{{
-		IsFail:  true,
+		IsError: true,
 		Title:   "nil analyzer",
 		Message: fmt.Sprintf("Analyzer Failed: %v", err),
+		Error:   err.Error(),
 	}}
 }
 
-func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
-	if analyzer == nil {
-		return nil, errors.New("nil analyzer")
-	}
-
-	if analyzer.ClusterVersion != nil {
-		isExcluded, err := isExcluded(analyzer.ClusterVersion.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeClusterVersion(analyzer.ClusterVersion, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.ClusterVersion.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.StorageClass != nil {
-		isExcluded, err := isExcluded(analyzer.StorageClass.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeStorageClass(analyzer.StorageClass, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.StorageClass.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.CustomResourceDefinition != nil {
-		isExcluded, err := isExcluded(analyzer.CustomResourceDefinition.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeCustomResourceDefinition(analyzer.CustomResourceDefinition, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.CustomResourceDefinition.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.Ingress != nil {
-		isExcluded, err := isExcluded(analyzer.Ingress.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeIngress(analyzer.Ingress, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.Ingress.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.Secret != nil {
-		isExcluded, err := isExcluded(analyzer.Secret.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeSecret(analyzer.Secret, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.Secret.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.ConfigMap != nil {
-		isExcluded, err := isExcluded(analyzer.ConfigMap.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeConfigMap(analyzer.ConfigMap, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.ConfigMap.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
-	}
-	if analyzer.ImagePullSecret != nil {
-		isExcluded, err := isExcluded(analyzer.ImagePullSecret.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeImagePullSecret(analyzer.ImagePullSecret, findFiles)
-		if err != nil {
-			return nil, err
+// NewAnalyzerErrorResult builds the result reported for an analyzer that could not run.
+func NewAnalyzerErrorResult(analyzer *troubleshootv1beta2.Analyze, err error) []*AnalyzeResult {
+	title := "Analyzer Failed"
+	strict := false
+	if analyzer != nil {
+		if analyzerInst, ok := GetAnalyzer(analyzer); ok {
+			title = analyzerInst.Title()
 		}
-		result.Strict = analyzer.ImagePullSecret.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+		strict = getStrictFlag(analyzer).BoolOrDefaultFalse()
 	}
-	if analyzer.DeploymentStatus != nil {
-		isExcluded, err := isExcluded(analyzer.DeploymentStatus.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeDeploymentStatus(analyzer.DeploymentStatus, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.DeploymentStatus.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+
+	return []*AnalyzeResult{{
+		IsError: true,
+		Strict:  strict,
+		Title:   title,
+		Message: fmt.Sprintf("Analyzer Failed: %v", err),
+		Error:   err.Error(),
+	}}
+}
+
+type Analyzer interface {
+	Title() string
+	IsExcluded() (bool, error)
+	Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error)
+}
+
+// AnalyzerFactory builds an Analyzer from the spec of a custom analyzer.
+type AnalyzerFactory func(analyzer *troubleshootv1beta2.CustomAnalyze) Analyzer
+
+var (
+	customAnalyzers   = map[string]AnalyzerFactory{}
+	customAnalyzersMu sync.RWMutex
+)
+
+// RegisterAnalyzer makes an analyzer type available to specs through the "custom" analyzer.
+// It is meant to be called from an init function of a program that embeds troubleshoot.
+func RegisterAnalyzer(analyzerType string, factory AnalyzerFactory) error {
+	if analyzerType == "" {
+		return errors.New("analyzer type is required")
 	}
-	if analyzer.StatefulsetStatus != nil {
-		isExcluded, err := isExcluded(analyzer.StatefulsetStatus.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeStatefulsetStatus(analyzer.StatefulsetStatus, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.StatefulsetStatus.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+	if factory == nil {
+		return errors.Errorf("analyzer %q has no factory", analyzerType)
 	}
-	if analyzer.JobStatus != nil {
-		isExcluded, err := isExcluded(analyzer.JobStatus.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeJobStatus(analyzer.JobStatus, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.JobStatus.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+
+	customAnalyzersMu.Lock()
+	defer customAnalyzersMu.Unlock()
+
+	if _, ok := customAnalyzers[analyzerType]; ok {
+		return errors.Errorf("analyzer %q is already registered", analyzerType)
 	}
-	if analyzer.ReplicaSetStatus != nil {
-		isExcluded, err := isExcluded(analyzer.ReplicaSetStatus.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeReplicaSetStatus(analyzer.ReplicaSetStatus, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.ReplicaSetStatus.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+	customAnalyzers[analyzerType] = factory
+
+	return nil
+}
+
+func getCustomAnalyzer(analyzer *troubleshootv1beta2.CustomAnalyze) (Analyzer, bool) {
+	customAnalyzersMu.RLock()
+	factory, ok := customAnalyzers[analyzer.Type]
+	customAnalyzersMu.RUnlock()
+
+	if !ok {
+		return nil, false
 	}
-	if analyzer.ClusterPodStatuses != nil {
-		isExcluded, err := isExcluded(analyzer.ClusterPodStatuses.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := clusterPodStatuses(analyzer.ClusterPodStatuses, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.ClusterPodStatuses.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+	return factory(analyzer), true
+}
+
+func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) (Analyzer, bool) {
+	switch {
+	case analyzer.ClusterVersion != nil:
+		return &AnalyzeClusterVersion{analyzer.ClusterVersion}, true
+	case analyzer.DeprecatedApis != nil:
+		return &AnalyzeDeprecatedApis{analyzer.DeprecatedApis}, true
+	case analyzer.VersionSkew != nil:
+		return &AnalyzeVersionSkew{analyzer: analyzer.VersionSkew}, true
+	case analyzer.StorageClass != nil:
+		return &AnalyzeStorageClass{analyzer.StorageClass}, true
+	case analyzer.CustomResourceDefinition != nil:
+		return &AnalyzeCustomResourceDefinition{analyzer.CustomResourceDefinition}, true
+	case analyzer.CustomResourceStatus != nil:
+		return &AnalyzeCustomResourceStatus{analyzer: analyzer.CustomResourceStatus}, true
+	case analyzer.Ingress != nil:
+		return &AnalyzeIngress{analyzer.Ingress}, true
+	case analyzer.Secret != nil:
+		return &AnalyzeSecret{analyzer.Secret}, true
+	case analyzer.ConfigMap != nil:
+		return &AnalyzeConfigMap{analyzer.ConfigMap}, true
+	case analyzer.ImagePullSecret != nil:
+		return &AnalyzeImagePullSecret{analyzer.ImagePullSecret}, true
+	case analyzer.DeploymentStatus != nil:
+		return &AnalyzeDeploymentStatus{analyzer.DeploymentStatus}, true
+	case analyzer.StatefulsetStatus != nil:
+		return &AnalyzeStatefulsetStatus{analyzer.StatefulsetStatus}, true
+	case analyzer.JobStatus != nil:
+		return &AnalyzeJobStatus{analyzer.JobStatus}, true
+	case analyzer.CronJobStatus != nil:
+		return &AnalyzeCronJobStatus{analyzer: analyzer.CronJobStatus}, true
+	case analyzer.ReplicaSetStatus != nil:
+		return &AnalyzeReplicaSetStatus{analyzer.ReplicaSetStatus}, true
+	case analyzer.PVCStatus != nil:
+		return &AnalyzePVCStatus{analyzer.PVCStatus}, true
+	case analyzer.Events != nil:
+		return &AnalyzeEvents{analyzer: analyzer.Events}, true
+	case analyzer.ServiceEndpoints != nil:
+		return &AnalyzeServiceEndpoints{analyzer: analyzer.ServiceEndpoints}, true
+	case analyzer.ClusterPodStatuses != nil:
+		return &AnalyzeClusterPodStatuses{analyzer: analyzer.ClusterPodStatuses}, true
+	case analyzer.ContainerStatuses != nil:
+		return &AnalyzeContainerStatuses{analyzer: analyzer.ContainerStatuses}, true
+	case analyzer.ContainerRuntime != nil:
+		return &AnalyzeContainerRuntime{analyzer: analyzer.ContainerRuntime}, true
+	case analyzer.Distribution != nil:
+		return &AnalyzeDistribution{analyzer: analyzer.Distribution}, true
+	case analyzer.NodeResources != nil:
+		return &AnalyzeNodeResources{analyzer: analyzer.NodeResources}, true
+	case analyzer.NodeConditions != nil:
+		return &AnalyzeNodeConditions{analyzer: analyzer.NodeConditions}, true
+	case analyzer.ClusterCapacity != nil:
+		return &AnalyzeClusterCapacity{analyzer: analyzer.ClusterCapacity}, true
+	case analyzer.ResourceQuota != nil:
+		return &AnalyzeResourceQuota{analyzer.ResourceQuota}, true
+	case analyzer.TextAnalyze != nil:
+		return &AnalyzeTextAnalyze{analyzer.TextAnalyze}, true
+	case analyzer.YamlCompare != nil:
+		return &AnalyzeYamlCompare{analyzer.YamlCompare}, true
+	case analyzer.JsonCompare != nil:
+		return &AnalyzeJsonCompare{analyzer.JsonCompare}, true
+	case analyzer.HTTP != nil:
+		return &AnalyzeHTTP{analyzer.HTTP}, true
+	case analyzer.ExecResult != nil:
+		return &AnalyzeExecResult{analyzer.ExecResult}, true
+	case analyzer.Postgres != nil:
+		return &AnalyzePostgres{analyzer.Postgres}, true
+	case analyzer.Mysql != nil:
+		return &AnalyzeMysql{analyzer.Mysql}, true
+	case analyzer.Redis != nil:
+		return &AnalyzeRedis{analyzer.Redis}, true
+	case analyzer.CephStatus != nil:
+		return &AnalyzeCephStatus{analyzer.CephStatus}, true
+	case analyzer.HelmRelease != nil:
+		return &AnalyzeHelmRelease{analyzer.HelmRelease}, true
+	case analyzer.Longhorn != nil:
+		return &AnalyzeLonghorn{analyzer.Longhorn}, true
+	case analyzer.Collectd != nil:
+		return &AnalyzeCollectd{analyzer.Collectd}, true
+	case analyzer.RegistryImages != nil:
+		return &AnalyzeRegistryImages{analyzer.RegistryImages}, true
+	case analyzer.WeaveReport != nil:
+		return &AnalyzeWeaveReport{analyzer.WeaveReport}, true
+	case analyzer.Calico != nil:
+		return &AnalyzeCalico{analyzer.Calico}, true
+	case analyzer.Cilium != nil:
+		return &AnalyzeCilium{analyzer.Cilium}, true
+	case analyzer.DNS != nil:
+		return &AnalyzeDNS{analyzer.DNS}, true
+	case analyzer.Sysctl != nil:
+		return &AnalyzeSysctl{analyzer.Sysctl}, true
+	case analyzer.Custom != nil:
+		return getCustomAnalyzer(analyzer.Custom)
+	default:
+		return nil, false
 	}
-	if analyzer.ContainerRuntime != nil {
-		isExcluded, err := isExcluded(analyzer.ContainerRuntime.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeContainerRuntime(analyzer.ContainerRuntime, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.ContainerRuntime.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+}
+
+func Analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	return analyze(analyzer, getFile, findFiles, nil)
+}
+
+// collectedObjectsAnalyzer is implemented by analyzers that parse files read by many analyzers, such
+// as the nodes and pods, so that the parsed objects can be shared through the cache of the run.
+type collectedObjectsAnalyzer interface {
+	setCollectedObjects(objects *collectedFileCache)
+}
+
+func analyze(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents, objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	if analyzer == nil {
+		return nil, errors.New("nil analyzer")
 	}
-	if analyzer.Distribution != nil {
-		isExcluded, err := isExcluded(analyzer.Distribution.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeDistribution(analyzer.Distribution, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.Distribution.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+
+	analyzerInst, ok := GetAnalyzer(analyzer)
+	if !ok {
+		return nil, errors.New("invalid analyzer")
 	}
-	if analyzer.NodeResources != nil {
-		isExcluded, err := isExcluded(analyzer.NodeResources.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeNodeResources(analyzer.NodeResources, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.NodeResources.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+	if a, ok := analyzerInst.(collectedObjectsAnalyzer); ok {
+		a.setCollectedObjects(objects)
 	}
-	if analyzer.TextAnalyze != nil {
-		isExcluded, err := isExcluded(analyzer.TextAnalyze.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeTextAnalyze(analyzer.TextAnalyze, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.TextAnalyze.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+
+	isExcluded, err := analyzerInst.IsExcluded()
+	if err != nil {
+		return nil, err
 	}
-	if analyzer.YamlCompare != nil {
-		isExcluded, err := isExcluded(analyzer.YamlCompare.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeYamlCompare(analyzer.YamlCompare, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.YamlCompare.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+	if isExcluded {
+		return nil, nil
 	}
-	if analyzer.JsonCompare != nil {
-		isExcluded, err := isExcluded(analyzer.JsonCompare.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeJsonCompare(analyzer.JsonCompare, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.JsonCompare.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+
+	results, err := analyzerInst.Analyze(getFile, findFiles)
+	if err != nil {
+		return nil, err
 	}
-	if analyzer.Postgres != nil {
-		isExcluded, err := isExcluded(analyzer.Postgres.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzePostgres(analyzer.Postgres, getFile)
-		if err != nil {
-			return nil, err
+
+	strict := getStrictFlag(analyzer).BoolOrDefaultFalse()
+	for _, result := range results {
+		if result != nil {
+			result.Strict = strict
 		}
-		result.Strict = analyzer.Postgres.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
 	}
-	if analyzer.Mysql != nil {
-		isExcluded, err := isExcluded(analyzer.Mysql.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeMysql(analyzer.Mysql, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.Mysql.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+
+	return results, nil
+}
+
+// AnalyzerConcurrencyOrDefault returns concurrency, or the number of CPUs if it is 0.
+func AnalyzerConcurrencyOrDefault(concurrency int) int {
+	if concurrency == 0 {
+		return runtime.NumCPU()
 	}
-	if analyzer.Redis != nil {
-		isExcluded, err := isExcluded(analyzer.Redis.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeRedis(analyzer.Redis, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.Redis.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+	return concurrency
+}
+
+// AnalyzeAll runs the analyzers with at most concurrency of them running at the same time. The
+// analyzers share a cache of the collected files so that each file is only read once. Analyzers
+// wait for the analyzers they depend on, and are skipped if their runIf condition is not met.
+// Results and errors are returned in the same order as the analyzers, regardless of when each one
+// finished.
+func AnalyzeAll(analyzers []*troubleshootv1beta2.Analyze, getFile getCollectedFileContents, findFiles getChildCollectedFileContents, concurrency int) ([][]*AnalyzeResult, []error) {
+	if concurrency < 1 {
+		concurrency = 1
 	}
-	if analyzer.CephStatus != nil {
-		isExcluded, err := isExcluded(analyzer.CephStatus.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := cephStatus(analyzer.CephStatus, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.CephStatus.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+
+	metas := make([]*troubleshootv1beta2.AnalyzeMeta, len(analyzers))
+	for i, analyzer := range analyzers {
+		metas[i] = getAnalyzeMeta(analyzer)
 	}
-	if analyzer.Longhorn != nil {
-		isExcluded, err := isExcluded(analyzer.Longhorn.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := longhorn(analyzer.Longhorn, getFile, findFiles)
-		if err != nil {
-			return nil, err
-		}
-		for i := range results {
-			results[i].Strict = analyzer.Longhorn.Strict.BoolOrDefaultFalse()
-		}
-		return results, nil
+
+	cache := newCollectedFileCache(getFile, findFiles)
+	graph := newAnalyzerGraph(metas)
+
+	results := make([][]*AnalyzeResult, len(analyzers))
+	errs := make([]error, len(analyzers))
+
+	done := make([]chan struct{}, len(analyzers))
+	for i := range done {
+		done[i] = make(chan struct{})
 	}
 
-	if analyzer.RegistryImages != nil {
-		isExcluded, err := isExcluded(analyzer.RegistryImages.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeRegistry(analyzer.RegistryImages, getFile)
-		if err != nil {
-			return nil, err
-		}
-		result.Strict = analyzer.RegistryImages.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, analyzer := range analyzers {
+		wg.Add(1)
+		go func(i int, analyzer *troubleshootv1beta2.Analyze) {
+			defer wg.Done()
+			defer close(done[i])
+
+			if graph.errs[i] != nil {
+				errs[i] = graph.errs[i]
+				return
+			}
+
+			// dependencies have to finish before taking a slot, otherwise waiting analyzers could
+			// hold every slot while the analyzers they wait for never get one
+			for _, dep := range graph.deps[i] {
+				<-done[dep]
+			}
+
+			skipReason, err := graph.skipReason(i, results, errs)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if skipReason != "" {
+				results[i], errs[i] = skippedAnalyzerResult(analyzer, skipReason)
+				return
+			}
+
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			results[i], errs[i] = analyze(analyzer, cache.GetFile, cache.FindFiles, cache)
+		}(i, analyzer)
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+func GetExcludeFlag(analyzer *troubleshootv1beta2.Analyze) *multitype.BoolOrString {
+	if analyzer == nil {
+		return nil
 	}
 
-	if analyzer.WeaveReport != nil {
-		isExcluded, err := isExcluded(analyzer.WeaveReport.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		results, err := analyzeWeaveReport(analyzer.WeaveReport, findFiles)
-		if err != nil {
-			return nil, err
+	reflected := reflect.ValueOf(analyzer).Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		if reflected.Field(i).IsNil() {
+			continue
 		}
-		for i := range results {
-			results[i].Strict = analyzer.WeaveReport.Strict.BoolOrDefaultFalse()
+
+		field := reflect.Indirect(reflected.Field(i)).FieldByName("Exclude")
+		exclude, ok := field.Interface().(*multitype.BoolOrString)
+		if !ok {
+			continue
 		}
-		return results, nil
+		return exclude
 	}
 
-	if analyzer.Sysctl != nil {
-		isExcluded, err := isExcluded(analyzer.Sysctl.Exclude)
-		if err != nil {
-			return nil, err
-		}
-		if isExcluded {
-			return nil, nil
-		}
-		result, err := analyzeSysctl(analyzer.Sysctl, findFiles)
-		if err != nil {
-			return nil, err
+	return nil
+}
+
+func getStrictFlag(analyzer *troubleshootv1beta2.Analyze) *multitype.BoolOrString {
+	reflected := reflect.ValueOf(analyzer).Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		if reflected.Field(i).IsNil() {
+			continue
 		}
-		if result == nil {
-			return []*AnalyzeResult{}, nil
+
+		field := reflect.Indirect(reflected.Field(i)).FieldByName("Strict")
+		strict, ok := field.Interface().(*multitype.BoolOrString)
+		if !ok {
+			continue
 		}
-		result.Strict = analyzer.Sysctl.Strict.BoolOrDefaultFalse()
-		return []*AnalyzeResult{result}, nil
+		return strict
 	}
 
-	return nil, errors.New("invalid analyzer")
+	return nil
 }
 
-func GetExcludeFlag(analyzer *troubleshootv1beta2.Analyze) *multitype.BoolOrString {
+func getAnalyzeMeta(analyzer *troubleshootv1beta2.Analyze) *troubleshootv1beta2.AnalyzeMeta {
 	if analyzer == nil {
 		return nil
 	}
+	return embeddedAnalyzeMeta(reflect.ValueOf(analyzer).Elem())
+}
 
-	reflected := reflect.ValueOf(analyzer).Elem()
+func getHostAnalyzeMeta(hostAnalyzer *troubleshootv1beta2.HostAnalyze) *troubleshootv1beta2.AnalyzeMeta {
+	if hostAnalyzer == nil {
+		return nil
+	}
+	return embeddedAnalyzeMeta(reflect.ValueOf(hostAnalyzer).Elem())
+}
+
+// embeddedAnalyzeMeta returns the AnalyzeMeta of the analyzer set in reflected, which is an Analyze
+// or a HostAnalyze.
+func embeddedAnalyzeMeta(reflected reflect.Value) *troubleshootv1beta2.AnalyzeMeta {
 	for i := 0; i < reflected.NumField(); i++ {
 		if reflected.Field(i).IsNil() {
 			continue
 		}
 
-		field := reflect.Indirect(reflected.Field(i)).FieldByName("Exclude")
-		exclude, ok := field.Interface().(*multitype.BoolOrString)
+		field := reflect.Indirect(reflected.Field(i)).FieldByName("AnalyzeMeta")
+		if !field.IsValid() {
+			continue
+		}
+		meta, ok := field.Addr().Interface().(*troubleshootv1beta2.AnalyzeMeta)
 		if !ok {
 			continue
 		}
-		return exclude
+		return meta
 	}
 
 	return nil
 }
+
+func analyzerTitleOrDefault(meta troubleshootv1beta2.AnalyzeMeta, defaultTitle string) string {
+	if meta.CheckName != "" {
+		return meta.CheckName
+	}
+	return defaultTitle
+}
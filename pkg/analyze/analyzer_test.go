@@ -1,12 +1,17 @@
 package analyzer
 
 import (
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func Test_GetExcludeFlag(t *testing.T) {
@@ -65,3 +70,179 @@ func Test_GetExcludeFlag(t *testing.T) {
 		})
 	}
 }
+
+type testCustomAnalyzer struct {
+	analyzer *troubleshootv1beta2.CustomAnalyze
+}
+
+func (a *testCustomAnalyzer) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Test Custom")
+}
+
+func (a *testCustomAnalyzer) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *testCustomAnalyzer) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	contents, err := getFile("custom/data.txt")
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{{
+		Title:   a.Title(),
+		IsPass:  true,
+		Message: string(contents) + " " + string(a.analyzer.Spec.Raw),
+	}}, nil
+}
+
+func Test_RegisterAnalyzer(t *testing.T) {
+	req := require.New(t)
+
+	err := RegisterAnalyzer("test-custom", func(analyzer *troubleshootv1beta2.CustomAnalyze) Analyzer {
+		return &testCustomAnalyzer{analyzer}
+	})
+	req.NoError(err)
+
+	err = RegisterAnalyzer("test-custom", func(analyzer *troubleshootv1beta2.CustomAnalyze) Analyzer {
+		return &testCustomAnalyzer{analyzer}
+	})
+	req.Error(err)
+
+	getFile := func(n string) ([]byte, error) {
+		return []byte("collected"), nil
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		return nil, nil
+	}
+
+	results, err := Analyze(&troubleshootv1beta2.Analyze{
+		Custom: &troubleshootv1beta2.CustomAnalyze{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+				CheckName: "My Check",
+				Strict:    multitype.FromBool(true),
+			},
+			Type: "test-custom",
+			Spec: runtime.RawExtension{Raw: []byte(`{"key":"value"}`)},
+		},
+	}, getFile, findFiles)
+	req.NoError(err)
+	assert.Equal(t, []*AnalyzeResult{{
+		Title:   "My Check",
+		IsPass:  true,
+		Strict:  true,
+		Message: `collected {"key":"value"}`,
+	}}, results)
+
+	results, err = Analyze(&troubleshootv1beta2.Analyze{
+		Custom: &troubleshootv1beta2.CustomAnalyze{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+				Exclude: multitype.FromBool(true),
+			},
+			Type: "test-custom",
+		},
+	}, getFile, findFiles)
+	req.NoError(err)
+	assert.Nil(t, results)
+
+	_, err = Analyze(&troubleshootv1beta2.Analyze{
+		Custom: &troubleshootv1beta2.CustomAnalyze{
+			Type: "not-registered",
+		},
+	}, getFile, findFiles)
+	req.Error(err)
+}
+
+func Test_AnalyzeAll(t *testing.T) {
+	req := require.New(t)
+
+	var reads int32
+	getFile := func(n string) ([]byte, error) {
+		return nil, nil
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		atomic.AddInt32(&reads, 1)
+		time.Sleep(10 * time.Millisecond)
+		return map[string][]byte{n: []byte("status: ready")}, nil
+	}
+
+	analyzers := []*troubleshootv1beta2.Analyze{}
+	for i := 0; i < 20; i++ {
+		analyzers = append(analyzers, &troubleshootv1beta2.Analyze{
+			TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: fmt.Sprintf("check %d", i),
+				},
+				FileName:     "status.txt",
+				RegexPattern: "ready",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "pass"}},
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "fail"}},
+				},
+			},
+		})
+	}
+	analyzers = append(analyzers, &troubleshootv1beta2.Analyze{})
+
+	results, errs := AnalyzeAll(analyzers, getFile, findFiles, 5)
+	req.Len(results, 21)
+	req.Len(errs, 21)
+	for i := 0; i < 20; i++ {
+		req.NoError(errs[i])
+		req.Len(results[i], 1)
+		assert.Equal(t, fmt.Sprintf("check %d", i), results[i][0].Title)
+		assert.True(t, results[i][0].IsPass)
+	}
+	req.Error(errs[20])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+}
+
+func Test_collectedFileCacheObjects(t *testing.T) {
+	nodes := `{"items": [{"metadata": {"name": "node-1"}}]}`
+	var reads int32
+	getFile := func(n string) ([]byte, error) {
+		atomic.AddInt32(&reads, 1)
+		return []byte(nodes), nil
+	}
+
+	cache := newCollectedFileCache(getFile, nil)
+	for i := 0; i < 3; i++ {
+		actual, err := readCollectedNodes(cache, cache.GetFile)
+		require.NoError(t, err)
+		assert.Equal(t, "node-1", actual.Items[0].Name)
+	}
+	first, _ := readCollectedNodes(cache, cache.GetFile)
+	second, _ := readCollectedNodes(cache, cache.GetFile)
+	assert.Same(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+
+	// without a cache the file is parsed on every call
+	_, err := readCollectedNodes(nil, getFile)
+	require.NoError(t, err)
+	_, err = readCollectedNodes(nil, getFile)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&reads))
+}
+
+func Test_NewAnalyzerErrorResult(t *testing.T) {
+	results := NewAnalyzerErrorResult(&troubleshootv1beta2.Analyze{
+		TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+				CheckName: "my check",
+				Strict:    multitype.FromBool(true),
+			},
+		},
+	}, errors.New("file not found"))
+
+	assert.Equal(t, []*AnalyzeResult{{
+		IsError: true,
+		Strict:  true,
+		Title:   "my check",
+		Message: "Analyzer Failed: file not found",
+		Error:   "file not found",
+	}}, results)
+
+	results = NewAnalyzerErrorResult(nil, errors.New("nil analyzer"))
+	assert.Equal(t, "Analyzer Failed", results[0].Title)
+	assert.True(t, results[0].IsError)
+	assert.False(t, results[0].IsFail)
+}
@@ -0,0 +1,251 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type AnalyzeCalico struct {
+	analyzer *troubleshootv1beta2.CalicoAnalyze
+}
+
+func (a *AnalyzeCalico) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Calico")
+}
+
+func (a *AnalyzeCalico) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCalico) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeCalico(a.analyzer, findFiles)
+}
+
+// relevant fields of the IPPool and IPAMBlock custom resources
+type calicoIPPoolList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			CIDR      string `json:"cidr"`
+			BlockSize int    `json:"blockSize"`
+			Disabled  bool   `json:"disabled"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+type calicoIPAMBlockList struct {
+	Items []struct {
+		Spec struct {
+			CIDR        string  `json:"cidr"`
+			Affinity    *string `json:"affinity"`
+			Allocations []*int  `json:"allocations"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// calicoIPPoolUsage is the number of addresses of an enabled IP pool that are assigned to pods, and
+// the number of its blocks that are affine to a node. Calico assigns addresses from the blocks
+// affine to the node of a pod, so new nodes cannot get any addresses once every block is claimed.
+type calicoIPPoolUsage struct {
+	Name         string
+	CIDR         string
+	Size         float64
+	Active       int
+	Blocks       float64
+	AffineBlocks int
+}
+
+func analyzeCalico(analyzer *troubleshootv1beta2.CalicoAnalyze, findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	pathPrefix := collect.GetCalicoCollectorFilepath(analyzer.CollectorName)
+
+	files, err := findFiles(path.Join(pathPrefix, "nodes", "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find calico node status files")
+	}
+
+	nodes := []collect.CalicoNodeStatus{}
+	for name, file := range files {
+		node := collect.CalicoNodeStatus{}
+		if err := json.Unmarshal(file, &node); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal calico node status from %s", name)
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+
+	pools, err := getCalicoIPPoolUsage(pathPrefix, findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 && len(pools) == 0 {
+		return nil, nil
+	}
+
+	results := []*AnalyzeResult{}
+
+	results = append(results, analyzeCalicoIPAMPools(pools)...)
+	results = append(results, analyzeCalicoFelix(nodes)...)
+	results = append(results, analyzeCalicoBGPPeers(nodes)...)
+
+	if len(results) == 0 {
+		results = append(results, &AnalyzeResult{
+			Title:   analyzerTitleOrDefault(analyzer.AnalyzeMeta, "Calico"),
+			IsPass:  true,
+			Message: "No issues detected in calico status",
+		})
+	}
+
+	return results, nil
+}
+
+// getCalicoIPPoolUsage counts the addresses allocated in the IPAM blocks of every enabled IP pool.
+// The files are missing when the custom resources could not be listed, and no pools are returned.
+func getCalicoIPPoolUsage(pathPrefix string, findFiles func(string) (map[string][]byte, error)) ([]calicoIPPoolUsage, error) {
+	poolFiles, err := findFiles(path.Join(pathPrefix, "ippools.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find calico ip pools")
+	}
+	blockFiles, err := findFiles(path.Join(pathPrefix, "ipamblocks.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find calico ipam blocks")
+	}
+
+	pools := calicoIPPoolList{}
+	for name, file := range poolFiles {
+		if err := json.Unmarshal(file, &pools); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal calico ip pools from %s", name)
+		}
+	}
+	blocks := calicoIPAMBlockList{}
+	for name, file := range blockFiles {
+		if err := json.Unmarshal(file, &blocks); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal calico ipam blocks from %s", name)
+		}
+	}
+
+	usage := []calicoIPPoolUsage{}
+	for _, pool := range pools.Items {
+		if pool.Spec.Disabled {
+			continue
+		}
+		_, poolNet, err := net.ParseCIDR(pool.Spec.CIDR)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse cidr of ip pool %s", pool.Metadata.Name)
+		}
+		ones, bits := poolNet.Mask.Size()
+
+		blockSize := pool.Spec.BlockSize
+		if blockSize == 0 {
+			blockSize = calicoDefaultBlockSize(bits)
+		}
+
+		poolUsage := calicoIPPoolUsage{
+			Name: pool.Metadata.Name,
+			CIDR: pool.Spec.CIDR,
+			Size: math.Ldexp(1, bits-ones),
+		}
+		if blockSize >= ones {
+			poolUsage.Blocks = math.Ldexp(1, blockSize-ones)
+		}
+		for _, block := range blocks.Items {
+			blockIP, _, err := net.ParseCIDR(block.Spec.CIDR)
+			if err != nil || !poolNet.Contains(blockIP) {
+				continue
+			}
+			if block.Spec.Affinity != nil && *block.Spec.Affinity != "" {
+				poolUsage.AffineBlocks++
+			}
+			for _, allocation := range block.Spec.Allocations {
+				if allocation != nil {
+					poolUsage.Active++
+				}
+			}
+		}
+		usage = append(usage, poolUsage)
+	}
+
+	return usage, nil
+}
+
+// calicoDefaultBlockSize is the block size of pools that do not set one, which is 26 for IPv4
+// pools and 122 for IPv6 pools
+func calicoDefaultBlockSize(bits int) int {
+	if bits == net.IPv6len*8 {
+		return 122
+	}
+	return 26
+}
+
+func analyzeCalicoIPAMPools(pools []calicoIPPoolUsage) []*AnalyzeResult {
+	results := []*AnalyzeResult{}
+	for _, pool := range pools {
+		if pool.Size > 0 && float64(pool.Active)/pool.Size >= 0.85 {
+			results = append(results, &AnalyzeResult{
+				Title:   "Available Pod IPs",
+				IsWarn:  true,
+				Message: fmt.Sprintf("%d of %.0f total available IPs in pool %s (%s) have been assigned", pool.Active, pool.Size, pool.Name, pool.CIDR),
+			})
+		}
+
+		if pool.Blocks > 0 && float64(pool.AffineBlocks)/pool.Blocks >= 0.85 {
+			results = append(results, &AnalyzeResult{
+				Title:   "Available Pod IPs",
+				IsWarn:  true,
+				Message: fmt.Sprintf("%d of %.0f blocks in pool %s (%s) have been claimed by nodes", pool.AffineBlocks, pool.Blocks, pool.Name, pool.CIDR),
+			})
+		}
+	}
+
+	return results
+}
+
+func analyzeCalicoFelix(nodes []collect.CalicoNodeStatus) []*AnalyzeResult {
+	results := []*AnalyzeResult{}
+	for _, node := range nodes {
+		if !node.FelixReady {
+			results = append(results, &AnalyzeResult{
+				Title:   "Calico Node Status",
+				IsWarn:  true,
+				Message: fmt.Sprintf("Felix is not ready on %s: %s", node.Node, node.FelixError),
+			})
+		}
+	}
+
+	return results
+}
+
+func analyzeCalicoBGPPeers(nodes []collect.CalicoNodeStatus) []*AnalyzeResult {
+	results := []*AnalyzeResult{}
+	for _, node := range nodes {
+		for _, peer := range node.BGPPeers {
+			if peer.IsEstablished() {
+				continue
+			}
+
+			state := peer.State
+			if peer.Info != "" {
+				state = fmt.Sprintf("%s (%s)", peer.State, peer.Info)
+			}
+			results = append(results, &AnalyzeResult{
+				Title:   "Calico BGP Peers",
+				IsWarn:  true,
+				Message: fmt.Sprintf("BGP session from %s to %s peer %s is %s", node.Node, peer.Type, peer.Address, state),
+			})
+		}
+	}
+
+	return results
+}
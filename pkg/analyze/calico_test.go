@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalico(t *testing.T) {
+	healthyNode := `{
+  "node": "node-1",
+  "namespace": "kube-system",
+  "pod": "calico-node-x7k2p",
+  "felixReady": true,
+  "bgpPeers": [
+    {"address": "172.18.0.4", "type": "node-to-node mesh", "state": "up", "since": "09:41:38", "info": "Established"}
+  ]
+}`
+	ipPools := `{
+  "apiVersion": "crd.projectcalico.org/v1",
+  "kind": "IPPoolList",
+  "items": [
+    {"metadata": {"name": "default-ipv4-ippool"}, "spec": {"cidr": "192.168.0.0/28", "blockSize": 29}},
+    {"metadata": {"name": "old-ippool"}, "spec": {"cidr": "10.0.0.0/30", "disabled": true}}
+  ]
+}`
+
+	tests := []struct {
+		name     string
+		analyzer troubleshootv1beta2.CalicoAnalyze
+		files    map[string]string
+		expect   []*AnalyzeResult
+	}{
+		{
+			name:     "no issues",
+			analyzer: troubleshootv1beta2.CalicoAnalyze{},
+			files: map[string]string{
+				"calico/nodes/node-1.json": healthyNode,
+				"calico/ippools.json":      ipPools,
+				"calico/ipamblocks.json": `{"items": [
+  {"spec": {"cidr": "192.168.0.0/29", "allocations": [0, 1, null, null, null, null, null, null]}},
+  {"spec": {"cidr": "192.168.0.8/29", "allocations": [0, null, null, null, null, null, null, null]}}
+]}`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Calico",
+					IsPass:  true,
+					Message: "No issues detected in calico status",
+				},
+			},
+		},
+		{
+			name:     "insufficient IPs",
+			analyzer: troubleshootv1beta2.CalicoAnalyze{},
+			files: map[string]string{
+				"calico/nodes/node-1.json": healthyNode,
+				"calico/ippools.json":      ipPools,
+				"calico/ipamblocks.json": `{"items": [
+  {"spec": {"cidr": "192.168.0.0/29", "allocations": [0, 1, 2, 3, 4, 5, 6, 7]}},
+  {"spec": {"cidr": "192.168.0.8/29", "allocations": [0, 1, 2, 3, 4, 5, null, null]}},
+  {"spec": {"cidr": "10.0.0.0/30", "allocations": [0, 1, 2, 3]}}
+]}`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Available Pod IPs",
+					IsWarn:  true,
+					Message: "14 of 16 total available IPs in pool default-ipv4-ippool (192.168.0.0/28) have been assigned",
+				},
+			},
+		},
+		{
+			name:     "blocks claimed by nodes",
+			analyzer: troubleshootv1beta2.CalicoAnalyze{},
+			files: map[string]string{
+				"calico/nodes/node-1.json": healthyNode,
+				"calico/ippools.json": `{"items": [
+  {"metadata": {"name": "default-ipv4-ippool"}, "spec": {"cidr": "192.168.0.0/24"}}
+]}`,
+				"calico/ipamblocks.json": `{"items": [
+  {"spec": {"cidr": "192.168.0.0/26", "affinity": "host:node-1", "allocations": [0, 1, null, null]}},
+  {"spec": {"cidr": "192.168.0.64/26", "affinity": "host:node-2", "allocations": [0, null, null, null]}},
+  {"spec": {"cidr": "192.168.0.128/26", "affinity": "host:node-3", "allocations": [0, null, null, null]}},
+  {"spec": {"cidr": "192.168.0.192/26", "affinity": "host:node-4", "allocations": [null, null, null, null]}}
+]}`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Available Pod IPs",
+					IsWarn:  true,
+					Message: "4 of 4 blocks in pool default-ipv4-ippool (192.168.0.0/24) have been claimed by nodes",
+				},
+			},
+		},
+		{
+			name:     "felix not ready and unreachable peers on every node",
+			analyzer: troubleshootv1beta2.CalicoAnalyze{CollectorName: "cni"},
+			files: map[string]string{
+				"cni/calico/nodes/node-1.json": healthyNode,
+				"cni/calico/nodes/node-2.json": `{
+  "node": "node-2",
+  "namespace": "kube-system",
+  "pod": "calico-node-b9d4q",
+  "felixReady": false,
+  "felixError": "calico/node is not ready: felix is not ready: readiness probe reporting 503",
+  "bgpPeers": [
+    {"address": "172.18.0.3", "type": "node-to-node mesh", "state": "start", "since": "09:41:36", "info": "Active Socket: Connection refused"}
+  ]
+}`,
+				"cni/calico/nodes/node-3.json": `{
+  "node": "node-3",
+  "namespace": "kube-system",
+  "pod": "calico-node-m2c8z",
+  "felixReady": false,
+  "felixError": "calico/node is not ready: BIRD is not ready",
+  "bgpPeers": [
+    {"address": "172.18.0.3", "type": "node-to-node mesh", "state": "up", "since": "09:41:38", "info": "Established"},
+    {"address": "172.18.0.4", "type": "node-to-node mesh", "state": "start", "since": "09:41:36", "info": "Connect"}
+  ]
+}`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Calico Node Status",
+					IsWarn:  true,
+					Message: "Felix is not ready on node-2: calico/node is not ready: felix is not ready: readiness probe reporting 503",
+				},
+				{
+					Title:   "Calico Node Status",
+					IsWarn:  true,
+					Message: "Felix is not ready on node-3: calico/node is not ready: BIRD is not ready",
+				},
+				{
+					Title:   "Calico BGP Peers",
+					IsWarn:  true,
+					Message: "BGP session from node-2 to node-to-node mesh peer 172.18.0.3 is start (Active Socket: Connection refused)",
+				},
+				{
+					Title:   "Calico BGP Peers",
+					IsWarn:  true,
+					Message: "BGP session from node-3 to node-to-node mesh peer 172.18.0.4 is start (Connect)",
+				},
+			},
+		},
+		{
+			name:     "not collected",
+			analyzer: troubleshootv1beta2.CalicoAnalyze{},
+			files:    map[string]string{},
+			expect:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			findFiles := func(glob string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, contents := range test.files {
+					if ok, _ := filepath.Match(glob, name); ok {
+						matching[name] = []byte(contents)
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeCalico(&test.analyzer, findFiles)
+			req.NoError(err)
+
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
@@ -11,6 +11,26 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+type AnalyzeCephStatus struct {
+	analyzer *troubleshootv1beta2.CephStatusAnalyze
+}
+
+func (a *AnalyzeCephStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Ceph Status")
+}
+
+func (a *AnalyzeCephStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCephStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := cephStatus(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 type CephHealth string
 
 const (
@@ -0,0 +1,343 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type AnalyzeCilium struct {
+	analyzer *troubleshootv1beta2.CiliumAnalyze
+}
+
+func (a *AnalyzeCilium) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Cilium")
+}
+
+func (a *AnalyzeCilium) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCilium) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeCilium(a.analyzer, findFiles)
+}
+
+// relevant fields from the output of "cilium status --output json"
+type CiliumStatus struct {
+	Kvstore     *CiliumStatusState `json:"kvstore"`
+	Kubernetes  *CiliumStatusState `json:"kubernetes"`
+	Cilium      *CiliumStatusState `json:"cilium"`
+	IPAM        *CiliumIPAMStatus  `json:"ipam"`
+	Controllers []CiliumController `json:"controllers"`
+}
+
+type CiliumStatusState struct {
+	State string `json:"state"`
+	Msg   string `json:"msg"`
+}
+
+type CiliumIPAMStatus struct {
+	IPV4 []string `json:"ipv4"`
+}
+
+type CiliumController struct {
+	Name   string `json:"name"`
+	Status struct {
+		ConsecutiveFailureCount int    `json:"consecutive-failure-count"`
+		LastFailureMsg          string `json:"last-failure-msg"`
+	} `json:"status"`
+}
+
+// relevant fields from the output of "cilium-health status --output json"
+type CiliumHealth struct {
+	Nodes []CiliumHealthNode `json:"nodes"`
+}
+
+type CiliumHealthNode struct {
+	Name           string                `json:"name"`
+	Host           *CiliumHealthEndpoint `json:"host"`
+	HealthEndpoint *CiliumHealthEndpoint `json:"health-endpoint"`
+}
+
+type CiliumHealthEndpoint struct {
+	PrimaryAddress *CiliumHealthAddress `json:"primary-address"`
+}
+
+type CiliumHealthAddress struct {
+	IP   string `json:"ip"`
+	ICMP *struct {
+		Status string `json:"status"`
+	} `json:"icmp"`
+	HTTP *struct {
+		Status string `json:"status"`
+	} `json:"http"`
+}
+
+// relevant fields of the CiliumNode custom resource
+type ciliumNodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			IPAM struct {
+				PodCIDRs []string `json:"podCIDRs"`
+			} `json:"ipam"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// ciliumAgent is what was collected from the cilium agent on a node. Health is nil when
+// cilium-health could not be run.
+type ciliumAgent struct {
+	Node     string
+	Status   *CiliumStatus
+	Health   *CiliumHealth
+	PodCIDRs []string
+}
+
+func analyzeCilium(analyzer *troubleshootv1beta2.CiliumAnalyze, findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	pathPrefix := collect.GetCiliumCollectorFilepath(analyzer.CollectorName)
+
+	agents, err := getCiliumAgents(pathPrefix, findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(agents) == 0 {
+		return nil, nil
+	}
+
+	results := []*AnalyzeResult{}
+
+	if result := analyzeCiliumIPAM(agents); result != nil {
+		results = append(results, result)
+	}
+
+	if result := analyzeCiliumAgentStatus(agents); result != nil {
+		results = append(results, result)
+	}
+
+	if result := analyzeCiliumControllers(agents); result != nil {
+		results = append(results, result)
+	}
+
+	if result := analyzeCiliumConnectivity(agents); result != nil {
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		results = append(results, &AnalyzeResult{
+			Title:   analyzerTitleOrDefault(analyzer.AnalyzeMeta, "Cilium"),
+			IsPass:  true,
+			Message: "No issues detected in cilium status",
+		})
+	}
+
+	return results, nil
+}
+
+// getCiliumAgents reads the status and health of the cilium agents, which the collector saves in
+// <node>/status.json and <node>/health.json, and the pod CIDRs of their nodes.
+func getCiliumAgents(pathPrefix string, findFiles func(string) (map[string][]byte, error)) ([]*ciliumAgent, error) {
+	agents := map[string]*ciliumAgent{}
+	getAgent := func(name string) *ciliumAgent {
+		node := path.Base(path.Dir(name))
+		if _, ok := agents[node]; !ok {
+			agents[node] = &ciliumAgent{Node: node}
+		}
+		return agents[node]
+	}
+
+	statusFiles, err := findFiles(path.Join(pathPrefix, "*", "status.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cilium status files")
+	}
+	for name, file := range statusFiles {
+		status := &CiliumStatus{}
+		if err := json.Unmarshal(file, status); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal cilium status from %s", name)
+		}
+		getAgent(name).Status = status
+	}
+
+	healthFiles, err := findFiles(path.Join(pathPrefix, "*", "health.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cilium health files")
+	}
+	for name, file := range healthFiles {
+		health := &CiliumHealth{}
+		if err := json.Unmarshal(file, health); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal cilium health from %s", name)
+		}
+		getAgent(name).Health = health
+	}
+
+	nodeFiles, err := findFiles(path.Join(pathPrefix, "ciliumnodes.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cilium nodes")
+	}
+	for name, file := range nodeFiles {
+		ciliumNodes := ciliumNodeList{}
+		if err := json.Unmarshal(file, &ciliumNodes); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal cilium nodes from %s", name)
+		}
+		for _, ciliumNode := range ciliumNodes.Items {
+			if agent, ok := agents[ciliumNode.Metadata.Name]; ok {
+				agent.PodCIDRs = ciliumNode.Spec.IPAM.PodCIDRs
+			}
+		}
+	}
+
+	sorted := []*ciliumAgent{}
+	for _, agent := range agents {
+		sorted = append(sorted, agent)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Node < sorted[j].Node
+	})
+
+	return sorted, nil
+}
+
+func analyzeCiliumIPAM(agents []*ciliumAgent) *AnalyzeResult {
+	for _, agent := range agents {
+		if agent.Status == nil || agent.Status.IPAM == nil {
+			continue
+		}
+
+		// the network and broadcast addresses of the pod CIDRs are not allocated
+		available := 0
+		for _, podCIDR := range agent.PodCIDRs {
+			_, ipNet, err := net.ParseCIDR(podCIDR)
+			if err != nil || ipNet.IP.To4() == nil {
+				continue
+			}
+			ones, bits := ipNet.Mask.Size()
+			if bits-ones >= 2 {
+				available += 1<<(bits-ones) - 2
+			}
+		}
+		if available == 0 {
+			continue
+		}
+
+		active := len(agent.Status.IPAM.IPV4)
+		ipsUsed := float64(active) / float64(available)
+		if ipsUsed < 0.85 {
+			continue
+		}
+
+		return &AnalyzeResult{
+			Title:   "Available Pod IPs",
+			IsWarn:  true,
+			Message: fmt.Sprintf("%d of %d total available IPs on %s have been assigned", active, available, agent.Node),
+		}
+	}
+
+	return nil
+}
+
+func analyzeCiliumAgentStatus(agents []*ciliumAgent) *AnalyzeResult {
+	for _, agent := range agents {
+		if agent.Status == nil {
+			continue
+		}
+
+		for _, component := range []struct {
+			name  string
+			state *CiliumStatusState
+		}{
+			{name: "Cilium", state: agent.Status.Cilium},
+			{name: "Kubernetes", state: agent.Status.Kubernetes},
+			{name: "KVStore", state: agent.Status.Kvstore},
+		} {
+			if component.state == nil || component.state.State == "Ok" || component.state.State == "Disabled" {
+				continue
+			}
+
+			return &AnalyzeResult{
+				Title:   "Cilium Agent Status",
+				IsWarn:  true,
+				Message: fmt.Sprintf("%s status of the cilium agent on %s is %s: %s", component.name, agent.Node, component.state.State, component.state.Msg),
+			}
+		}
+	}
+
+	return nil
+}
+
+func analyzeCiliumControllers(agents []*ciliumAgent) *AnalyzeResult {
+	for _, agent := range agents {
+		if agent.Status == nil {
+			continue
+		}
+
+		for _, controller := range agent.Status.Controllers {
+			if controller.Status.ConsecutiveFailureCount == 0 {
+				continue
+			}
+
+			return &AnalyzeResult{
+				Title:   "Cilium Controllers",
+				IsWarn:  true,
+				Message: fmt.Sprintf("Controller %s of the cilium agent on %s has failed %d times: %s", controller.Name, agent.Node, controller.Status.ConsecutiveFailureCount, controller.Status.LastFailureMsg),
+			}
+		}
+	}
+
+	return nil
+}
+
+func analyzeCiliumConnectivity(agents []*ciliumAgent) *AnalyzeResult {
+	for _, agent := range agents {
+		if agent.Health == nil {
+			continue
+		}
+
+		for _, node := range agent.Health.Nodes {
+			// nodes are named <cluster>/<node>
+			peer := node.Name
+			if i := strings.LastIndex(peer, "/"); i >= 0 {
+				peer = peer[i+1:]
+			}
+
+			for _, endpoint := range []*CiliumHealthEndpoint{node.Host, node.HealthEndpoint} {
+				status := getCiliumHealthAddressStatus(endpoint)
+				if status == "" {
+					continue
+				}
+
+				return &AnalyzeResult{
+					Title:   "Cilium Inter-Node Connectivity",
+					IsWarn:  true,
+					Message: fmt.Sprintf("Connection from %s to %s (%s) failed: %s", agent.Node, peer, endpoint.PrimaryAddress.IP, status),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// getCiliumHealthAddressStatus returns why the address of an endpoint could not be reached, or
+// an empty string when it could be
+func getCiliumHealthAddressStatus(endpoint *CiliumHealthEndpoint) string {
+	if endpoint == nil || endpoint.PrimaryAddress == nil {
+		return ""
+	}
+	if icmp := endpoint.PrimaryAddress.ICMP; icmp != nil && icmp.Status != "" {
+		return icmp.Status
+	}
+	if http := endpoint.PrimaryAddress.HTTP; http != nil && http.Status != "" {
+		return http.Status
+	}
+	return ""
+}
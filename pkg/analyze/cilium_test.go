@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCilium(t *testing.T) {
+	healthyStatus := `{
+  "kvstore": {"state": "Ok", "msg": "Disabled"},
+  "kubernetes": {"state": "Ok", "msg": "1.24 (v1.24.0) [linux/amd64]"},
+  "cilium": {"state": "Ok", "msg": "Ok"},
+  "ipam": {"ipv4": ["10.0.1.10", "10.0.1.11", "10.0.1.12"]},
+  "controllers": [
+    {"name": "sync-to-k8s-ciliumendpoint (1203)", "status": {"consecutive-failure-count": 0, "success-count": 12}}
+  ]
+}`
+	healthyHealth := `{
+  "local": {"name": "kind/node-1"},
+  "nodes": [
+    {
+      "name": "kind/node-2",
+      "host": {"primary-address": {"ip": "172.18.0.3", "icmp": {"latency": 81230}, "http": {"latency": 413000}}},
+      "health-endpoint": {"primary-address": {"ip": "10.0.2.77", "icmp": {"latency": 99120}, "http": {"latency": 520000}}}
+    }
+  ]
+}`
+	ciliumNodes := `{
+  "apiVersion": "cilium.io/v2",
+  "kind": "CiliumNodeList",
+  "items": [
+    {"metadata": {"name": "node-1"}, "spec": {"ipam": {"podCIDRs": ["10.0.1.0/29"]}}},
+    {"metadata": {"name": "node-2"}, "spec": {"ipam": {"podCIDRs": ["10.0.2.0/24"]}}}
+  ]
+}`
+
+	tests := []struct {
+		name     string
+		analyzer troubleshootv1beta2.CiliumAnalyze
+		files    map[string]string
+		expect   []*AnalyzeResult
+	}{
+		{
+			name:     "no issues",
+			analyzer: troubleshootv1beta2.CiliumAnalyze{},
+			files: map[string]string{
+				"cilium/node-1/status.json": healthyStatus,
+				"cilium/node-1/health.json": healthyHealth,
+				"cilium/ciliumnodes.json":   ciliumNodes,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Cilium",
+					IsPass:  true,
+					Message: "No issues detected in cilium status",
+				},
+			},
+		},
+		{
+			name:     "insufficient IPs",
+			analyzer: troubleshootv1beta2.CiliumAnalyze{},
+			files: map[string]string{
+				"cilium/node-1/status.json": `{
+  "cilium": {"state": "Ok", "msg": "Ok"},
+  "ipam": {"ipv4": ["10.0.1.1", "10.0.1.2", "10.0.1.3", "10.0.1.4", "10.0.1.5", "10.0.1.6"]}
+}`,
+				"cilium/ciliumnodes.json": ciliumNodes,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Available Pod IPs",
+					IsWarn:  true,
+					Message: "6 of 6 total available IPs on node-1 have been assigned",
+				},
+			},
+		},
+		{
+			name:     "unhealthy agent and unreachable node",
+			analyzer: troubleshootv1beta2.CiliumAnalyze{CollectorName: "cni"},
+			files: map[string]string{
+				"cni/cilium/node-1/status.json": healthyStatus,
+				"cni/cilium/node-1/health.json": healthyHealth,
+				"cni/cilium/node-2/status.json": `{
+  "kvstore": {"state": "Ok", "msg": "Disabled"},
+  "kubernetes": {"state": "Failure", "msg": "Get \"https://10.96.0.1:443/version\": dial tcp 10.96.0.1:443: i/o timeout"},
+  "cilium": {"state": "Ok", "msg": "Ok"},
+  "controllers": [
+    {"name": "ipcache-inject-labels", "status": {"consecutive-failure-count": 14, "last-failure-msg": "k8s cache not fully synced"}}
+  ]
+}`,
+				"cni/cilium/node-2/health.json": `{
+  "local": {"name": "kind/node-2"},
+  "nodes": [
+    {
+      "name": "kind/node-1",
+      "host": {"primary-address": {"ip": "172.18.0.2", "icmp": {"status": "Connection timed out"}, "http": {"status": "Connection timed out"}}}
+    }
+  ]
+}`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "Cilium Agent Status",
+					IsWarn:  true,
+					Message: "Kubernetes status of the cilium agent on node-2 is Failure: Get \"https://10.96.0.1:443/version\": dial tcp 10.96.0.1:443: i/o timeout",
+				},
+				{
+					Title:   "Cilium Controllers",
+					IsWarn:  true,
+					Message: "Controller ipcache-inject-labels of the cilium agent on node-2 has failed 14 times: k8s cache not fully synced",
+				},
+				{
+					Title:   "Cilium Inter-Node Connectivity",
+					IsWarn:  true,
+					Message: "Connection from node-2 to node-1 (172.18.0.2) failed: Connection timed out",
+				},
+			},
+		},
+		{
+			name:     "not collected",
+			analyzer: troubleshootv1beta2.CiliumAnalyze{},
+			files:    map[string]string{},
+			expect:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			findFiles := func(glob string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, contents := range test.files {
+					if ok, _ := filepath.Match(glob, name); ok {
+						matching[name] = []byte(contents)
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeCilium(&test.analyzer, findFiles)
+			req.NoError(err)
+
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// clusterCapacityResources are always available in clusterCapacity `when` clauses, even when no
+// node or pod mentions them.
+var clusterCapacityResources = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	corev1.ResourceEphemeralStorage,
+}
+
+type AnalyzeClusterCapacity struct {
+	analyzer *troubleshootv1beta2.ClusterCapacity
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeClusterCapacity) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Cluster Capacity")
+}
+
+func (a *AnalyzeClusterCapacity) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeClusterCapacity) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeClusterCapacity) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeClusterCapacity(a.analyzer, a.Title(), getFile, findFiles, a.objects)
+}
+
+// clusterCapacity is the allocatable capacity of one or more nodes and what the pods scheduled on
+// them request and are limited to.
+type clusterCapacity struct {
+	name        string
+	nodes       int
+	pods        int
+	allocatable corev1.ResourceList
+	requests    corev1.ResourceList
+	limits      corev1.ResourceList
+}
+
+func newClusterCapacity(name string) *clusterCapacity {
+	return &clusterCapacity{
+		name:        name,
+		allocatable: corev1.ResourceList{},
+		requests:    corev1.ResourceList{},
+		limits:      corev1.ResourceList{},
+	}
+}
+
+// vars returns the variables available in `when` clauses and message templates, e.g.
+// available.cpu, requests.memory or requestsPercent.cpu. Quantities are pointers so that they
+// are printed as quantities in messages.
+func (c *clusterCapacity) vars() map[string]interface{} {
+	allocatable := map[string]interface{}{}
+	requests := map[string]interface{}{}
+	limits := map[string]interface{}{}
+	available := map[string]interface{}{}
+	requestsPercent := map[string]interface{}{}
+	limitsPercent := map[string]interface{}{}
+
+	names := map[corev1.ResourceName]bool{}
+	for _, name := range clusterCapacityResources {
+		names[name] = true
+	}
+	for _, list := range []corev1.ResourceList{c.allocatable, c.requests, c.limits} {
+		for name := range list {
+			names[name] = true
+		}
+	}
+
+	for name := range names {
+		if name == corev1.ResourcePods {
+			continue
+		}
+		allocatableQuantity := c.allocatable[name]
+		requestsQuantity := c.requests[name]
+		limitsQuantity := c.limits[name]
+		availableQuantity := allocatableQuantity.DeepCopy()
+		availableQuantity.Sub(requestsQuantity)
+
+		allocatable[string(name)] = &allocatableQuantity
+		requests[string(name)] = &requestsQuantity
+		limits[string(name)] = &limitsQuantity
+		available[string(name)] = &availableQuantity
+		requestsPercent[string(name)] = capacityPercent(requestsQuantity, allocatableQuantity)
+		limitsPercent[string(name)] = capacityPercent(limitsQuantity, allocatableQuantity)
+	}
+
+	allocatablePods := c.allocatable[corev1.ResourcePods]
+	allocatable["pods"] = allocatablePods.Value()
+	available["pods"] = allocatablePods.Value() - int64(c.pods)
+
+	return map[string]interface{}{
+		"name":            c.name,
+		"nodes":           c.nodes,
+		"pods":            c.pods,
+		"allocatable":     allocatable,
+		"requests":        requests,
+		"limits":          limits,
+		"available":       available,
+		"requestsPercent": requestsPercent,
+		"limitsPercent":   limitsPercent,
+	}
+}
+
+// maxRequestsPercent returns the highest percentage of allocatable cpu or memory that is requested.
+func (c *clusterCapacity) maxRequestsPercent() (corev1.ResourceName, float64) {
+	cpu := capacityPercent(c.requests[corev1.ResourceCPU], c.allocatable[corev1.ResourceCPU])
+	memory := capacityPercent(c.requests[corev1.ResourceMemory], c.allocatable[corev1.ResourceMemory])
+	if memory > cpu {
+		return corev1.ResourceMemory, memory
+	}
+	return corev1.ResourceCPU, cpu
+}
+
+func capacityPercent(used resource.Quantity, allocatable resource.Quantity) float64 {
+	if allocatable.IsZero() {
+		return 0
+	}
+	return used.AsApproximateFloat64() / allocatable.AsApproximateFloat64() * 100
+}
+
+func analyzeClusterCapacity(analyzer *troubleshootv1beta2.ClusterCapacity, title string, getCollectedFileContents func(string) ([]byte, error), getChildCollectedFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	// pods in namespaces that were not collected are missing from the bundle, so their requests
+	// can't be accounted for
+	pods, err := readCollectedPods(objects, nil, getChildCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := newClusterCapacity("")
+	nodeCapacities := []*clusterCapacity{}
+	byNodeName := map[string]*clusterCapacity{}
+	for _, node := range nodes.Items {
+		if !labelSelector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		nodeCapacity := newClusterCapacity(node.Name)
+		nodeCapacity.nodes = 1
+		addResourceList(nodeCapacity.allocatable, node.Status.Allocatable)
+		addResourceList(cluster.allocatable, node.Status.Allocatable)
+		cluster.nodes++
+
+		nodeCapacities = append(nodeCapacities, nodeCapacity)
+		byNodeName[node.Name] = nodeCapacity
+	}
+
+	for _, pod := range pods {
+		// pods that are done no longer hold on to their requests
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		nodeCapacity, ok := byNodeName[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+
+		requests, limits := podRequestsAndLimits(&pod)
+		for _, c := range []*clusterCapacity{nodeCapacity, cluster} {
+			addResourceList(c.requests, requests)
+			addResourceList(c.limits, limits)
+			c.pods++
+		}
+	}
+
+	if !analyzer.PerNode {
+		result, err := clusterCapacityResult(analyzer.Outcomes, title, cluster)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process cluster capacity")
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, nodeCapacity := range nodeCapacities {
+		result, err := clusterCapacityResult(analyzer.Outcomes, fmt.Sprintf("Node %s Capacity", nodeCapacity.name), nodeCapacity)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process node capacity")
+		}
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       nodeCapacity.name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// podRequestsAndLimits returns what the scheduler reserves for the pod: the larger of the sum of its
+// containers and its largest init container, plus the pod overhead. Resources without a limit in
+// any container are not counted towards the limits.
+func podRequestsAndLimits(pod *corev1.Pod) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResourceList(requests, container.Resources.Requests)
+		addResourceList(limits, container.Resources.Limits)
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		maxResourceList(requests, container.Resources.Requests)
+		maxResourceList(limits, container.Resources.Limits)
+	}
+
+	addResourceList(requests, pod.Spec.Overhead)
+	addResourceList(limits, pod.Spec.Overhead)
+
+	return requests, limits
+}
+
+func addResourceList(list corev1.ResourceList, add corev1.ResourceList) {
+	for name, quantity := range add {
+		value, ok := list[name]
+		if !ok {
+			list[name] = quantity.DeepCopy()
+			continue
+		}
+		value.Add(quantity)
+		list[name] = value
+	}
+}
+
+func maxResourceList(list corev1.ResourceList, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if value, ok := list[name]; !ok || quantity.Cmp(value) > 0 {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func clusterCapacityResult(outcomes []*troubleshootv1beta2.Outcome, title string, capacity *clusterCapacity) (*AnalyzeResult, error) {
+	if len(outcomes) == 0 {
+		return getDefaultClusterCapacityResult(title, capacity), nil
+	}
+
+	vars := capacity.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, title, "kubernetes_node_resources", "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18", compareWhen, vars)
+}
+
+func getDefaultClusterCapacityResult(title string, capacity *clusterCapacity) *AnalyzeResult {
+	resourceName, percent := capacity.maxRequestsPercent()
+	if percent < 90 {
+		return nil
+	}
+
+	requests := capacity.requests[resourceName]
+	allocatable := capacity.allocatable[resourceName]
+
+	subject := "The cluster"
+	if capacity.name != "" {
+		subject = fmt.Sprintf("Node %s", capacity.name)
+	}
+
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}
+	if percent >= 100 {
+		result.IsFail = true
+		result.Message = fmt.Sprintf("%s has no %s left to request, %s of %s allocatable is requested", subject, resourceName, requests.String(), allocatable.String())
+	} else {
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("%s has %.0f%% of allocatable %s requested, %s of %s", subject, percent, resourceName, requests.String(), allocatable.String())
+	}
+	return result
+}
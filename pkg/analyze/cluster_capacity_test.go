@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeClusterCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ClusterCapacity
+		expectResult []*AnalyzeResult
+	}{
+		{
+			name: "headroom across the cluster",
+			analyzer: troubleshootv1beta2.ClusterCapacity{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "available.cpu < 4 || available.memory < 8Gi",
+							Message: "Only {{ .available.cpu }} cpu and {{ .available.memory }} memory can be requested",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .available.cpu }} cpu can be requested on {{ .nodes }} nodes",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "Cluster Capacity",
+					Message: "Only 3400m cpu and 10Gi memory can be requested",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "overcommitted limits per node",
+			analyzer: troubleshootv1beta2.ClusterCapacity{
+				PerNode: true,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "limitsPercent.memory > 100 || available.pods < 5",
+							Message: "{{ .name }} is overcommitted with {{ .pods }} pods",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "Node node-1 Capacity",
+					Message:        "node-1 is overcommitted with 2 pods",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "node-1"},
+				},
+			},
+		},
+		{
+			name: "percentage of requested cpu or memory",
+			analyzer: troubleshootv1beta2.ClusterCapacity{
+				Selector: []string{"node-role.kubernetes.io/worker"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "requestsPercent.cpu > 80% || requestsPercent.memory > 80%",
+							Message: "workers are {{ .requestsPercent.cpu }}% requested",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "Cluster Capacity",
+					Message: "workers are 90% requested",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "default results",
+			analyzer: troubleshootv1beta2.ClusterCapacity{
+				PerNode: true,
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "Node node-1 Capacity",
+					Message:        "Node node-1 has 90% of allocatable cpu requested, 3600m of 4",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: "node-1"},
+				},
+			},
+		},
+	}
+
+	files := map[string][]byte{
+		"cluster-resources/nodes.json":        []byte(capacityNodes),
+		"cluster-resources/pods/default.json": []byte(capacityPods),
+	}
+	getFile := func(n string) ([]byte, error) {
+		return files[n], nil
+	}
+	getFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			a := AnalyzeClusterCapacity{analyzer: &test.analyzer}
+			actual, err := analyzeClusterCapacity(&test.analyzer, a.Title(), getFile, getFiles, nil)
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var capacityNodes = `{
+  "kind": "NodeList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "node-1", "labels": {"node-role.kubernetes.io/worker": ""}},
+      "status": {"allocatable": {"cpu": "4", "memory": "8Gi", "pods": "6"}}
+    },
+    {
+      "metadata": {"name": "node-2"},
+      "status": {"allocatable": {"cpu": "4", "memory": "8Gi", "pods": "110"}}
+    }
+  ]
+}`
+
+var capacityPods = `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "api-0", "namespace": "default"},
+      "spec": {
+        "nodeName": "node-1",
+        "initContainers": [
+          {"name": "migrate", "resources": {"requests": {"cpu": "2", "memory": "1Gi"}}}
+        ],
+        "containers": [
+          {"name": "api", "resources": {"requests": {"cpu": "1", "memory": "2Gi"}, "limits": {"memory": "6Gi"}}},
+          {"name": "proxy", "resources": {"requests": {"cpu": "500m", "memory": "1Gi"}, "limits": {"memory": "1Gi"}}}
+        ]
+      },
+      "status": {"phase": "Running"}
+    },
+    {
+      "metadata": {"name": "worker-0", "namespace": "default"},
+      "spec": {
+        "nodeName": "node-1",
+        "containers": [
+          {"name": "worker", "resources": {"requests": {"cpu": "1600m", "memory": "1Gi"}, "limits": {"memory": "2Gi"}}}
+        ]
+      },
+      "status": {"phase": "Running"}
+    },
+    {
+      "metadata": {"name": "job-0", "namespace": "default"},
+      "spec": {
+        "nodeName": "node-2",
+        "containers": [
+          {"name": "job", "resources": {"requests": {"cpu": "4", "memory": "8Gi"}}}
+        ]
+      },
+      "status": {"phase": "Succeeded"}
+    },
+    {
+      "metadata": {"name": "db-0", "namespace": "default"},
+      "spec": {
+        "nodeName": "node-2",
+        "containers": [
+          {"name": "db", "resources": {"requests": {"cpu": "1", "memory": "2Gi"}}}
+        ]
+      },
+      "status": {"phase": "Running"}
+    },
+    {
+      "metadata": {"name": "pending-0", "namespace": "default"},
+      "spec": {
+        "containers": [
+          {"name": "pending", "resources": {"requests": {"cpu": "8"}}}
+        ]
+      },
+      "status": {"phase": "Pending"}
+    }
+  ]
+}`
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"text/template"
 
@@ -14,34 +15,31 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func clusterPodStatuses(analyzer *troubleshootv1beta2.ClusterPodStatuses, getChildCollectedFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
-	collected, err := getChildCollectedFileContents(filepath.Join("cluster-resources", "pods", "*.json"))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read collected pods")
-	}
+type AnalyzeClusterPodStatuses struct {
+	analyzer *troubleshootv1beta2.ClusterPodStatuses
+	objects  *collectedFileCache
+}
 
-	var pods []corev1.Pod
-	for fileName, fileContent := range collected {
-		podsNs := strings.TrimSuffix(filepath.Base(fileName), ".json")
-		include := len(analyzer.Namespaces) == 0
-		for _, ns := range analyzer.Namespaces {
-			if ns == podsNs {
-				include = true
-				break
-			}
-		}
-		if include {
-			var nsPods corev1.PodList
-			if err := json.Unmarshal(fileContent, &nsPods); err != nil {
-				var nsPodsArr []corev1.Pod
-				if err := json.Unmarshal(fileContent, &nsPodsArr); err != nil {
-					return nil, errors.Wrapf(err, "failed to unmarshal pods list for namespace %s", podsNs)
-				}
-				pods = append(pods, nsPodsArr...)
-			} else {
-				pods = append(pods, nsPods.Items...)
-			}
-		}
+func (a *AnalyzeClusterPodStatuses) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Cluster Pod Status")
+}
+
+func (a *AnalyzeClusterPodStatuses) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeClusterPodStatuses) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeClusterPodStatuses) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return clusterPodStatuses(a.analyzer, findFiles, a.objects)
+}
+
+func clusterPodStatuses(analyzer *troubleshootv1beta2.ClusterPodStatuses, getChildCollectedFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	pods, err := readCollectedPods(objects, analyzer.Namespaces, getChildCollectedFileContents)
+	if err != nil {
+		return nil, err
 	}
 
 	allResults := []*AnalyzeResult{}
@@ -154,3 +152,46 @@ func clusterPodStatuses(analyzer *troubleshootv1beta2.ClusterPodStatuses, getChi
 
 	return allResults, nil
 }
+
+// readCollectedPods returns the collected pods in the given namespaces, or in all namespaces if none
+// are given. The pods of each namespace are parsed once per objects cache.
+func readCollectedPods(objects *collectedFileCache, namespaces []string, getChildCollectedFileContents func(string) (map[string][]byte, error)) ([]corev1.Pod, error) {
+	collected, err := getChildCollectedFileContents(filepath.Join("cluster-resources", "pods", "*.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pods")
+	}
+
+	var pods []corev1.Pod
+	for fileName, fileContent := range collected {
+		podsNs := strings.TrimSuffix(filepath.Base(fileName), ".json")
+		include := len(namespaces) == 0
+		for _, ns := range namespaces {
+			if ns == podsNs {
+				include = true
+				break
+			}
+		}
+		if !include {
+			continue
+		}
+
+		fileContent := fileContent
+		nsPods, err := objects.getObject(fileName, reflect.TypeOf([]corev1.Pod{}), func() (interface{}, error) {
+			var nsPods corev1.PodList
+			if err := json.Unmarshal(fileContent, &nsPods); err != nil {
+				var nsPodsArr []corev1.Pod
+				if err := json.Unmarshal(fileContent, &nsPodsArr); err != nil {
+					return nil, errors.Wrapf(err, "failed to unmarshal pods list for namespace %s", podsNs)
+				}
+				return nsPodsArr, nil
+			}
+			return nsPods.Items, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, nsPods.([]corev1.Pod)...)
+	}
+
+	return pods, nil
+}
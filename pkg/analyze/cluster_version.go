@@ -10,6 +10,26 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+type AnalyzeClusterVersion struct {
+	analyzer *troubleshootv1beta2.ClusterVersion
+}
+
+func (a *AnalyzeClusterVersion) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Required Kubernetes Version")
+}
+
+func (a *AnalyzeClusterVersion) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeClusterVersion) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeClusterVersion(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeClusterVersion(analyzer *troubleshootv1beta2.ClusterVersion, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	clusterInfo, err := getCollectedFileContents("cluster-info/cluster_version.json")
 	if err != nil {
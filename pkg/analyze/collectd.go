@@ -0,0 +1,586 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/expression"
+	"github.com/replicatedhq/troubleshoot/pkg/rrd"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// the collectd collector copies the rrd directory of every node to collectd/rrd/<node name>, and
+// collectd writes a file per plugin instance and type, e.g. <hostname>/cpu-0/cpu-idle.rrd
+const collectdRRDPath = "collectd/rrd/"
+
+var collectdRRDGlobs = []string{
+	"collectd/rrd/*/*/*/*.rrd",
+	"collectd/rrd/*/*/*/*/*.rrd",
+}
+
+// defaultCollectdWindow is the window that results are reported for when there are no outcomes
+const defaultCollectdWindow = "24h"
+
+// errCollectdMetricMissing is returned when an outcome refers to a metric that was not collected
+// for a host, in which case the outcome does not match.
+var errCollectdMetricMissing = errors.New("metric was not collected")
+
+type AnalyzeCollectd struct {
+	analyzer *troubleshootv1beta2.CollectdAnalyze
+}
+
+func (a *AnalyzeCollectd) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Collectd")
+}
+
+func (a *AnalyzeCollectd) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCollectd) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeCollectd(a.analyzer, findFiles)
+}
+
+// collectdHost is the RRD files collected for a collectd host, keyed by plugin instance and type,
+// e.g. "cpu-0/cpu-idle".
+type collectdHost struct {
+	Node  string
+	Name  string
+	Files map[string]*rrd.File
+}
+
+// collectdStats are the statistics of a metric over a window. Percentiles use the nearest rank
+// method, and Last is the most recent value.
+type collectdStats struct {
+	Avg  float64
+	Min  float64
+	Max  float64
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+	Last float64
+}
+
+func (s *collectdStats) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"avg":  s.Avg,
+		"min":  s.Min,
+		"max":  s.Max,
+		"p50":  s.P50,
+		"p90":  s.P90,
+		"p95":  s.P95,
+		"p99":  s.P99,
+		"last": s.Last,
+	}
+}
+
+// collectdState is what outcomes of the collectd analyzer are evaluated against, e.g. cpu.p95 or
+// disk.last, and what their messages are templated with, for every host and window. CPU, Memory and
+// Disk are percentages, where Disk is the fullest filesystem, and Load is the one minute load
+// average. Metrics that were not collected for the host are nil.
+type collectdState struct {
+	Node   string
+	Host   string
+	Window string
+	CPU    *collectdStats
+	Load   *collectdStats
+	Memory *collectdStats
+	Disk   *collectdStats
+}
+
+func (s collectdState) vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"node": s.Node,
+		"host": s.Host,
+	}
+	for metric, stats := range s.metrics() {
+		if stats != nil {
+			vars[metric] = stats.vars()
+		}
+	}
+	return vars
+}
+
+func (s collectdState) metrics() map[string]*collectdStats {
+	return map[string]*collectdStats{
+		"cpu":    s.CPU,
+		"load":   s.Load,
+		"memory": s.Memory,
+		"disk":   s.Disk,
+	}
+}
+
+func analyzeCollectd(analyzer *troubleshootv1beta2.CollectdAnalyze, findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	hosts, err := getCollectdHosts(findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hosts) == 0 {
+		return []*AnalyzeResult{
+			{
+				Title:   analyzerTitleOrDefault(analyzer.AnalyzeMeta, "Collectd"),
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				IsWarn:  true,
+				Message: "No collectd data was collected",
+			},
+		}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, host := range hosts {
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = collectdStatus(analyzer.Outcomes, host)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to analyze collectd data of host %s", host.Name)
+			}
+		} else {
+			result, err = getDefaultCollectdResult(host)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to analyze collectd data of host %s", host.Name)
+			}
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       host.Node,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func getCollectdHosts(findFiles func(string) (map[string][]byte, error)) ([]*collectdHost, error) {
+	hostsByKey := map[string]*collectdHost{}
+	for _, glob := range collectdRRDGlobs {
+		files, err := findFiles(glob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find files matching %s", glob)
+		}
+
+		for name, contents := range files {
+			// file names may be prefixed with the bundle directory
+			name = filepath.ToSlash(name)
+			index := strings.Index(name, collectdRRDPath)
+			if index < 0 {
+				continue
+			}
+			parts := strings.Split(name[index+len(collectdRRDPath):], "/")
+			if len(parts) < 4 {
+				continue
+			}
+
+			node := parts[0]
+			hostname := parts[len(parts)-3]
+			key := strings.Join([]string{parts[len(parts)-2], strings.TrimSuffix(parts[len(parts)-1], ".rrd")}, "/")
+
+			f, err := rrd.Parse(contents)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s", name)
+			}
+
+			hostKey := node + "/" + hostname
+			if _, ok := hostsByKey[hostKey]; !ok {
+				hostsByKey[hostKey] = &collectdHost{
+					Node:  node,
+					Name:  hostname,
+					Files: map[string]*rrd.File{},
+				}
+			}
+			hostsByKey[hostKey].Files[key] = f
+		}
+	}
+
+	hosts := []*collectdHost{}
+	for _, host := range hostsByKey {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Node != hosts[j].Node {
+			return hosts[i].Node < hosts[j].Node
+		}
+		return hosts[i].Name < hosts[j].Name
+	})
+
+	return hosts, nil
+}
+
+// parseCollectdWhen splits the window off a when clause, e.g. "cpu.p95 > 90 over 24h". Windows
+// are durations, and may also be given in days or weeks, e.g. "7d".
+func parseCollectdWhen(when string) (string, string, time.Duration, error) {
+	index := strings.LastIndex(when, " over ")
+	if index < 0 {
+		return strings.TrimSpace(when), "", 0, nil
+	}
+
+	window := strings.TrimSpace(when[index+len(" over "):])
+	duration, err := parseCollectdWindow(window)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return strings.TrimSpace(when[:index]), window, duration, nil
+}
+
+func parseCollectdWindow(window string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(window, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(window, suffix), 64)
+			if err != nil || n <= 0 {
+				return 0, errors.Errorf("invalid window %q", window)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return 0, errors.Errorf("invalid window %q", window)
+	}
+	return duration, nil
+}
+
+func collectdStatus(outcomes []*troubleshootv1beta2.Outcome, host *collectdHost) (*AnalyzeResult, error) {
+	// outcomes often share a window, so the statistics are only computed once per window
+	states := map[string]collectdState{}
+
+	// messages are templated with the statistics of the window of the matching outcome, so every
+	// outcome is compared, including those that would always match
+	outcomes = withDefaultWhen(outcomes, func(*troubleshootv1beta2.Outcome) string {
+		return "true"
+	})
+
+	current := &collectdState{}
+	compareWhen := func(when string) (bool, error) {
+		expr, window, duration, err := parseCollectdWhen(when)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to parse window")
+		}
+
+		state, ok := states[window]
+		if !ok {
+			state = getCollectdState(host, window, duration)
+			states[window] = state
+		}
+		*current = state
+
+		return compareCollectdToWhen(expr, state)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("Host %s Resource Usage", host.Name), "kubernetes_node_resources", "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18", compareWhen, current)
+}
+
+// compareCollectdToWhen evaluates an expression such as "cpu.p95 > 90 || memory.max >= 95" against
+// the statistics of a host. Expressions that refer to a metric that was not collected for the host
+// do not match.
+func compareCollectdToWhen(expr string, state collectdState) (bool, error) {
+	metrics := state.metrics()
+	resolveVar := expression.MapResolver(state.vars())
+	resolve := func(name string) (interface{}, bool, error) {
+		if stats, ok := metrics[strings.SplitN(name, ".", 2)[0]]; ok && stats == nil {
+			return nil, false, errCollectdMetricMissing
+		}
+		return resolveVar(name)
+	}
+
+	match, err := expression.Evaluate(expr, resolve)
+	if errors.Cause(err) == errCollectdMetricMissing {
+		return false, nil
+	}
+	return match, err
+}
+
+func getDefaultCollectdResult(host *collectdHost) (*AnalyzeResult, error) {
+	duration, err := parseCollectdWindow(defaultCollectdWindow)
+	if err != nil {
+		return nil, err
+	}
+	state := getCollectdState(host, defaultCollectdWindow, duration)
+
+	messages := []string{}
+	if state.CPU != nil && state.CPU.P95 >= 90 {
+		messages = append(messages, fmt.Sprintf("CPU usage was at least %.1f%% for 5%% of the last %s", state.CPU.P95, state.Window))
+	}
+	if state.Memory != nil && state.Memory.P95 >= 90 {
+		messages = append(messages, fmt.Sprintf("memory usage was at least %.1f%% for 5%% of the last %s", state.Memory.P95, state.Window))
+	}
+	if state.Disk != nil && state.Disk.Last >= 90 {
+		messages = append(messages, fmt.Sprintf("the fullest filesystem is %.1f%% full", state.Disk.Last))
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return &AnalyzeResult{
+		Title:   fmt.Sprintf("Host %s Resource Usage", host.Name),
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+		IsWarn:  true,
+		Message: fmt.Sprintf("On host %s, %s", host.Name, strings.Join(messages, ", ")),
+	}, nil
+}
+
+func getCollectdState(host *collectdHost, window string, duration time.Duration) collectdState {
+	return collectdState{
+		Node:   host.Node,
+		Host:   host.Name,
+		Window: window,
+		CPU:    getCollectdStats(collectdCPUUsage(host, duration)),
+		Load:   getCollectdStats(collectdSeriesOf(host.Files["load/load"], "shortterm", duration)),
+		Memory: getCollectdStats(collectdMemoryUsage(host, duration)),
+		Disk:   getCollectdStats(collectdDiskUsage(host, duration)),
+	}
+}
+
+// collectdSeries are the values of a metric by unix time
+type collectdSeries map[int64]float64
+
+// collectdSeriesOf returns the values of a data source over a window that ends with the last update
+// of the file, or all values when duration is 0. Values are read from the finest AVERAGE archive
+// that covers the window, or the longest one if none does. Unknown values are skipped.
+func collectdSeriesOf(f *rrd.File, dataSource string, duration time.Duration) collectdSeries {
+	if f == nil {
+		return nil
+	}
+	ds := f.DataSourceIndex(dataSource)
+	if ds < 0 {
+		return nil
+	}
+
+	var archive *rrd.Archive
+	for i := range f.Archives {
+		candidate := &f.Archives[i]
+		if candidate.ConsolidationFunction != "AVERAGE" {
+			continue
+		}
+		if archive == nil {
+			archive = candidate
+			continue
+		}
+		if duration == 0 {
+			if candidate.Step < archive.Step {
+				archive = candidate
+			}
+			continue
+		}
+
+		span, archiveSpan := collectdArchiveSpan(candidate), collectdArchiveSpan(archive)
+		switch {
+		case span >= duration && archiveSpan < duration:
+			archive = candidate
+		case span >= duration && candidate.Step < archive.Step:
+			archive = candidate
+		case archiveSpan < duration && span > archiveSpan:
+			archive = candidate
+		}
+	}
+	if archive == nil {
+		return nil
+	}
+
+	series := collectdSeries{}
+	for _, row := range archive.Rows {
+		if duration > 0 && !row.Time.After(f.LastUpdate.Add(-duration)) {
+			continue
+		}
+		if math.IsNaN(row.Values[ds]) {
+			continue
+		}
+		series[row.Time.Unix()] = row.Values[ds]
+	}
+	return series
+}
+
+func collectdArchiveSpan(archive *rrd.Archive) time.Duration {
+	return archive.Step * time.Duration(len(archive.Rows))
+}
+
+// sumCollectdSeries adds up series, at the times that all of them have a value
+func sumCollectdSeries(series ...collectdSeries) collectdSeries {
+	if len(series) == 0 {
+		return nil
+	}
+
+	sum := collectdSeries{}
+	for t := range series[0] {
+		total := 0.0
+		complete := true
+		for _, s := range series {
+			value, ok := s[t]
+			if !ok {
+				complete = false
+				break
+			}
+			total += value
+		}
+		if complete {
+			sum[t] = total
+		}
+	}
+	return sum
+}
+
+// collectdPercentage returns part as a percentage of total, at the times that both have a value
+func collectdPercentage(part collectdSeries, total collectdSeries) collectdSeries {
+	percentage := collectdSeries{}
+	for t, value := range part {
+		if totalValue, ok := total[t]; ok && totalValue > 0 {
+			percentage[t] = value / totalValue * 100
+		}
+	}
+	return percentage
+}
+
+// collectdCPUUsage returns the percentage of time the CPUs were busy, i.e. neither idle nor waiting
+// for IO. The cpu plugin reports either jiffies (cpu-<state>) or percentages (percent-<state>) per
+// CPU or for all CPUs.
+func collectdCPUUsage(host *collectdHost, duration time.Duration) collectdSeries {
+	all := []collectdSeries{}
+	idle := []collectdSeries{}
+	for key, f := range host.Files {
+		parts := strings.SplitN(key, "/", 2)
+		if parts[0] != "cpu" && !strings.HasPrefix(parts[0], "cpu-") {
+			continue
+		}
+
+		var state string
+		if strings.HasPrefix(parts[1], "cpu-") {
+			state = strings.TrimPrefix(parts[1], "cpu-")
+		} else if strings.HasPrefix(parts[1], "percent-") {
+			state = strings.TrimPrefix(parts[1], "percent-")
+		} else {
+			continue
+		}
+
+		series := collectdSeriesOf(f, "value", duration)
+		all = append(all, series)
+		if state == "idle" || state == "wait" {
+			idle = append(idle, series)
+		}
+	}
+	if len(all) == 0 || len(idle) == 0 {
+		return nil
+	}
+
+	total := sumCollectdSeries(all...)
+	idleTotal := sumCollectdSeries(idle...)
+	busy := collectdSeries{}
+	for t, value := range total {
+		if idleValue, ok := idleTotal[t]; ok {
+			busy[t] = value - idleValue
+		}
+	}
+	return collectdPercentage(busy, total)
+}
+
+// collectdMemoryUsage returns the percentage of memory used, i.e. not free, cached or buffers
+func collectdMemoryUsage(host *collectdHost, duration time.Duration) collectdSeries {
+	all := []collectdSeries{}
+	var used collectdSeries
+	for key, f := range host.Files {
+		if !strings.HasPrefix(key, "memory/memory-") {
+			continue
+		}
+		series := collectdSeriesOf(f, "value", duration)
+		all = append(all, series)
+		if key == "memory/memory-used" {
+			used = series
+		}
+	}
+	if used == nil {
+		return nil
+	}
+
+	return collectdPercentage(used, sumCollectdSeries(all...))
+}
+
+// collectdDiskUsage returns the percentage of space used on the fullest filesystem. The df plugin
+// reports either bytes (df_complex-<state>) or percentages (percent_bytes-<state>) per mount point.
+func collectdDiskUsage(host *collectdHost, duration time.Duration) collectdSeries {
+	mounts := map[string]map[string]*rrd.File{}
+	for key, f := range host.Files {
+		parts := strings.SplitN(key, "/", 2)
+		if !strings.HasPrefix(parts[0], "df-") {
+			continue
+		}
+		if _, ok := mounts[parts[0]]; !ok {
+			mounts[parts[0]] = map[string]*rrd.File{}
+		}
+		mounts[parts[0]][parts[1]] = f
+	}
+
+	usage := collectdSeries{}
+	for _, files := range mounts {
+		var used collectdSeries
+		if f, ok := files["percent_bytes-used"]; ok {
+			used = collectdSeriesOf(f, "value", duration)
+		} else if f, ok := files["df_complex-used"]; ok {
+			all := []collectdSeries{}
+			for _, state := range []string{"used", "free", "reserved"} {
+				if f, ok := files["df_complex-"+state]; ok {
+					all = append(all, collectdSeriesOf(f, "value", duration))
+				}
+			}
+			used = collectdPercentage(collectdSeriesOf(f, "value", duration), sumCollectdSeries(all...))
+		}
+
+		for t, value := range used {
+			if current, ok := usage[t]; !ok || value > current {
+				usage[t] = value
+			}
+		}
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+	return usage
+}
+
+func getCollectdStats(series collectdSeries) *collectdStats {
+	if len(series) == 0 {
+		return nil
+	}
+
+	times := []int64{}
+	values := []float64{}
+	sum := 0.0
+	for t, value := range series {
+		times = append(times, t)
+		values = append(values, value)
+		sum += value
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	sort.Float64s(values)
+
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(values))))
+		if rank < 1 {
+			rank = 1
+		}
+		return values[rank-1]
+	}
+
+	return &collectdStats{
+		Avg:  sum / float64(len(values)),
+		Min:  values[0],
+		Max:  values[len(values)-1],
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P95:  percentile(95),
+		P99:  percentile(99),
+		Last: series[times[len(times)-1]],
+	}
+}
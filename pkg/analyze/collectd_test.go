@@ -0,0 +1,277 @@
+package analyzer
+
+import (
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeCollectd(t *testing.T) {
+	nodeRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: name}
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.CollectdAnalyze
+		files        map[string][]byte
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "default results",
+			analyzer: troubleshootv1beta2.CollectdAnalyze{},
+			files:    collectdTestFiles(),
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "Host node-1 Resource Usage",
+					Message:        "On host node-1, CPU usage was at least 95.0% for 5% of the last 24h",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("node-1"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "Host node-2 Resource Usage",
+					Message:        "On host node-2, the fullest filesystem is 95.0% full",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("node-2"),
+				},
+			},
+		},
+		{
+			name: "windows and missing metrics",
+			analyzer: troubleshootv1beta2.CollectdAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "cpu.p95 > 90 over 2m",
+							Message: "CPU usage on {{ .Host }} averaged {{ .CPU.Avg }}% over the last {{ .Window }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "disk.last >= 90%",
+							Message: "{{ .Host }} is running out of disk space",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "Resource usage on {{ .Host }} is fine",
+						},
+					},
+				},
+			},
+			files: collectdTestFiles(),
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "Host node-1 Resource Usage",
+					Message:        "CPU usage on node-1 averaged 95% over the last 2m",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("node-1"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "Host node-2 Resource Usage",
+					Message:        "node-2 is running out of disk space",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("node-2"),
+				},
+			},
+		},
+		{
+			name: "expression over the whole archive",
+			analyzer: troubleshootv1beta2.CollectdAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "load.max >= 4 && memory.avg < 70 && cpu.p50 == 60",
+							Message: "{{ .Host }} peaked at a load of {{ .Load.Max }}",
+						},
+					},
+				},
+			},
+			files: collectdTestFiles(),
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "Host node-1 Resource Usage",
+					Message:        "node-1 peaked at a load of 4",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("node-1"),
+				},
+			},
+		},
+		{
+			name:     "no data",
+			analyzer: troubleshootv1beta2.CollectdAnalyze{},
+			files:    map[string][]byte{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "Collectd",
+					Message: "No collectd data was collected",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "invalid window",
+			analyzer: troubleshootv1beta2.CollectdAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "cpu.p95 > 90 over a while",
+							Message: "CPU usage is high",
+						},
+					},
+				},
+			},
+			files:   collectdTestFiles(),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			findFiles := func(glob string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, contents := range test.files {
+					if ok, _ := filepath.Match(glob, name); ok {
+						matching[name] = contents
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeCollectd(&test.analyzer, findFiles)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+func Test_parseCollectdWindow(t *testing.T) {
+	tests := []struct {
+		window   string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{window: "90m", expected: 90 * time.Minute},
+		{window: "1.5d", expected: 36 * time.Hour},
+		{window: "2w", expected: 14 * 24 * time.Hour},
+		{window: "0h", wantErr: true},
+		{window: "day", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.window, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := parseCollectdWindow(test.window)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+// collectdTestFiles returns ten minutes of data for two hosts, at the paths that the collectd
+// collector extracts the rrd files of each node to. node-1 has CPU, load and memory data, with CPU
+// usage at 60% that rises to 95% in the last two minutes. node-2 only has disk data, with one
+// filesystem that is 95% full.
+func collectdTestFiles() map[string][]byte {
+	values := func(first float64, last float64) []float64 {
+		return []float64{first, first, first, first, first, first, first, first, last, last}
+	}
+
+	return map[string][]byte{
+		"collectd/rrd/node-1/rrd/node-1/cpu-0/cpu-user.rrd":          collectdTestRRD("value", values(50, 85)),
+		"collectd/rrd/node-1/rrd/node-1/cpu-0/cpu-system.rrd":        collectdTestRRD("value", values(10, 10)),
+		"collectd/rrd/node-1/rrd/node-1/cpu-0/cpu-idle.rrd":          collectdTestRRD("value", values(35, 5)),
+		"collectd/rrd/node-1/rrd/node-1/cpu-0/cpu-wait.rrd":          collectdTestRRD("value", values(5, 0)),
+		"collectd/rrd/node-1/rrd/node-1/load/load.rrd":               collectdTestRRD("shortterm", values(1.5, 4)),
+		"collectd/rrd/node-1/rrd/node-1/memory/memory-used.rrd":      collectdTestRRD("value", values(6, 6)),
+		"collectd/rrd/node-1/rrd/node-1/memory/memory-free.rrd":      collectdTestRRD("value", values(2, 2)),
+		"collectd/rrd/node-1/rrd/node-1/memory/memory-cached.rrd":    collectdTestRRD("value", values(2, 2)),
+		"collectd/rrd/node-2/rrd/node-2/df-root/df_complex-used.rrd": collectdTestRRD("value", values(90, 95)),
+		"collectd/rrd/node-2/rrd/node-2/df-root/df_complex-free.rrd": collectdTestRRD("value", values(10, 5)),
+		"collectd/rrd/node-2/rrd/node-2/df-var/percent_bytes-used.rrd": collectdTestRRD("value",
+			[]float64{40, 40, 40, 40, 40, 40, 40, 40, math.NaN(), 40}),
+	}
+}
+
+// collectdTestRRD returns an RRD file with a single data source and a single AVERAGE archive of
+// one minute steps, laid out the way RRDtool writes them on 64 bit little endian platforms.
+func collectdTestRRD(dataSource string, values []float64) []byte {
+	b := []byte{}
+	putUint64 := func(v uint64) {
+		buf := make([]byte, 8)
+		binary.LittleEndian.PutUint64(buf, v)
+		b = append(b, buf...)
+	}
+	putString := func(s string, size int) {
+		buf := make([]byte, size)
+		copy(buf, s)
+		b = append(b, buf...)
+	}
+
+	// header
+	putString("RRD", 4)
+	putString("0003", 5)
+	b = append(b, make([]byte, 7)...)
+	putUint64(math.Float64bits(8.642135e130))
+	putUint64(1)  // data sources
+	putUint64(1)  // archives
+	putUint64(60) // step
+	b = append(b, make([]byte, 80)...)
+
+	// data source and archive definitions
+	putString(dataSource, 20)
+	putString("GAUGE", 20)
+	b = append(b, make([]byte, 80)...)
+	putString("AVERAGE", 24)
+	putUint64(uint64(len(values)))
+	putUint64(1)
+	b = append(b, make([]byte, 80)...)
+
+	// last update, pdp and cdp preparation areas, and the current row, which is the last one so
+	// that the values are stored in chronological order
+	putUint64(uint64(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC).Unix()))
+	putUint64(0)
+	b = append(b, make([]byte, 112+80)...)
+	putUint64(uint64(len(values) - 1))
+
+	for _, value := range values {
+		putUint64(math.Float64bits(value))
+	}
+
+	return b
+}
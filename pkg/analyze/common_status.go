@@ -15,6 +15,10 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, title, iconKey string
 		IconURI: iconURI,
 	}
 
+	vars := map[string]interface{}{
+		"ready": readyReplicas,
+	}
+
 	// ordering from the spec is important, the first one that matches returns
 	for _, outcome := range outcomes {
 		if outcome.Fail != nil {
@@ -26,7 +30,9 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, title, iconKey string
 				return result, nil
 			}
 
-			match, err := compareActualToWhen(outcome.Fail.When, readyReplicas)
+			match, err := evaluateWhen(outcome.Fail.When, nil, vars, func(when string) (bool, error) {
+				return compareActualToWhen(when, readyReplicas)
+			})
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse fail range")
 			}
@@ -47,7 +53,9 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, title, iconKey string
 				return result, nil
 			}
 
-			match, err := compareActualToWhen(outcome.Warn.When, readyReplicas)
+			match, err := evaluateWhen(outcome.Warn.When, nil, vars, func(when string) (bool, error) {
+				return compareActualToWhen(when, readyReplicas)
+			})
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse warn range")
 			}
@@ -68,7 +76,9 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, title, iconKey string
 				return result, nil
 			}
 
-			match, err := compareActualToWhen(outcome.Pass.When, readyReplicas)
+			match, err := evaluateWhen(outcome.Pass.When, nil, vars, func(when string) (bool, error) {
+				return compareActualToWhen(when, readyReplicas)
+			})
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse pass range")
 			}
@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	iutils "github.com/replicatedhq/troubleshoot/pkg/interfaceutils"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// getCompareFiles returns the contents of the collected file to compare, or of every collected file
+// that matches when the file name is a glob pattern, sorted by name.
+func getCompareFiles(collectorName string, fileName string, getCollectedFileContents func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]string, map[string][]byte, error) {
+	fullPath := filepath.Join(collectorName, fileName)
+
+	if !strings.ContainsAny(fileName, "*?[") {
+		collected, err := getCollectedFileContents(fullPath)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+		}
+		return []string{fullPath}, map[string][]byte{fullPath: collected}, nil
+	}
+
+	files, err := findFiles(fullPath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to find collected files matching %s", fullPath)
+	}
+	if len(files) == 0 {
+		return nil, nil, errors.Errorf("no collected files match %s", fullPath)
+	}
+
+	fileNames := []string{}
+	for name := range files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	return fileNames, files, nil
+}
+
+// selectCompareValues returns the values in a collected document that outcomes are compared to. A
+// JSONPath expression such as {.items[*].spec.replicas} can select any number of values, while a
+// dotted path selects exactly one. Without either, the document itself is the value.
+func selectCompareValues(document interface{}, path string, jsonPathExpression string) ([]interface{}, error) {
+	if jsonPathExpression != "" {
+		return findJSONPathValues(document, jsonPathExpression)
+	}
+
+	if path != "" {
+		value, err := iutils.GetAtPath(document, path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get object at path: %s", path)
+		}
+		return []interface{}{value}, nil
+	}
+
+	return []interface{}{document}, nil
+}
+
+// findJSONPathValues evaluates a JSONPath expression the way kubectl does, so the enclosing braces
+// and the leading dot are optional. Missing keys select nothing rather than being an error, so the
+// same expression can be used for documents that do not all have the same fields.
+func findJSONPathValues(document interface{}, expression string) ([]interface{}, error) {
+	if !strings.HasPrefix(expression, "{") {
+		if !strings.HasPrefix(expression, ".") && !strings.HasPrefix(expression, "$") {
+			expression = "." + expression
+		}
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+
+	parser := jsonpath.New("compare").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse jsonpath %s", expression)
+	}
+
+	results, err := parser.FindResults(document)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate jsonpath %s", expression)
+	}
+
+	values := []interface{}{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// compareValuesStatus returns the result of the first outcome that matches the values. "true" and
+// "false" outcomes are matched by whether all values are equal to the expected value, as are outcomes
+// without a when clause when there is an expected value: pass outcomes when they are equal, fail and
+// warn outcomes when they are not. Anything else is an expression, in which the selected values are
+// the variable values, e.g. "any values < 2", and a single selected value is also the variable value,
+// e.g. "value >= 1.10.0".
+func compareValuesStatus(outcomes []*troubleshootv1beta2.Outcome, title string, values []interface{}, expected interface{}, hasExpected bool) (*AnalyzeResult, error) {
+	equal := len(values) > 0
+	for _, value := range values {
+		if !reflect.DeepEqual(value, expected) {
+			equal = false
+			break
+		}
+	}
+
+	if hasExpected {
+		outcomes = withDefaultWhen(outcomes, func(outcome *troubleshootv1beta2.Outcome) string {
+			// default to passing when values are equal
+			return strconv.FormatBool(outcome.Fail == nil && outcome.Warn == nil)
+		})
+	}
+
+	vars := map[string]interface{}{
+		"values": values,
+		"count":  len(values),
+	}
+	if len(values) == 1 {
+		vars["value"] = values[0]
+	}
+	if hasExpected {
+		vars["expected"] = expected
+		vars["equal"] = equal
+	}
+
+	compareWhen := func(when string) (bool, error) {
+		if isEqual, err := strconv.ParseBool(strings.TrimSpace(when)); err == nil {
+			if !hasExpected {
+				return false, errors.Errorf("a value to compare to is required for when statement: %s", when)
+			}
+			return equal == isEqual, nil
+		}
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	result, err := evaluateOutcomes(outcomes, title, "kubernetes_text_analyze", "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg", compareWhen, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return result, nil
+	}
+
+	return &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_text_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+		IsFail:  true,
+		Message: "Invalid analyzer",
+	}, nil
+}
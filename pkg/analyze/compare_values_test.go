@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compareValuesStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		when        string
+		values      []interface{}
+		expected    interface{}
+		hasExpected bool
+		isPass      bool
+		isError     bool
+	}{
+		{
+			name:   "numbers",
+			when:   "all values >= 3",
+			values: []interface{}{float64(3), 5},
+			isPass: true,
+		},
+		{
+			name:   "every value has to match",
+			when:   "all values > 3",
+			values: []interface{}{float64(3), float64(5)},
+			isPass: false,
+		},
+		{
+			name:   "any value can match",
+			when:   "any values > 3",
+			values: []interface{}{float64(3), float64(5)},
+			isPass: true,
+		},
+		{
+			name:   "quantities",
+			when:   "all values < 2Gi",
+			values: []interface{}{"1536Mi", "500M"},
+			isPass: true,
+		},
+		{
+			name:   "versions",
+			when:   "all values >= 1.9.0",
+			values: []interface{}{"1.10.2", "v1.9.0"},
+			isPass: true,
+		},
+		{
+			name:   "single value",
+			when:   `value != "Failed" && count == 1`,
+			values: []interface{}{"Running"},
+			isPass: true,
+		},
+		{
+			name:   "contains array element",
+			when:   `value contains "NET_ADMIN"`,
+			values: []interface{}{[]interface{}{"NET_ADMIN", "SYS_TIME"}},
+			isPass: true,
+		},
+		{
+			name:   "in list",
+			when:   "all values in [1, 3, 5]",
+			values: []interface{}{float64(3), 4},
+			isPass: false,
+		},
+		{
+			name:   "matches regex",
+			when:   `all values matches "^v1\.2[0-9]\."`,
+			values: []interface{}{"v1.24.3", "v1.21.0"},
+			isPass: true,
+		},
+		{
+			name:   "no values",
+			when:   "any values == 0",
+			values: []interface{}{},
+			isPass: false,
+		},
+		{
+			name:        "equal to the expected value",
+			when:        "true",
+			values:      []interface{}{"IfNotPresent", "IfNotPresent"},
+			expected:    "IfNotPresent",
+			hasExpected: true,
+			isPass:      true,
+		},
+		{
+			name:    "equality without an expected value",
+			when:    "false",
+			values:  []interface{}{"foo"},
+			isError: true,
+		},
+		{
+			name:    "unknown operator",
+			when:    "value like foo",
+			values:  []interface{}{"foo"},
+			isError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			outcomes := []*troubleshootv1beta2.Outcome{
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: test.when, Message: "pass"}},
+				{Fail: &troubleshootv1beta2.SingleOutcome{Message: "fail"}},
+			}
+
+			actual, err := compareValuesStatus(outcomes, "Compare", test.values, test.expected, test.hasExpected)
+			if test.isError {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.isPass, actual.IsPass)
+		})
+	}
+}
@@ -8,6 +8,26 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+type AnalyzeConfigMap struct {
+	analyzer *troubleshootv1beta2.AnalyzeConfigMap
+}
+
+func (a *AnalyzeConfigMap) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, fmt.Sprintf("ConfigMap %s", a.analyzer.ConfigMapName))
+}
+
+func (a *AnalyzeConfigMap) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeConfigMap) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeConfigMap(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeConfigMap(analyzer *troubleshootv1beta2.AnalyzeConfigMap, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	filename := collect.GetConfigMapFileName(
 		&troubleshootv1beta2.ConfigMap{
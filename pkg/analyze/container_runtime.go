@@ -1,24 +1,42 @@
 package analyzer
 
 import (
-	"encoding/json"
 	"net/url"
 	"strings"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
-	corev1 "k8s.io/api/core/v1"
 )
 
-func analyzeContainerRuntime(analyzer *troubleshootv1beta2.ContainerRuntime, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
-	collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+type AnalyzeContainerRuntime struct {
+	analyzer *troubleshootv1beta2.ContainerRuntime
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeContainerRuntime) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Container Runtime")
+}
+
+func (a *AnalyzeContainerRuntime) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeContainerRuntime) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeContainerRuntime) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeContainerRuntime(a.analyzer, getFile, a.objects)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+		return nil, err
 	}
+	return []*AnalyzeResult{result}, nil
+}
 
-	var nodes corev1.NodeList
-	if err := json.Unmarshal(collected, &nodes); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal node list")
+func analyzeContainerRuntime(analyzer *troubleshootv1beta2.ContainerRuntime, getCollectedFileContents func(string) ([]byte, error), objects *collectedFileCache) (*AnalyzeResult, error) {
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
+	if err != nil {
+		return nil, err
 	}
 
 	foundRuntimes := []string{}
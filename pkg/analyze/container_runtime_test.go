@@ -113,7 +113,7 @@ func Test_containerRuntime(t *testing.T) {
 				return test.files[n], nil
 			}
 
-			actual, err := analyzeContainerRuntime(&test.analyzer, getFiles)
+			actual, err := analyzeContainerRuntime(&test.analyzer, getFiles, nil)
 			req.NoError(err)
 
 			assert.Equal(t, &test.expectResult, actual)
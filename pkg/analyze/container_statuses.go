@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AnalyzeContainerStatuses struct {
+	analyzer *troubleshootv1beta2.ContainerStatuses
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeContainerStatuses) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Container Statuses")
+}
+
+func (a *AnalyzeContainerStatuses) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeContainerStatuses) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeContainerStatuses) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeContainerStatuses(a.analyzer, findFiles, a.objects)
+}
+
+// containerState is what outcomes of the containerStatuses analyzer are evaluated against, and what
+// their messages are templated with.
+type containerState struct {
+	Namespace            string
+	PodName              string
+	ContainerName        string
+	InitContainer        bool
+	Ready                bool
+	RestartCount         int
+	State                string
+	WaitingReason        string
+	LastTerminatedReason string
+	LastExitCode         int
+	LastRestart          time.Time
+	// MinutesSinceLastRestart is relative to getReferenceTime, and only set when the container has
+	// restarted
+	MinutesSinceLastRestart int
+}
+
+func (s containerState) vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"namespace":            s.Namespace,
+		"pod":                  s.PodName,
+		"container":            s.ContainerName,
+		"initContainer":        s.InitContainer,
+		"ready":                s.Ready,
+		"restartCount":         s.RestartCount,
+		"state":                s.State,
+		"waitingReason":        s.WaitingReason,
+		"lastTerminatedReason": s.LastTerminatedReason,
+		"lastExitCode":         s.LastExitCode,
+	}
+	if !s.LastRestart.IsZero() {
+		vars["minutesSinceLastRestart"] = s.MinutesSinceLastRestart
+	}
+	return vars
+}
+
+func analyzeContainerStatuses(analyzer *troubleshootv1beta2.ContainerStatuses, getChildCollectedFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	pods, err := readCollectedPods(objects, analyzer.Namespaces, getChildCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	states := []containerState{}
+	for _, pod := range pods {
+		if !labelSelector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+
+		for i, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, status := range statuses {
+				if analyzer.ContainerName != "" && status.Name != analyzer.ContainerName {
+					continue
+				}
+				states = append(states, getContainerState(&pod, &status, i == 0))
+			}
+		}
+	}
+
+	now, err := getReferenceTime(objects, getChildCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+	results := []*AnalyzeResult{}
+	for _, state := range states {
+		if !state.LastRestart.IsZero() {
+			state.MinutesSinceLastRestart = int(now.Sub(state.LastRestart).Minutes())
+		}
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = containerStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process container status")
+			}
+		} else {
+			result = getDefaultContainerStatusResult(state)
+		}
+
+		if result != nil {
+			fieldPath := fmt.Sprintf("spec.containers{%s}", state.ContainerName)
+			if state.InitContainer {
+				fieldPath = fmt.Sprintf("spec.initContainers{%s}", state.ContainerName)
+			}
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Namespace:  state.Namespace,
+				Name:       state.PodName,
+				FieldPath:  fieldPath,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func getContainerState(pod *corev1.Pod, status *corev1.ContainerStatus, initContainer bool) containerState {
+	state := containerState{
+		Namespace:     pod.Namespace,
+		PodName:       pod.Name,
+		ContainerName: status.Name,
+		InitContainer: initContainer,
+		Ready:         status.Ready,
+		RestartCount:  int(status.RestartCount),
+	}
+
+	switch {
+	case status.State.Running != nil:
+		state.State = "running"
+	case status.State.Waiting != nil:
+		state.State = "waiting"
+		state.WaitingReason = status.State.Waiting.Reason
+	case status.State.Terminated != nil:
+		state.State = "terminated"
+	}
+
+	// a container that is still terminated has not been restarted yet, so report on its current termination
+	terminated := status.LastTerminationState.Terminated
+	if status.State.Terminated != nil {
+		terminated = status.State.Terminated
+	}
+	if terminated != nil {
+		state.LastTerminatedReason = terminated.Reason
+		state.LastExitCode = int(terminated.ExitCode)
+	}
+	if status.LastTerminationState.Terminated != nil {
+		state.LastRestart = status.LastTerminationState.Terminated.FinishedAt.Time
+	}
+
+	return state
+}
+
+func containerStatus(outcomes []*troubleshootv1beta2.Outcome, state containerState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := optionalVariablesDoNotMatch(func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}, "minutesSinceLastRestart")
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s Container %s Status", state.Namespace, state.PodName, state.ContainerName), "", "", compareWhen, state)
+}
+
+func getDefaultContainerStatusResult(state containerState) *AnalyzeResult {
+	if state.RestartCount == 0 && state.WaitingReason != "CrashLoopBackOff" {
+		return nil
+	}
+
+	result := &AnalyzeResult{
+		Title: fmt.Sprintf("%s/%s Container %s Status", state.Namespace, state.PodName, state.ContainerName),
+	}
+
+	message := fmt.Sprintf("Container %s in pod %s/%s has restarted %d times", state.ContainerName, state.Namespace, state.PodName, state.RestartCount)
+	if state.LastTerminatedReason != "" {
+		message = fmt.Sprintf("%s, last terminated with %s (exit code %d)", message, state.LastTerminatedReason, state.LastExitCode)
+	}
+	if state.WaitingReason != "" {
+		message = fmt.Sprintf("%s and is in %s", message, state.WaitingReason)
+	}
+	result.Message = message
+
+	if state.WaitingReason == "CrashLoopBackOff" || state.LastTerminatedReason == "OOMKilled" {
+		result.IsFail = true
+	} else {
+		result.IsWarn = true
+	}
+
+	return result
+}
@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeContainerStatuses(t *testing.T) {
+	apiContainer := &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  "default",
+		Name:       "api-6d4cf56db6-2xk8p",
+		FieldPath:  "spec.containers{api}",
+	}
+	workerContainer := &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespace:  "default",
+		Name:       "worker-0",
+		FieldPath:  "spec.containers{worker}",
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ContainerStatuses
+		expectResult []*AnalyzeResult
+	}{
+		{
+			name:     "restarted containers without outcomes",
+			analyzer: troubleshootv1beta2.ContainerStatuses{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api-6d4cf56db6-2xk8p Container api Status",
+					Message:        "Container api in pod default/api-6d4cf56db6-2xk8p has restarted 7 times, last terminated with OOMKilled (exit code 137) and is in CrashLoopBackOff",
+					InvolvedObject: apiContainer,
+				},
+				{
+					IsWarn:         true,
+					Title:          "default/worker-0 Container worker Status",
+					Message:        "Container worker in pod default/worker-0 has restarted 1 times, last terminated with Error (exit code 1)",
+					InvolvedObject: workerContainer,
+				},
+			},
+		},
+		{
+			name: "restart thresholds and reasons",
+			analyzer: troubleshootv1beta2.ContainerStatuses{
+				Namespaces: []string{"default"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    `lastTerminatedReason == "OOMKilled"`,
+							Message: "{{ .ContainerName }} in {{ .Namespace }}/{{ .PodName }} was OOM killed",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "restartCount > 0 && minutesSinceLastRestart < 60",
+							Message: "{{ .ContainerName }} restarted {{ .MinutesSinceLastRestart }} minutes ago",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .ContainerName }} is stable",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api-6d4cf56db6-2xk8p Container api Status",
+					Message:        "api in default/api-6d4cf56db6-2xk8p was OOM killed",
+					InvolvedObject: apiContainer,
+				},
+				{
+					IsPass:  true,
+					Title:   "default/api-6d4cf56db6-2xk8p Container migrate Status",
+					Message: "migrate is stable",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Pod",
+						Namespace:  "default",
+						Name:       "api-6d4cf56db6-2xk8p",
+						FieldPath:  "spec.initContainers{migrate}",
+					},
+				},
+				{
+					IsWarn:         true,
+					Title:          "default/worker-0 Container worker Status",
+					Message:        "worker restarted 20 minutes ago",
+					InvolvedObject: workerContainer,
+				},
+			},
+		},
+		{
+			name: "container that never restarted",
+			analyzer: troubleshootv1beta2.ContainerStatuses{
+				ContainerName: "migrate",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "minutesSinceLastRestart < 60",
+							Message: "{{ .ContainerName }} restarted recently",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .ContainerName }} has not restarted",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "default/api-6d4cf56db6-2xk8p Container migrate Status",
+					Message: "migrate has not restarted",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "Pod",
+						Namespace:  "default",
+						Name:       "api-6d4cf56db6-2xk8p",
+						FieldPath:  "spec.initContainers{migrate}",
+					},
+				},
+			},
+		},
+		{
+			name: "container name and exit code",
+			analyzer: troubleshootv1beta2.ContainerStatuses{
+				ContainerName: "worker",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "lastExitCode != 0",
+							Message: "exited with {{ .LastExitCode }}",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/worker-0 Container worker Status",
+					Message:        "exited with 1",
+					InvolvedObject: workerContainer,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFiles := func(n string) (map[string][]byte, error) {
+				return map[string][]byte{
+					"cluster-resources/pods/default.json": []byte(containerStatusesPods),
+				}, nil
+			}
+
+			actual, err := analyzeContainerStatuses(&test.analyzer, getFiles, nil)
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var containerStatusesPods = `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "api-6d4cf56db6-2xk8p",
+        "namespace": "default",
+        "labels": {
+          "app": "api"
+        }
+      },
+      "status": {
+        "phase": "Running",
+        "initContainerStatuses": [
+          {
+            "name": "migrate",
+            "state": {
+              "terminated": {
+                "exitCode": 0,
+                "reason": "Completed",
+                "startedAt": "2022-03-01T09:00:00Z",
+                "finishedAt": "2022-03-01T09:01:00Z"
+              }
+            },
+            "lastState": {},
+            "ready": true,
+            "restartCount": 0,
+            "image": "api:1.0.0",
+            "imageID": ""
+          }
+        ],
+        "containerStatuses": [
+          {
+            "name": "api",
+            "state": {
+              "waiting": {
+                "reason": "CrashLoopBackOff",
+                "message": "back-off 5m0s restarting failed container"
+              }
+            },
+            "lastState": {
+              "terminated": {
+                "exitCode": 137,
+                "reason": "OOMKilled",
+                "startedAt": "2022-03-01T11:50:00Z",
+                "finishedAt": "2022-03-01T11:55:00Z"
+              }
+            },
+            "ready": false,
+            "restartCount": 7,
+            "image": "api:1.0.0",
+            "imageID": ""
+          }
+        ]
+      }
+    },
+    {
+      "metadata": {
+        "name": "worker-0",
+        "namespace": "default",
+        "labels": {
+          "app": "worker"
+        }
+      },
+      "status": {
+        "phase": "Running",
+        "containerStatuses": [
+          {
+            "name": "worker",
+            "state": {
+              "running": {
+                "startedAt": "2022-03-01T12:00:00Z"
+              }
+            },
+            "lastState": {
+              "terminated": {
+                "exitCode": 1,
+                "reason": "Error",
+                "startedAt": "2022-03-01T10:00:00Z",
+                "finishedAt": "2022-03-01T11:40:00Z"
+              }
+            },
+            "ready": true,
+            "restartCount": 1,
+            "image": "worker:1.0.0",
+            "imageID": ""
+          }
+        ]
+      }
+    }
+  ]
+}`
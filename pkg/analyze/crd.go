@@ -8,6 +8,26 @@ import (
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 )
 
+type AnalyzeCustomResourceDefinition struct {
+	analyzer *troubleshootv1beta2.CustomResourceDefinition
+}
+
+func (a *AnalyzeCustomResourceDefinition) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, fmt.Sprintf("Custom resource definition %s", a.analyzer.CustomResourceDefinitionName))
+}
+
+func (a *AnalyzeCustomResourceDefinition) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCustomResourceDefinition) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeCustomResourceDefinition(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeCustomResourceDefinition(analyzer *troubleshootv1beta2.CustomResourceDefinition, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	crdData, err := getCollectedFileContents("cluster-resources/custom-resource-definitions.json")
 	if err != nil {
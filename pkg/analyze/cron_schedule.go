@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronSchedule is a parsed standard five field cron schedule, as used by CronJobs.
+type cronSchedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	// anyDay and anyWeekday are set for "*", because a day matches if either the day of the month or
+	// the day of the week matches when both are restricted
+	anyDay     bool
+	anyWeekday bool
+	location   *time.Location
+}
+
+var cronScheduleAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseCronSchedule(schedule string) (*cronSchedule, error) {
+	s := &cronSchedule{location: time.UTC}
+
+	schedule = strings.TrimSpace(schedule)
+	if strings.HasPrefix(schedule, "TZ=") || strings.HasPrefix(schedule, "CRON_TZ=") {
+		parts := strings.SplitN(schedule, " ", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid schedule %q", schedule)
+		}
+		location, err := time.LoadLocation(strings.SplitN(parts[0], "=", 2)[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid time zone in schedule %q", schedule)
+		}
+		s.location = location
+		schedule = strings.TrimSpace(parts[1])
+	}
+
+	if alias, ok := cronScheduleAliases[strings.ToLower(schedule)]; ok {
+		schedule = alias
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields in schedule %q", schedule)
+	}
+
+	var err error
+	if s.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, errors.Wrap(err, "invalid minute")
+	}
+	if s.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, errors.Wrap(err, "invalid hour")
+	}
+	if s.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, errors.Wrap(err, "invalid day of month")
+	}
+	if s.months, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, errors.Wrap(err, "invalid month")
+	}
+	if s.weekdays, err = parseCronField(fields[4], 0, 7, cronWeekdayNames); err != nil {
+		return nil, errors.Wrap(err, "invalid day of week")
+	}
+	if s.weekdays[7] {
+		s.weekdays[0] = true
+	}
+	s.anyDay = fields[2] == "*" || fields[2] == "?"
+	s.anyWeekday = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+func parseCronField(field string, min int, max int, names map[string]int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return nil, errors.Errorf("invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], names); err != nil {
+				return nil, err
+			}
+			if end, err = parseCronValue(bounds[1], names); err != nil {
+				return nil, err
+			}
+		default:
+			value, err := parseCronValue(part, names)
+			if err != nil {
+				return nil, err
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, errors.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+func parseCronValue(value string, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", value)
+	}
+	return n, nil
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dayMatches := s.days[t.Day()]
+	weekdayMatches := s.weekdays[int(t.Weekday())]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekdayMatches
+	case s.anyWeekday:
+		return dayMatches
+	}
+	return dayMatches || weekdayMatches
+}
+
+// prev returns the most recent time at or before t at which the schedule runs. The second return
+// value is false if the schedule does not run in the five years before t.
+func (s *cronSchedule) prev(t time.Time) (time.Time, bool) {
+	t = t.In(s.location).Truncate(time.Minute)
+	limit := t.AddDate(-5, 0, 0)
+
+	for t.After(limit) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, s.location).Add(-time.Minute)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location).Add(-time.Minute)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, s.location).Add(-time.Minute)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,297 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type AnalyzeCronJobStatus struct {
+	analyzer *troubleshootv1beta2.CronJobStatus
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeCronJobStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "CronJob Status")
+}
+
+func (a *AnalyzeCronJobStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCronJobStatus) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeCronJobStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeCronJobStatus(a.analyzer, findFiles, a.objects)
+}
+
+// cronJobState is what outcomes of the cronJobStatus analyzer are evaluated against, and what their
+// messages are templated with. Times are relative to getReferenceTime, and only set when the cronjob
+// has been scheduled or succeeded at all.
+type cronJobState struct {
+	Namespace      string
+	Name           string
+	Schedule       string
+	Suspended      bool
+	Active         int
+	MissedSchedule bool
+	// FailureStreak is the number of most recent jobs that failed in a row
+	FailureStreak            int
+	LastScheduleTime         *time.Time
+	LastSuccessfulTime       *time.Time
+	MinutesSinceLastSchedule int
+	MinutesSinceLastSuccess  int
+}
+
+func (s cronJobState) healthy() bool {
+	return !s.MissedSchedule && s.FailureStreak == 0
+}
+
+func (s cronJobState) vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"namespace":      s.Namespace,
+		"name":           s.Name,
+		"schedule":       s.Schedule,
+		"suspended":      s.Suspended,
+		"active":         s.Active,
+		"missedSchedule": s.MissedSchedule,
+		"failureStreak":  s.FailureStreak,
+		"healthy":        s.healthy(),
+	}
+	if s.LastScheduleTime != nil {
+		vars["minutesSinceLastSchedule"] = s.MinutesSinceLastSchedule
+	}
+	if s.LastSuccessfulTime != nil {
+		vars["minutesSinceLastSuccess"] = s.MinutesSinceLastSuccess
+	}
+	return vars
+}
+
+func analyzeCronJobStatus(analyzer *troubleshootv1beta2.CronJobStatus, getFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	namespaces := []string{}
+	if analyzer.Namespace != "" {
+		namespaces = append(namespaces, analyzer.Namespace)
+	}
+	namespaces = append(namespaces, analyzer.Namespaces...)
+
+	fileNames := []string{}
+	for _, ns := range namespaces {
+		fileNames = append(fileNames, fmt.Sprintf("%s.json", ns))
+	}
+	// no namespace specified, so we need to analyze all cronjobs
+	if len(fileNames) == 0 {
+		fileNames = append(fileNames, "*.json")
+	}
+
+	cronJobs := []batchv1beta1.CronJob{}
+	jobs := []batchv1.Job{}
+	for _, fileName := range fileNames {
+		files, err := getFileContents(filepath.Join("cluster-resources", "cronjobs", fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected cronjobs from file")
+		}
+		for _, collected := range files {
+			var list batchv1beta1.CronJobList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal cronjob list")
+			}
+			cronJobs = append(cronJobs, list.Items...)
+		}
+
+		files, err = getFileContents(filepath.Join("cluster-resources", "jobs", fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected jobs from file")
+		}
+		for _, collected := range files {
+			var list batchv1.JobList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal job list")
+			}
+			jobs = append(jobs, list.Items...)
+		}
+	}
+
+	now, err := getReferenceTime(objects, getFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*AnalyzeResult{}
+	found := false
+	for _, cronJob := range cronJobs {
+		if analyzer.Name != "" && cronJob.Name != analyzer.Name {
+			continue
+		}
+		found = true
+
+		state, err := getCronJobState(&cronJob, jobs, now)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get status of cronjob %s/%s", cronJob.Namespace, cronJob.Name)
+		}
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = cronJobStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process status")
+			}
+		} else {
+			result = getDefaultCronJobResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "batch/v1beta1",
+				Kind:       "CronJob",
+				Namespace:  cronJob.Namespace,
+				Name:       cronJob.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	if analyzer.Name != "" && !found {
+		// there's not an error, but maybe the requested cronjob is not even deployed
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s CronJob Status", analyzer.Name),
+				IconKey: "kubernetes_deployment_status",                                                  // TODO: need new icon
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", // TODO: need new icon
+				IsFail:  true,
+				Message: fmt.Sprintf("The cronjob %q was not found", analyzer.Name),
+			},
+		}, nil
+	}
+
+	return results, nil
+}
+
+func getCronJobState(cronJob *batchv1beta1.CronJob, jobs []batchv1.Job, now time.Time) (cronJobState, error) {
+	state := cronJobState{
+		Namespace: cronJob.Namespace,
+		Name:      cronJob.Name,
+		Schedule:  cronJob.Spec.Schedule,
+		Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		Active:    len(cronJob.Status.Active),
+	}
+
+	if cronJob.Status.LastScheduleTime != nil {
+		t := cronJob.Status.LastScheduleTime.Time
+		state.LastScheduleTime = &t
+		state.MinutesSinceLastSchedule = int(now.Sub(t).Minutes())
+	}
+	if cronJob.Status.LastSuccessfulTime != nil {
+		t := cronJob.Status.LastSuccessfulTime.Time
+		state.LastSuccessfulTime = &t
+		state.MinutesSinceLastSuccess = int(now.Sub(t).Minutes())
+	}
+
+	// a suspended cronjob is not expected to run, and without a last schedule time there is nothing to compare to
+	if !state.Suspended && state.LastScheduleTime != nil {
+		schedule, err := parseCronSchedule(cronJob.Spec.Schedule)
+		if err != nil {
+			return state, errors.Wrap(err, "failed to parse schedule")
+		}
+		expected, ok := schedule.prev(now)
+		if ok && expected.After(*state.LastScheduleTime) {
+			deadline := expected
+			if cronJob.Spec.StartingDeadlineSeconds != nil {
+				deadline = deadline.Add(time.Duration(*cronJob.Spec.StartingDeadlineSeconds) * time.Second)
+			}
+			state.MissedSchedule = now.After(deadline)
+		}
+	}
+
+	state.FailureStreak = cronJobFailureStreak(cronJob, jobs)
+
+	return state, nil
+}
+
+// cronJobFailureStreak returns how many of the most recent finished jobs owned by the cronjob failed in a row.
+func cronJobFailureStreak(cronJob *batchv1beta1.CronJob, jobs []batchv1.Job) int {
+	owned := []batchv1.Job{}
+	for _, job := range jobs {
+		if job.Namespace != cronJob.Namespace {
+			continue
+		}
+		for _, owner := range job.OwnerReferences {
+			if owner.Kind == "CronJob" && owner.Name == cronJob.Name {
+				owned = append(owned, job)
+				break
+			}
+		}
+	}
+
+	jobStart := func(job batchv1.Job) time.Time {
+		if job.Status.StartTime != nil {
+			return job.Status.StartTime.Time
+		}
+		return job.CreationTimestamp.Time
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return jobStart(owned[i]).After(jobStart(owned[j]))
+	})
+
+	streak := 0
+	for _, job := range owned {
+		switch {
+		case jobHasCondition(&job, batchv1.JobFailed):
+			streak++
+		case jobHasCondition(&job, batchv1.JobComplete):
+			return streak
+		}
+	}
+	return streak
+}
+
+func jobHasCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func cronJobStatus(outcomes []*troubleshootv1beta2.Outcome, state cronJobState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := optionalVariablesDoNotMatch(func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}, "minutesSinceLastSchedule", "minutesSinceLastSuccess")
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s CronJob Status", state.Namespace, state.Name), "kubernetes_deployment_status", "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", compareWhen, state)
+}
+
+func getDefaultCronJobResult(state cronJobState) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title:   fmt.Sprintf("%s/%s CronJob Status", state.Namespace, state.Name),
+		IconKey: "kubernetes_deployment_status",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+	}
+
+	switch {
+	case state.FailureStreak > 0:
+		result.IsFail = true
+		result.Message = fmt.Sprintf("The last %d jobs of cronjob %s/%s failed", state.FailureStreak, state.Namespace, state.Name)
+	case state.MissedSchedule:
+		result.IsFail = true
+		result.Message = fmt.Sprintf("The cronjob %s/%s missed its schedule %q", state.Namespace, state.Name, state.Schedule)
+	case state.Suspended:
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("The cronjob %s/%s is suspended", state.Namespace, state.Name)
+	default:
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,367 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_cronSchedulePrev(t *testing.T) {
+	tests := []struct {
+		schedule string
+		at       string
+		want     string
+		wantErr  bool
+	}{
+		{
+			schedule: "*/15 * * * *",
+			at:       "2022-03-01T12:07:30Z",
+			want:     "2022-03-01T12:00:00Z",
+		},
+		{
+			schedule: "@daily",
+			at:       "2022-03-01T12:07:00Z",
+			want:     "2022-03-01T00:00:00Z",
+		},
+		{
+			schedule: "30 2 * * MON-FRI",
+			at:       "2022-03-06T12:00:00Z", // a sunday
+			want:     "2022-03-04T02:30:00Z",
+		},
+		{
+			schedule: "0 0 1,15 * *",
+			at:       "2022-03-14T12:00:00Z",
+			want:     "2022-03-01T00:00:00Z",
+		},
+		{
+			schedule: "0 3 1 * 0",
+			at:       "2022-03-07T12:00:00Z", // day of month or day of week
+			want:     "2022-03-06T03:00:00Z",
+		},
+		{
+			schedule: "TZ=America/New_York 0 1 * * *",
+			at:       "2022-03-01T12:00:00Z",
+			want:     "2022-03-01T06:00:00Z",
+		},
+		{
+			schedule: "0 0 * *",
+			wantErr:  true,
+		},
+		{
+			schedule: "61 * * * *",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			req := require.New(t)
+
+			schedule, err := parseCronSchedule(test.schedule)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			at, err := time.Parse(time.RFC3339, test.at)
+			req.NoError(err)
+			want, err := time.Parse(time.RFC3339, test.want)
+			req.NoError(err)
+
+			got, ok := schedule.prev(at)
+			req.True(ok)
+			assert.True(t, want.Equal(got), "expected %s, got %s", want, got)
+		})
+	}
+}
+
+func Test_analyzeCronJobStatus(t *testing.T) {
+	cronJobRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "batch/v1beta1", Kind: "CronJob", Namespace: "default", Name: name}
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.CronJobStatus
+		nodes        string
+		expectResult []*AnalyzeResult
+	}{
+		{
+			name:     "unhealthy cronjobs without outcomes",
+			analyzer: troubleshootv1beta2.CronJobStatus{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/backup CronJob Status",
+					Message:        "The last 2 jobs of cronjob default/backup failed",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: cronJobRef("backup"),
+				},
+				{
+					IsFail:         true,
+					Title:          "default/cleanup CronJob Status",
+					Message:        `The cronjob default/cleanup missed its schedule "0 * * * *"`,
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: cronJobRef("cleanup"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "default/report CronJob Status",
+					Message:        "The cronjob default/report is suspended",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: cronJobRef("report"),
+				},
+			},
+		},
+		{
+			name: "outcomes",
+			analyzer: troubleshootv1beta2.CronJobStatus{
+				Namespace: "default",
+				Name:      "backup",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "failureStreak >= 2 || minutesSinceLastSuccess > 1440",
+							Message: "{{ .Name }} has failed {{ .FailureStreak }} times in a row",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "healthy",
+							Message: "pass",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/backup CronJob Status",
+					Message:        "backup has failed 2 times in a row",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: cronJobRef("backup"),
+				},
+			},
+		},
+		{
+			name: "ages are measured from the newest timestamp in the bundle",
+			analyzer: troubleshootv1beta2.CronJobStatus{
+				Namespace: "default",
+				Name:      "heartbeat",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "missedSchedule",
+							Message: "{{ .Name }} last ran {{ .MinutesSinceLastSchedule }} minutes before the bundle was collected",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "pass",
+						},
+					},
+				},
+			},
+			// the nodes kept reporting for an hour after the last job ran
+			nodes: `{
+  "kind": "NodeList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "node-1", "creationTimestamp": "2022-01-01T00:00:00Z"},
+      "status": {
+        "conditions": [
+          {"type": "Ready", "status": "True", "lastHeartbeatTime": "2022-03-01T13:00:30Z", "lastTransitionTime": "2022-01-01T00:00:00Z"}
+        ]
+      }
+    }
+  ]
+}`,
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/heartbeat CronJob Status",
+					Message:        "heartbeat last ran 60 minutes before the bundle was collected",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: cronJobRef("heartbeat"),
+				},
+			},
+		},
+		{
+			name: "not found",
+			analyzer: troubleshootv1beta2.CronJobStatus{
+				Namespace: "default",
+				Name:      "does-not-exist",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "does-not-exist CronJob Status",
+					Message: `The cronjob "does-not-exist" was not found`,
+					IconKey: "kubernetes_deployment_status",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			files := map[string][]byte{
+				"cluster-resources/cronjobs/default.json": []byte(defaultCronJobs),
+				"cluster-resources/jobs/default.json":     []byte(defaultCronJobJobs),
+			}
+			if test.nodes != "" {
+				files["cluster-resources/nodes.json"] = []byte(test.nodes)
+			}
+			getFiles := func(n string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, file := range files {
+					if matched, _ := filepath.Match(n, name); matched {
+						matching[name] = file
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeCronJobStatus(&test.analyzer, getFiles, nil)
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+func Test_cronJobStatusNeverSucceeded(t *testing.T) {
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "minutesSinceLastSuccess > 1440",
+				Message: "no recent success",
+			},
+		},
+		{
+			Warn: &troubleshootv1beta2.SingleOutcome{
+				Message: "never succeeded",
+			},
+		},
+	}
+
+	result, err := cronJobStatus(outcomes, cronJobState{Namespace: "default", Name: "new"})
+	require.NoError(t, err)
+	assert.True(t, result.IsWarn)
+	assert.Equal(t, "never succeeded", result.Message)
+}
+
+var defaultCronJobs = `{
+  "kind": "CronJobList",
+  "apiVersion": "batch/v1beta1",
+  "items": [
+    {
+      "metadata": {"name": "backup", "namespace": "default"},
+      "spec": {"schedule": "0 2 * * *", "jobTemplate": {"spec": {"template": {"spec": {"containers": []}}}}},
+      "status": {
+        "lastScheduleTime": "2022-03-01T02:00:00Z",
+        "lastSuccessfulTime": "2022-02-27T02:05:00Z"
+      }
+    },
+    {
+      "metadata": {"name": "cleanup", "namespace": "default"},
+      "spec": {"schedule": "0 * * * *", "jobTemplate": {"spec": {"template": {"spec": {"containers": []}}}}},
+      "status": {
+        "lastScheduleTime": "2022-03-01T09:00:00Z",
+        "lastSuccessfulTime": "2022-03-01T09:01:00Z"
+      }
+    },
+    {
+      "metadata": {"name": "report", "namespace": "default"},
+      "spec": {"schedule": "0 6 * * 1", "suspend": true, "jobTemplate": {"spec": {"template": {"spec": {"containers": []}}}}},
+      "status": {}
+    },
+    {
+      "metadata": {"name": "heartbeat", "namespace": "default"},
+      "spec": {"schedule": "*/5 * * * *", "jobTemplate": {"spec": {"template": {"spec": {"containers": []}}}}},
+      "status": {
+        "lastScheduleTime": "2022-03-01T12:00:00Z",
+        "lastSuccessfulTime": "2022-03-01T12:00:05Z"
+      }
+    }
+  ]
+}`
+
+var defaultCronJobJobs = `{
+  "kind": "JobList",
+  "apiVersion": "batch/v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "backup-27434520",
+        "namespace": "default",
+        "ownerReferences": [{"apiVersion": "batch/v1", "kind": "CronJob", "name": "backup", "uid": "1"}]
+      },
+      "spec": {"template": {"spec": {"containers": []}}},
+      "status": {
+        "startTime": "2022-03-01T02:00:00Z",
+        "failed": 6,
+        "conditions": [{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"}]
+      }
+    },
+    {
+      "metadata": {
+        "name": "backup-27433080",
+        "namespace": "default",
+        "ownerReferences": [{"apiVersion": "batch/v1", "kind": "CronJob", "name": "backup", "uid": "1"}]
+      },
+      "spec": {"template": {"spec": {"containers": []}}},
+      "status": {
+        "startTime": "2022-02-28T02:00:00Z",
+        "failed": 6,
+        "conditions": [{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"}]
+      }
+    },
+    {
+      "metadata": {
+        "name": "backup-27431640",
+        "namespace": "default",
+        "ownerReferences": [{"apiVersion": "batch/v1", "kind": "CronJob", "name": "backup", "uid": "1"}]
+      },
+      "spec": {"template": {"spec": {"containers": []}}},
+      "status": {
+        "startTime": "2022-02-27T02:00:00Z",
+        "completionTime": "2022-02-27T02:05:00Z",
+        "succeeded": 1,
+        "conditions": [{"type": "Complete", "status": "True"}]
+      }
+    },
+    {
+      "metadata": {
+        "name": "heartbeat-27434880",
+        "namespace": "default",
+        "ownerReferences": [{"apiVersion": "batch/v1", "kind": "CronJob", "name": "heartbeat", "uid": "2"}]
+      },
+      "spec": {"template": {"spec": {"containers": []}}},
+      "status": {
+        "startTime": "2022-03-01T12:00:00Z",
+        "completionTime": "2022-03-01T12:00:05Z",
+        "succeeded": 1,
+        "conditions": [{"type": "Complete", "status": "True"}]
+      }
+    }
+  ]
+}`
@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type AnalyzeCustomResourceStatus struct {
+	analyzer *troubleshootv1beta2.CustomResourceStatus
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeCustomResourceStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Custom Resource Status")
+}
+
+func (a *AnalyzeCustomResourceStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeCustomResourceStatus) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeCustomResourceStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeCustomResourceStatus(a.analyzer, findFiles, a.objects)
+}
+
+// customResourceState is what outcomes of the customResourceStatus analyzer are evaluated against,
+// and what their messages are templated with. Status is the status of the condition, or empty if
+// the object does not have the condition. Times are relative to getReferenceTime, and only set when
+// the condition has a last transition time.
+type customResourceState struct {
+	APIVersion                 string
+	Kind                       string
+	Namespace                  string
+	Name                       string
+	ConditionType              string
+	Status                     string
+	Reason                     string
+	Message                    string
+	LastTransitionTime         *time.Time
+	MinutesSinceLastTransition int
+}
+
+func (s customResourceState) vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"apiVersion":    s.APIVersion,
+		"kind":          s.Kind,
+		"namespace":     s.Namespace,
+		"name":          s.Name,
+		"conditionType": s.ConditionType,
+		"status":        s.Status,
+		"reason":        s.Reason,
+		"message":       s.Message,
+		"found":         s.Status != "",
+	}
+	if s.LastTransitionTime != nil {
+		vars["minutesSinceLastTransition"] = s.MinutesSinceLastTransition
+	}
+	return vars
+}
+
+func (s customResourceState) title() string {
+	if s.Namespace == "" {
+		return fmt.Sprintf("%s %s Status", s.Name, s.Kind)
+	}
+	return fmt.Sprintf("%s/%s %s Status", s.Namespace, s.Name, s.Kind)
+}
+
+func analyzeCustomResourceStatus(analyzer *troubleshootv1beta2.CustomResourceStatus, getFileContents func(string) (map[string][]byte, error), collectedObjects *collectedFileCache) ([]*AnalyzeResult, error) {
+	if analyzer.Kind == "" {
+		return nil, errors.New("kind is required")
+	}
+
+	conditionType := analyzer.ConditionType
+	if conditionType == "" {
+		conditionType = "Ready"
+	}
+
+	namespaces := map[string]bool{}
+	if analyzer.Namespace != "" {
+		namespaces[analyzer.Namespace] = true
+	}
+	for _, ns := range analyzer.Namespaces {
+		namespaces[ns] = true
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	// custom resources are collected to <plural>.<group>.yaml, or <plural>.<group>/<namespace>.yaml
+	// when they are namespaced
+	fileNames := []string{
+		filepath.Join("cluster-resources", "custom-resources", fmt.Sprintf("*.%s.yaml", analyzer.Group)),
+		filepath.Join("cluster-resources", "custom-resources", fmt.Sprintf("*.%s", analyzer.Group), "*.yaml"),
+	}
+
+	collected := []*unstructured.Unstructured{}
+	for _, fileName := range fileNames {
+		files, err := getFileContents(fileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected custom resources from file")
+		}
+		for name, contents := range files {
+			objects, err := decodeCollectedObjects(contents)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode %s", name)
+			}
+			for _, object := range objects {
+				collected = append(collected, &unstructured.Unstructured{Object: object})
+			}
+		}
+	}
+
+	now, err := getReferenceTime(collectedObjects, getFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []*unstructured.Unstructured{}
+	for _, object := range collected {
+		gv, err := schema.ParseGroupVersion(object.GetAPIVersion())
+		if err != nil {
+			continue
+		}
+		if gv.Group != analyzer.Group || object.GetKind() != analyzer.Kind {
+			continue
+		}
+		if analyzer.Version != "" && gv.Version != analyzer.Version {
+			continue
+		}
+		if len(namespaces) > 0 && !namespaces[object.GetNamespace()] {
+			continue
+		}
+		if analyzer.Name != "" && object.GetName() != analyzer.Name {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(object.GetLabels())) {
+			continue
+		}
+		objects = append(objects, object)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+
+	if analyzer.Name != "" && len(objects) == 0 {
+		// there's not an error, but maybe the requested object is not even created
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s %s Status", analyzer.Name, analyzer.Kind),
+				IsFail:  true,
+				Message: fmt.Sprintf("The %s %q was not found", analyzer.Kind, analyzer.Name),
+			},
+		}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, object := range objects {
+		state := getCustomResourceState(object, conditionType, now)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = customResourceStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process custom resource status")
+			}
+		} else {
+			result = getDefaultCustomResourceResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: state.APIVersion,
+				Kind:       state.Kind,
+				Namespace:  state.Namespace,
+				Name:       state.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func getCustomResourceState(object *unstructured.Unstructured, conditionType string, now time.Time) customResourceState {
+	state := customResourceState{
+		APIVersion:    object.GetAPIVersion(),
+		Kind:          object.GetKind(),
+		Namespace:     object.GetNamespace(),
+		Name:          object.GetName(),
+		ConditionType: conditionType,
+	}
+
+	for _, condition := range customResourceConditions(object) {
+		if t, _ := condition["type"].(string); t != conditionType {
+			continue
+		}
+		state.Status, _ = condition["status"].(string)
+		state.Reason, _ = condition["reason"].(string)
+		state.Message, _ = condition["message"].(string)
+		if t, ok := customResourceConditionTime(condition); ok {
+			state.LastTransitionTime = &t
+			state.MinutesSinceLastTransition = int(now.Sub(t).Minutes())
+		}
+		break
+	}
+
+	return state
+}
+
+func customResourceConditions(object *unstructured.Unstructured) []map[string]interface{} {
+	list, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	conditions := []map[string]interface{}{}
+	for _, item := range list {
+		if condition, ok := item.(map[string]interface{}); ok {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+func customResourceConditionTime(condition map[string]interface{}) (time.Time, bool) {
+	value, _ := condition["lastTransitionTime"].(string)
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func customResourceStatus(outcomes []*troubleshootv1beta2.Outcome, state customResourceState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := optionalVariablesDoNotMatch(func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}, "minutesSinceLastTransition")
+
+	return evaluateOutcomes(outcomes, state.title(), "", "", compareWhen, state)
+}
+
+func getDefaultCustomResourceResult(state customResourceState) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title: state.title(),
+	}
+
+	name := state.Name
+	if state.Namespace != "" {
+		name = fmt.Sprintf("%s/%s", state.Namespace, state.Name)
+	}
+
+	switch state.Status {
+	case "True":
+		return nil
+	case "":
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("The %s %s does not have a %s condition", state.Kind, name, state.ConditionType)
+	case "False":
+		result.IsFail = true
+		result.Message = fmt.Sprintf("The %s %s is not %s", state.Kind, name, state.ConditionType)
+	default:
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("The %s %s has %s condition status %s", state.Kind, name, state.ConditionType, state.Status)
+	}
+
+	if state.Reason != "" {
+		result.Message = fmt.Sprintf("%s: %s", result.Message, state.Reason)
+		if state.Message != "" {
+			result.Message = fmt.Sprintf("%s, %s", result.Message, state.Message)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,336 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeCustomResourceStatus(t *testing.T) {
+	certificateRef := func(namespace string, name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Namespace: namespace, Name: name}
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.CustomResourceStatus
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name: "default results",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group: "cert-manager.io",
+				Kind:  "Certificate",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api-tls Certificate Status",
+					Message:        "The Certificate default/api-tls is not Ready: Failed, Issuing certificate as Secret does not exist",
+					InvolvedObject: certificateRef("default", "api-tls"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "monitoring/grafana-tls Certificate Status",
+					Message:        "The Certificate monitoring/grafana-tls does not have a Ready condition",
+					InvolvedObject: certificateRef("monitoring", "grafana-tls"),
+				},
+			},
+		},
+		{
+			name: "condition status and age",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group: "cert-manager.io",
+				Kind:  "Certificate",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    `status == "False" && minutesSinceLastTransition > 60`,
+							Message: "{{ .Name }} has not been ready since {{ .LastTransitionTime.Format \"2006-01-02\" }}: {{ .Reason }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "!found",
+							Message: "{{ .Name }} has no {{ .ConditionType }} condition",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    `status == "True"`,
+							Message: "{{ .Name }} is ready",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api-tls Certificate Status",
+					Message:        "api-tls has not been ready since 2022-03-01: Failed",
+					InvolvedObject: certificateRef("default", "api-tls"),
+				},
+				{
+					IsPass:         true,
+					Title:          "default/web-tls Certificate Status",
+					Message:        "web-tls is ready",
+					InvolvedObject: certificateRef("default", "web-tls"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "monitoring/grafana-tls Certificate Status",
+					Message:        "grafana-tls has no Ready condition",
+					InvolvedObject: certificateRef("monitoring", "grafana-tls"),
+				},
+			},
+		},
+		{
+			name: "age of a missing condition",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group:     "cert-manager.io",
+				Kind:      "Certificate",
+				Namespace: "monitoring",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "minutesSinceLastTransition < 60",
+							Message: "{{ .Name }} changed recently",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Name }} is stable",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "monitoring/grafana-tls Certificate Status",
+					Message:        "grafana-tls is stable",
+					InvolvedObject: certificateRef("monitoring", "grafana-tls"),
+				},
+			},
+		},
+		{
+			name: "ages are measured from the newest timestamp in the bundle",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group:     "cert-manager.io",
+				Kind:      "Certificate",
+				Namespace: "default",
+				Selector:  []string{"app=web"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "minutesSinceLastTransition < 60",
+							Message: "{{ .Name }} changed {{ .MinutesSinceLastTransition }} minutes before the bundle was collected",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Name }} is stable",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "default/web-tls Certificate Status",
+					Message:        "web-tls changed 30 minutes before the bundle was collected",
+					InvolvedObject: certificateRef("default", "web-tls"),
+				},
+			},
+		},
+		{
+			name: "namespace, selector and condition type",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group:         "cert-manager.io",
+				Version:       "v1",
+				Kind:          "Certificate",
+				Namespace:     "default",
+				Selector:      []string{"app=web"},
+				ConditionType: "Issuing",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    `status == "True"`,
+							Message: "{{ .Name }} is being renewed",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "default/web-tls Certificate Status",
+					Message:        "web-tls is being renewed",
+					InvolvedObject: certificateRef("default", "web-tls"),
+				},
+			},
+		},
+		{
+			name: "cluster scoped",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group: "cert-manager.io",
+				Kind:  "ClusterIssuer",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "letsencrypt ClusterIssuer Status",
+					Message: "The ClusterIssuer letsencrypt has Ready condition status Unknown: ErrRegisterACMEAccount",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "cert-manager.io/v1",
+						Kind:       "ClusterIssuer",
+						Name:       "letsencrypt",
+					},
+				},
+			},
+		},
+		{
+			name: "other versions are ignored",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group:   "cert-manager.io",
+				Version: "v1alpha2",
+				Kind:    "Certificate",
+			},
+			expectResult: []*AnalyzeResult{},
+		},
+		{
+			name: "object not found",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group: "kustomize.toolkit.fluxcd.io",
+				Kind:  "Kustomization",
+				Name:  "apps",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "apps Kustomization Status",
+					Message: "The Kustomization \"apps\" was not found",
+				},
+			},
+		},
+		{
+			name: "kind is required",
+			analyzer: troubleshootv1beta2.CustomResourceStatus{
+				Group: "cert-manager.io",
+			},
+			wantErr: true,
+		},
+	}
+
+	files := map[string][]byte{
+		"cluster-resources/custom-resources/certificates.cert-manager.io/default.yaml":    []byte(defaultCertificates),
+		"cluster-resources/custom-resources/certificates.cert-manager.io/monitoring.yaml": []byte(monitoringCertificates),
+		"cluster-resources/custom-resources/clusterissuers.cert-manager.io.yaml":          []byte(clusterIssuers),
+		"cluster-resources/custom-resources/custom-resources-errors.json":                 []byte("[]"),
+		"cluster-resources/nodes.json":                                                    []byte(nodeHeartbeats),
+	}
+	getFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := analyzeCustomResourceStatus(&test.analyzer, getFiles, nil)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var defaultCertificates = `- apiVersion: cert-manager.io/v1
+  kind: Certificate
+  metadata:
+    creationTimestamp: "2022-01-01T12:00:00Z"
+    labels:
+      app: api
+    name: api-tls
+    namespace: default
+  spec:
+    secretName: api-tls
+  status:
+    conditions:
+    - lastTransitionTime: "2022-03-01T12:00:00Z"
+      message: Issuing certificate as Secret does not exist
+      reason: Failed
+      status: "False"
+      type: Ready
+- apiVersion: cert-manager.io/v1
+  kind: Certificate
+  metadata:
+    creationTimestamp: "2022-01-01T12:00:00Z"
+    labels:
+      app: web
+    name: web-tls
+    namespace: default
+  spec:
+    secretName: web-tls
+  status:
+    conditions:
+    - lastTransitionTime: "2022-03-03T12:00:00Z"
+      message: Certificate is up to date and has not expired
+      reason: Ready
+      status: "True"
+      type: Ready
+    - lastTransitionTime: "2022-03-03T12:00:00Z"
+      message: Renewing certificate as renewal was scheduled
+      reason: Renewing
+      status: "True"
+      type: Issuing
+`
+
+var monitoringCertificates = `- apiVersion: cert-manager.io/v1
+  kind: Certificate
+  metadata:
+    creationTimestamp: "2022-03-03T11:59:00Z"
+    labels:
+      app: web
+    name: grafana-tls
+    namespace: monitoring
+  spec:
+    secretName: grafana-tls
+`
+
+// the nodes last reported half an hour after the web-tls certificate was renewed
+var nodeHeartbeats = `{"items": [{"metadata": {"name": "node-1"}, "status": {"conditions": [{"type": "Ready", "status": "True", "lastHeartbeatTime": "2022-03-03T12:30:00Z"}]}}]}`
+
+var clusterIssuers = `- apiVersion: cert-manager.io/v1
+  kind: ClusterIssuer
+  metadata:
+    creationTimestamp: "2022-01-01T12:00:00Z"
+    name: letsencrypt
+  spec:
+    acme:
+      server: https://acme-v02.api.letsencrypt.org/directory
+  status:
+    conditions:
+    - lastTransitionTime: "2022-01-01T12:00:00Z"
+      reason: ErrRegisterACMEAccount
+      status: Unknown
+      type: Ready
+`
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/expression"
+)
+
+const defaultRunIf = "pass"
+
+// analyzerGraph holds the dependencies between analyzers declared with dependsOn. Analyzers are
+// referred to by their index in the spec, and described by their meta, which is nil for analyzers
+// without one.
+type analyzerGraph struct {
+	metas []*troubleshootv1beta2.AnalyzeMeta
+	// deps are the indexes of the analyzers each analyzer depends on
+	deps [][]int
+	// errs are set for analyzers that can't run because of a broken dependency
+	errs []error
+}
+
+func newAnalyzerGraph(metas []*troubleshootv1beta2.AnalyzeMeta) *analyzerGraph {
+	g := &analyzerGraph{
+		metas: metas,
+		deps:  make([][]int, len(metas)),
+		errs:  make([]error, len(metas)),
+	}
+
+	byCheckName := map[string][]int{}
+	for i, meta := range metas {
+		if meta != nil && meta.CheckName != "" {
+			byCheckName[meta.CheckName] = append(byCheckName[meta.CheckName], i)
+		}
+	}
+
+	for i, meta := range metas {
+		if meta == nil {
+			continue
+		}
+		for _, checkName := range meta.DependsOn {
+			deps, ok := byCheckName[checkName]
+			if !ok {
+				g.errs[i] = errors.Errorf("analyzer depends on unknown analyzer %q", checkName)
+				break
+			}
+			g.deps[i] = append(g.deps[i], deps...)
+		}
+	}
+
+	g.markCycles()
+
+	return g
+}
+
+// markCycles sets an error on every analyzer that is part of, or depends on, a dependency cycle.
+// Those analyzers would otherwise wait for each other forever.
+func (g *analyzerGraph) markCycles() {
+	remaining := make([]int, len(g.metas))
+	dependents := make([][]int, len(g.metas))
+	for i, deps := range g.deps {
+		remaining[i] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	ready := []int{}
+	for i := range g.metas {
+		if remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		for _, dependent := range dependents[i] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	for i := range g.metas {
+		if remaining[i] > 0 {
+			g.errs[i] = errors.New("analyzer is part of a dependency cycle")
+		}
+	}
+}
+
+// skipReason returns why the analyzer at index i should be skipped given the results of the
+// analyzers it depends on, or an empty string if it should run.
+func (g *analyzerGraph) skipReason(i int, results [][]*AnalyzeResult, errs []error) (string, error) {
+	if len(g.deps[i]) == 0 {
+		return "", nil
+	}
+
+	runIf := defaultRunIf
+	if meta := g.metas[i]; meta != nil && meta.RunIf != "" {
+		runIf = meta.RunIf
+	}
+
+	for _, dep := range g.deps[i] {
+		outcome := dependencyOutcome(results[dep], errs[dep])
+		vars := map[string]interface{}{
+			"pass":    outcome == "pass",
+			"warn":    outcome == "warn",
+			"fail":    outcome == "fail",
+			"error":   outcome == "error",
+			"skipped": outcome == "skipped",
+		}
+
+		shouldRun, err := expression.Evaluate(runIf, expression.MapResolver(vars))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to evaluate runIf")
+		}
+		if !shouldRun {
+			checkName := ""
+			if meta := g.metas[dep]; meta != nil {
+				checkName = meta.CheckName
+			}
+			return fmt.Sprintf("Skipped because %q resulted in %s, which does not satisfy %q", checkName, outcome, runIf), nil
+		}
+	}
+
+	return "", nil
+}
+
+// dependencyOutcome summarizes the results of an analyzer into a single outcome. An analyzer that
+// produced several results is only considered passing if all of them passed.
+func dependencyOutcome(results []*AnalyzeResult, err error) string {
+	if err != nil {
+		return "error"
+	}
+
+	outcome := "skipped"
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		switch {
+		case result.IsFail:
+			return "fail"
+		case result.IsError:
+			outcome = "error"
+		case result.IsWarn:
+			if outcome == "error" {
+				continue
+			}
+			outcome = "warn"
+		case result.IsPass:
+			if outcome == "skipped" {
+				outcome = "pass"
+			}
+		}
+	}
+	return outcome
+}
+
+func skippedAnalyzerResult(analyzer *troubleshootv1beta2.Analyze, reason string) ([]*AnalyzeResult, error) {
+	analyzerInst, ok := GetAnalyzer(analyzer)
+	if !ok {
+		return nil, errors.New("invalid analyzer")
+	}
+
+	isExcluded, err := analyzerInst.IsExcluded()
+	if err != nil {
+		return nil, err
+	}
+	if isExcluded {
+		return nil, nil
+	}
+
+	return []*AnalyzeResult{
+		{
+			IsSkipped: true,
+			Title:     analyzerInst.Title(),
+			Message:   reason,
+		},
+	}, nil
+}
+
+func skippedHostAnalyzerResult(hostAnalyzer *troubleshootv1beta2.HostAnalyze, reason string) []*AnalyzeResult {
+	analyzer, ok := GetHostAnalyzer(hostAnalyzer)
+	if !ok {
+		return NewAnalyzeResultError(analyzer, errors.New("invalid host analyzer"))
+	}
+
+	isExcluded, _ := analyzer.IsExcluded()
+	if isExcluded {
+		return nil
+	}
+
+	return []*AnalyzeResult{
+		{
+			IsSkipped: true,
+			Title:     analyzer.Title(),
+			Message:   reason,
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnalyzeAllDependencies(t *testing.T) {
+	textAnalyzer := func(checkName string, regex string, dependsOn []string, runIf string) *troubleshootv1beta2.Analyze {
+		return &troubleshootv1beta2.Analyze{
+			TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: checkName,
+					DependsOn: dependsOn,
+					RunIf:     runIf,
+				},
+				FileName:     "status.txt",
+				RegexPattern: regex,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "pass"}},
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "fail"}},
+				},
+			},
+		}
+	}
+
+	getFile := func(n string) ([]byte, error) {
+		return nil, nil
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		return map[string][]byte{n: []byte("status: ready")}, nil
+	}
+
+	analyzers := []*troubleshootv1beta2.Analyze{
+		// declared before its dependency to show that order in the spec does not matter
+		textAnalyzer("runs after crd", "ready", []string{"crd"}, ""),
+		textAnalyzer("crd", "ready", nil, ""),
+		textAnalyzer("not ready", "not ready", nil, ""),
+		textAnalyzer("skipped, dependency failed", "ready", []string{"not ready"}, ""),
+		textAnalyzer("runs, dependency failed", "ready", []string{"not ready"}, "fail || warn"),
+		textAnalyzer("skipped, dependency skipped", "ready", []string{"skipped, dependency failed"}, "pass"),
+		textAnalyzer("unknown dependency", "ready", []string{"does not exist"}, ""),
+		textAnalyzer("cycle a", "ready", []string{"cycle b"}, ""),
+		textAnalyzer("cycle b", "ready", []string{"cycle a"}, ""),
+		textAnalyzer("bad runIf", "ready", []string{"crd"}, "pass &&"),
+	}
+
+	results, errs := AnalyzeAll(analyzers, getFile, findFiles, 2)
+
+	assertResult := func(i int, isPass, isFail, isSkipped bool) {
+		require.NoError(t, errs[i], analyzers[i].TextAnalyze.CheckName)
+		require.Len(t, results[i], 1, analyzers[i].TextAnalyze.CheckName)
+		assert.Equal(t, analyzers[i].TextAnalyze.CheckName, results[i][0].Title)
+		assert.Equal(t, isPass, results[i][0].IsPass, analyzers[i].TextAnalyze.CheckName)
+		assert.Equal(t, isFail, results[i][0].IsFail, analyzers[i].TextAnalyze.CheckName)
+		assert.Equal(t, isSkipped, results[i][0].IsSkipped, analyzers[i].TextAnalyze.CheckName)
+	}
+
+	assertResult(0, true, false, false)
+	assertResult(1, true, false, false)
+	assertResult(2, false, true, false)
+	assertResult(3, false, false, true)
+	assert.Equal(t, `Skipped because "not ready" resulted in fail, which does not satisfy "pass"`, results[3][0].Message)
+	assertResult(4, true, false, false)
+	assertResult(5, false, false, true)
+
+	assert.EqualError(t, errs[6], `analyzer depends on unknown analyzer "does not exist"`)
+	assert.EqualError(t, errs[7], "analyzer is part of a dependency cycle")
+	assert.EqualError(t, errs[8], "analyzer is part of a dependency cycle")
+	assert.Error(t, errs[9])
+}
+
+func Test_HostAnalyzeAllDependencies(t *testing.T) {
+	cpuAnalyzer := func(checkName string, when string, dependsOn []string, runIf string) *troubleshootv1beta2.HostAnalyze {
+		return &troubleshootv1beta2.HostAnalyze{
+			CPU: &troubleshootv1beta2.CPUAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: checkName,
+					DependsOn: dependsOn,
+					RunIf:     runIf,
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: when, Message: "fail"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "pass"}},
+				},
+			},
+		}
+	}
+
+	cpuInfo, err := json.Marshal(collect.CPUInfo{LogicalCount: 4, PhysicalCount: 2})
+	require.NoError(t, err)
+	getFile := func(n string) ([]byte, error) {
+		return cpuInfo, nil
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		return nil, nil
+	}
+
+	hostAnalyzers := []*troubleshootv1beta2.HostAnalyze{
+		// declared before its dependency to show that order in the spec does not matter
+		cpuAnalyzer("runs after cpu", "logical < 2", []string{"cpu"}, ""),
+		cpuAnalyzer("cpu", "logical < 2", nil, ""),
+		cpuAnalyzer("too few cpus", "logical < 8", nil, ""),
+		cpuAnalyzer("skipped, dependency failed", "logical < 2", []string{"too few cpus"}, ""),
+		cpuAnalyzer("runs, dependency failed", "logical < 2", []string{"too few cpus"}, "fail"),
+		cpuAnalyzer("unknown dependency", "logical < 2", []string{"does not exist"}, ""),
+		cpuAnalyzer("cycle a", "logical < 2", []string{"cycle b"}, ""),
+		cpuAnalyzer("cycle b", "logical < 2", []string{"cycle a"}, ""),
+	}
+
+	results := HostAnalyzeAll(hostAnalyzers, getFile, findFiles)
+	require.Len(t, results, len(hostAnalyzers))
+
+	assertResult := func(i int, isPass, isFail, isSkipped, isError bool) {
+		checkName := hostAnalyzers[i].CPU.CheckName
+		require.Len(t, results[i], 1, checkName)
+		assert.Equal(t, checkName, results[i][0].Title)
+		assert.Equal(t, isPass, results[i][0].IsPass, checkName)
+		assert.Equal(t, isFail, results[i][0].IsFail, checkName)
+		assert.Equal(t, isSkipped, results[i][0].IsSkipped, checkName)
+		assert.Equal(t, isError, results[i][0].IsError, checkName)
+	}
+
+	assertResult(0, true, false, false, false)
+	assertResult(1, true, false, false, false)
+	assertResult(2, false, true, false, false)
+	assertResult(3, false, false, true, false)
+	assert.Equal(t, `Skipped because "too few cpus" resulted in fail, which does not satisfy "pass"`, results[3][0].Message)
+	assertResult(4, true, false, false, false)
+	assertResult(5, false, false, false, true)
+	assert.Equal(t, `analyzer depends on unknown analyzer "does not exist"`, results[5][0].Error)
+	assertResult(6, false, false, false, true)
+	assertResult(7, false, false, false, true)
+}
@@ -0,0 +1,476 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// deprecatedAPI is an API that is deprecated in, and eventually removed from, Kubernetes.
+type deprecatedAPI struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string
+}
+
+// deprecatedAPIs are the deprecations and removals of built-in APIs, from
+// https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedAPIs = []deprecatedAPI{
+	{"extensions/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "1.10", "1.16", "policy/v1beta1"},
+
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"authentication.k8s.io/v1beta1", "TokenReview", "1.19", "1.22", "authentication.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "LocalSubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "SelfSubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "SubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"extensions/v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "1.17", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "1.19", "1.22", "storage.k8s.io/v1"},
+
+	{"batch/v1beta1", "CronJob", "1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "1.19", "1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.23", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "1.21", "1.25", ""},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "1.20", "1.25", "node.k8s.io/v1"},
+
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling/v2"},
+
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io/v1"},
+
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// deprecatedAPIsCollectedFiles are the collected objects that are checked for deprecated APIs.
+var deprecatedAPIsCollectedFiles = []string{
+	"cluster-resources/*.json",
+	"cluster-resources/*/*.json",
+	"cluster-resources/custom-resources/*.yaml",
+	"cluster-resources/custom-resources/*/*.yaml",
+}
+
+type AnalyzeDeprecatedApis struct {
+	analyzer *troubleshootv1beta2.DeprecatedApis
+}
+
+func (a *AnalyzeDeprecatedApis) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Deprecated APIs")
+}
+
+func (a *AnalyzeDeprecatedApis) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeDeprecatedApis) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeDeprecatedApis(a.analyzer, a.Title(), getFile, findFiles)
+}
+
+// deprecatedAPIState is what outcomes of the deprecatedApis analyzer are evaluated against, and what
+// their messages are templated with. Objects are the objects and manifests that use the API.
+type deprecatedAPIState struct {
+	APIVersion    string
+	Kind          string
+	DeprecatedIn  string
+	RemovedIn     string
+	Replacement   string
+	TargetVersion string
+	Removed       bool
+	Objects       []string
+}
+
+func (s deprecatedAPIState) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion":   s.APIVersion,
+		"kind":         s.Kind,
+		"deprecatedIn": s.DeprecatedIn,
+		"removedIn":    s.RemovedIn,
+		"replacement":  s.Replacement,
+		"deprecated":   s.APIVersion != "",
+		"removed":      s.Removed,
+		"count":        len(s.Objects),
+	}
+}
+
+func analyzeDeprecatedApis(analyzer *troubleshootv1beta2.DeprecatedApis, title string, getCollectedFileContents func(string) ([]byte, error), getChildCollectedFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	targetVersion, err := deprecatedAPIsTargetVersion(analyzer.TargetVersion, getCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]*deprecatedAPIState{}
+	addObject := func(api deprecatedAPI, removed bool, object string) {
+		key := fmt.Sprintf("%s/%s", api.APIVersion, api.Kind)
+		state, ok := states[key]
+		if !ok {
+			state = &deprecatedAPIState{
+				APIVersion:    api.APIVersion,
+				Kind:          api.Kind,
+				DeprecatedIn:  api.DeprecatedIn,
+				RemovedIn:     api.RemovedIn,
+				Replacement:   api.Replacement,
+				TargetVersion: fmt.Sprintf("%d.%d", targetVersion.Major, targetVersion.Minor),
+				Removed:       removed,
+			}
+			states[key] = state
+		}
+		for _, existing := range state.Objects {
+			if existing == object {
+				return
+			}
+		}
+		state.Objects = append(state.Objects, object)
+	}
+
+	addObjects := func(fileName string, contents []byte, isManifest bool) error {
+		objects, err := decodeCollectedObjects(contents)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %s", fileName)
+		}
+		for _, object := range objects {
+			for _, typeMeta := range objectAPIVersions(object) {
+				api, removed, ok := findDeprecatedAPI(typeMeta[0], typeMeta[1], targetVersion)
+				if !ok {
+					continue
+				}
+				name := objectDisplayName(object)
+				if isManifest {
+					name = fmt.Sprintf("%s in %s", name, fileName)
+				}
+				addObject(api, removed, name)
+			}
+		}
+		return nil
+	}
+
+	for _, pattern := range deprecatedAPIsCollectedFiles {
+		files, err := getChildCollectedFileContents(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find collected files matching %s", pattern)
+		}
+		for fileName, contents := range files {
+			if strings.HasSuffix(fileName, "-errors.json") {
+				continue
+			}
+			if err := addObjects(fileName, contents, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, pattern := range analyzer.Manifests {
+		files, err := getChildCollectedFileContents(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find collected files matching %s", pattern)
+		}
+		for fileName, contents := range files {
+			if err := addObjects(fileName, contents, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	crdAPIs, err := deprecatedCustomResourceVersions(getCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+	for _, crdAPI := range crdAPIs {
+		addObject(crdAPI.deprecatedAPI, false, crdAPI.crdName)
+	}
+
+	keys := []string{}
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := []*AnalyzeResult{}
+	for _, key := range keys {
+		state := states[key]
+		sort.Strings(state.Objects)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = deprecatedAPIStatus(analyzer.Outcomes, fmt.Sprintf("%s %s", state.Kind, state.APIVersion), *state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process deprecated api")
+			}
+		} else {
+			result = getDefaultDeprecatedAPIResult(*state)
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	if len(states) == 0 && len(analyzer.Outcomes) > 0 {
+		// there's nothing deprecated in use, but the outcomes can still report that
+		result, err := deprecatedAPIStatus(analyzer.Outcomes, title, deprecatedAPIState{
+			TargetVersion: fmt.Sprintf("%d.%d", targetVersion.Major, targetVersion.Minor),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process deprecated apis")
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// deprecatedAPIsTargetVersion parses the version of Kubernetes to check against, which defaults to
+// the version of the cluster the bundle was collected from.
+func deprecatedAPIsTargetVersion(version string, getCollectedFileContents func(string) ([]byte, error)) (semver.Version, error) {
+	if version == "" {
+		clusterInfo, err := getCollectedFileContents("cluster-info/cluster_version.json")
+		if err != nil {
+			return semver.Version{}, errors.Wrap(err, "failed to get contents of cluster_version.json")
+		}
+
+		collectorClusterVersion := collect.ClusterVersion{}
+		if err := json.Unmarshal(clusterInfo, &collectorClusterVersion); err != nil {
+			return semver.Version{}, errors.Wrap(err, "failed to parse cluster_version.json")
+		}
+		version = collectorClusterVersion.String
+	}
+
+	targetVersion, err := semver.ParseTolerant(version)
+	if err != nil {
+		return semver.Version{}, errors.Wrapf(err, "failed to parse target version %s", version)
+	}
+	return targetVersion, nil
+}
+
+// findDeprecatedAPI returns the deprecation of the API, if it is deprecated in the target version.
+// The second return value is true if the API is removed in the target version.
+func findDeprecatedAPI(apiVersion string, kind string, targetVersion semver.Version) (deprecatedAPI, bool, bool) {
+	for _, api := range deprecatedAPIs {
+		if api.APIVersion != apiVersion || api.Kind != kind {
+			continue
+		}
+
+		deprecatedIn := semver.MustParse(api.DeprecatedIn + ".0")
+		removedIn := semver.MustParse(api.RemovedIn + ".0")
+		if compareMinorVersions(targetVersion, deprecatedIn) < 0 {
+			return deprecatedAPI{}, false, false
+		}
+		return api, compareMinorVersions(targetVersion, removedIn) >= 0, true
+	}
+	return deprecatedAPI{}, false, false
+}
+
+// compareMinorVersions compares versions ignoring their patch versions and pre-release tags, so that
+// e.g. 1.25.0-rc.1 is considered 1.25.
+func compareMinorVersions(a semver.Version, b semver.Version) int {
+	return semver.Version{Major: a.Major, Minor: a.Minor}.Compare(semver.Version{Major: b.Major, Minor: b.Minor})
+}
+
+// decodeCollectedObjects decodes the objects in a collected file or manifest, which may be a
+// list, an array of objects, or multiple YAML documents.
+func decodeCollectedObjects(contents []byte) ([]map[string]interface{}, error) {
+	objects := []map[string]interface{}{}
+
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(contents), 4096)
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		items := []interface{}{document}
+		if list, ok := document.([]interface{}); ok {
+			items = list
+		} else if object, ok := document.(map[string]interface{}); ok {
+			if list, ok := object["items"].([]interface{}); ok {
+				items = list
+			}
+		}
+
+		for _, item := range items {
+			if object, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// objectAPIVersions returns the apiVersion and kind of the object, and those the object was last
+// applied with by kubectl, which are what its owner still uses even though the object is returned
+// in the preferred version when collected.
+func objectAPIVersions(object map[string]interface{}) [][2]string {
+	typeMetas := [][2]string{}
+
+	apiVersion, _ := object["apiVersion"].(string)
+	kind, _ := object["kind"].(string)
+	if apiVersion != "" && kind != "" {
+		typeMetas = append(typeMetas, [2]string{apiVersion, kind})
+	}
+
+	metadata, _ := object["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	lastApplied, _ := annotations["kubectl.kubernetes.io/last-applied-configuration"].(string)
+	if lastApplied != "" {
+		applied := struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}{}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil && applied.APIVersion != "" {
+			typeMetas = append(typeMetas, [2]string{applied.APIVersion, applied.Kind})
+		}
+	}
+
+	return typeMetas
+}
+
+func objectDisplayName(object map[string]interface{}) string {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	if namespace != "" {
+		return fmt.Sprintf("%s/%s", namespace, name)
+	}
+	return name
+}
+
+type deprecatedCustomResourceVersion struct {
+	deprecatedAPI
+	crdName string
+}
+
+// deprecatedCustomResourceVersions returns the versions of custom resources that their CRDs mark as
+// deprecated and that objects are still stored in.
+func deprecatedCustomResourceVersions(getCollectedFileContents func(string) ([]byte, error)) ([]deprecatedCustomResourceVersion, error) {
+	contents, err := getCollectedFileContents("cluster-resources/custom-resource-definitions.json")
+	if err != nil {
+		// crds may not have been collected
+		return nil, nil
+	}
+
+	var crds apiextensionsv1.CustomResourceDefinitionList
+	if err := json.Unmarshal(contents, &crds); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal custom resource definitions")
+	}
+
+	versions := []deprecatedCustomResourceVersion{}
+	for _, crd := range crds.Items {
+		stored := map[string]bool{}
+		for _, version := range crd.Status.StoredVersions {
+			stored[version] = true
+		}
+
+		for _, version := range crd.Spec.Versions {
+			if !version.Served || !version.Deprecated || !stored[version.Name] {
+				continue
+			}
+
+			replacement := ""
+			for _, other := range crd.Spec.Versions {
+				if other.Storage && other.Name != version.Name {
+					replacement = fmt.Sprintf("%s/%s", crd.Spec.Group, other.Name)
+				}
+			}
+
+			versions = append(versions, deprecatedCustomResourceVersion{
+				deprecatedAPI: deprecatedAPI{
+					APIVersion:  fmt.Sprintf("%s/%s", crd.Spec.Group, version.Name),
+					Kind:        crd.Spec.Names.Kind,
+					Replacement: replacement,
+				},
+				crdName: crd.Name,
+			})
+		}
+	}
+
+	return versions, nil
+}
+
+func deprecatedAPIStatus(outcomes []*troubleshootv1beta2.Outcome, title string, state deprecatedAPIState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, title, "kubernetes_cluster_version", "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16", compareWhen, state)
+}
+
+func getDefaultDeprecatedAPIResult(state deprecatedAPIState) *AnalyzeResult {
+	objects := state.Objects
+	if len(objects) > 5 {
+		objects = append(objects[:5:5], fmt.Sprintf("%d more", len(state.Objects)-5))
+	}
+
+	result := &AnalyzeResult{
+		Title:   fmt.Sprintf("%s %s", state.Kind, state.APIVersion),
+		IconKey: "kubernetes_cluster_version",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+	}
+
+	var message string
+	switch {
+	case state.Removed:
+		result.IsFail = true
+		message = fmt.Sprintf("%s %s is removed in Kubernetes %s", state.Kind, state.APIVersion, state.RemovedIn)
+	case state.RemovedIn != "":
+		result.IsWarn = true
+		message = fmt.Sprintf("%s %s is deprecated and will be removed in Kubernetes %s", state.Kind, state.APIVersion, state.RemovedIn)
+	default:
+		result.IsWarn = true
+		message = fmt.Sprintf("%s %s is deprecated", state.Kind, state.APIVersion)
+	}
+	if state.Replacement != "" {
+		message = fmt.Sprintf("%s, use %s instead", message, state.Replacement)
+	}
+	result.Message = fmt.Sprintf("%s. It is used by %s", message, strings.Join(objects, ", "))
+
+	return result
+}
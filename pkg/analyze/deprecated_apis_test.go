@@ -0,0 +1,273 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_analyzeDeprecatedApis(t *testing.T) {
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.DeprecatedApis
+		files        map[string][]byte
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name: "removed in target version",
+			analyzer: troubleshootv1beta2.DeprecatedApis{
+				TargetVersion: "1.25",
+				Manifests:     []string{"helm/*/manifest.yaml"},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "CronJob batch/v1beta1",
+					Message: "CronJob batch/v1beta1 is removed in Kubernetes 1.25, use batch/v1 instead. It is used by default/cleanup",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+				{
+					IsFail:  true,
+					Title:   "Ingress extensions/v1beta1",
+					Message: "Ingress extensions/v1beta1 is removed in Kubernetes 1.22, use networking.k8s.io/v1 instead. It is used by default/web",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+				{
+					IsFail:  true,
+					Title:   "PodDisruptionBudget policy/v1beta1",
+					Message: "PodDisruptionBudget policy/v1beta1 is removed in Kubernetes 1.25, use policy/v1 instead. It is used by app in helm/app/manifest.yaml",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+				{
+					IsWarn:  true,
+					Title:   "Widget example.com/v1alpha1",
+					Message: "Widget example.com/v1alpha1 is deprecated, use example.com/v1 instead. It is used by widgets.example.com",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+			},
+		},
+		{
+			name: "deprecated in cluster version",
+			analyzer: troubleshootv1beta2.DeprecatedApis{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "removed",
+							Message: "{{ .Kind }} {{ .APIVersion }} can't be used in {{ .TargetVersion }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    `removedIn != ""`,
+							Message: "{{ .Kind }} {{ .APIVersion }} is removed in {{ .RemovedIn }}",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "CronJob batch/v1beta1",
+					Message: "CronJob batch/v1beta1 is removed in 1.25",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+				{
+					IsFail:  true,
+					Title:   "Ingress extensions/v1beta1",
+					Message: "Ingress extensions/v1beta1 can't be used in 1.24",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+			},
+		},
+		{
+			name: "nothing deprecated",
+			analyzer: troubleshootv1beta2.DeprecatedApis{
+				TargetVersion: "v1.13.2",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count > 0",
+							Message: "{{ .Kind }} {{ .APIVersion }} is deprecated",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "No deprecated APIs are used in {{ .TargetVersion }}",
+						},
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/ingress/default.json": []byte(deprecatedApisIngresses),
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "Deprecated APIs",
+					Message: "No deprecated APIs are used in 1.13",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+			},
+		},
+		{
+			name:     "cluster version not collected",
+			analyzer: troubleshootv1beta2.DeprecatedApis{},
+			files:    map[string][]byte{},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			files := test.files
+			if files == nil {
+				files = map[string][]byte{
+					"cluster-info/cluster_version.json":                                   []byte(`{"info": {"gitVersion": "v1.24.3"}, "string": "v1.24.3"}`),
+					"cluster-resources/ingress/default.json":                              []byte(deprecatedApisIngresses),
+					"cluster-resources/cronjobs/default.json":                             []byte(deprecatedApisCronJobs),
+					"cluster-resources/cronjobs-errors.json":                              []byte(`["the server could not find the requested resource"]`),
+					"cluster-resources/custom-resource-definitions.json":                  []byte(deprecatedApisCRDs),
+					"cluster-resources/custom-resources/widgets.example.com/default.yaml": []byte(deprecatedApisWidgets),
+					"helm/app/manifest.yaml":                                              []byte(deprecatedApisManifest),
+				}
+			}
+			getFile := func(n string) ([]byte, error) {
+				if contents, ok := files[n]; ok {
+					return contents, nil
+				}
+				return nil, errors.Errorf("%s not found", n)
+			}
+			getFiles := func(n string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, file := range files {
+					if matched, _ := filepath.Match(n, name); matched {
+						matching[name] = file
+					}
+				}
+				return matching, nil
+			}
+
+			a := AnalyzeDeprecatedApis{analyzer: &test.analyzer}
+			actual, err := analyzeDeprecatedApis(&test.analyzer, a.Title(), getFile, getFiles)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var deprecatedApisIngresses = `{
+  "kind": "IngressList",
+  "apiVersion": "networking.k8s.io/v1",
+  "items": [
+    {
+      "kind": "Ingress",
+      "apiVersion": "networking.k8s.io/v1",
+      "metadata": {
+        "name": "web",
+        "namespace": "default",
+        "annotations": {
+          "kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"extensions/v1beta1\",\"kind\":\"Ingress\",\"metadata\":{\"name\":\"web\",\"namespace\":\"default\"}}\n"
+        }
+      }
+    },
+    {
+      "kind": "Ingress",
+      "apiVersion": "networking.k8s.io/v1",
+      "metadata": {"name": "api", "namespace": "default"}
+    }
+  ]
+}`
+
+var deprecatedApisCronJobs = `{
+  "kind": "CronJobList",
+  "apiVersion": "batch/v1beta1",
+  "items": [
+    {
+      "kind": "CronJob",
+      "apiVersion": "batch/v1beta1",
+      "metadata": {"name": "cleanup", "namespace": "default"}
+    }
+  ]
+}`
+
+var deprecatedApisCRDs = `{
+  "kind": "CustomResourceDefinitionList",
+  "apiVersion": "apiextensions.k8s.io/v1",
+  "items": [
+    {
+      "kind": "CustomResourceDefinition",
+      "apiVersion": "apiextensions.k8s.io/v1",
+      "metadata": {"name": "widgets.example.com"},
+      "spec": {
+        "group": "example.com",
+        "names": {"kind": "Widget", "plural": "widgets"},
+        "scope": "Namespaced",
+        "versions": [
+          {"name": "v1alpha1", "served": true, "storage": false, "deprecated": true},
+          {"name": "v1", "served": true, "storage": true}
+        ]
+      },
+      "status": {"storedVersions": ["v1alpha1", "v1"]}
+    },
+    {
+      "kind": "CustomResourceDefinition",
+      "apiVersion": "apiextensions.k8s.io/v1",
+      "metadata": {"name": "gadgets.example.com"},
+      "spec": {
+        "group": "example.com",
+        "names": {"kind": "Gadget", "plural": "gadgets"},
+        "scope": "Namespaced",
+        "versions": [
+          {"name": "v1beta1", "served": true, "storage": false, "deprecated": true},
+          {"name": "v1", "served": true, "storage": true}
+        ]
+      },
+      "status": {"storedVersions": ["v1"]}
+    }
+  ]
+}`
+
+var deprecatedApisWidgets = `- apiVersion: example.com/v1
+  kind: Widget
+  metadata:
+    name: small
+    namespace: default
+`
+
+var deprecatedApisManifest = `---
+# Source: app/templates/pdb.yaml
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: app
+spec:
+  minAvailable: 1
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`
@@ -11,6 +11,31 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+type AnalyzeDistribution struct {
+	analyzer *troubleshootv1beta2.Distribution
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeDistribution) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Kubernetes Distribution")
+}
+
+func (a *AnalyzeDistribution) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeDistribution) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeDistribution) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeDistribution(a.analyzer, getFile, a.objects)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 type providers struct {
 	microk8s      bool
 	dockerDesktop bool
@@ -135,16 +160,11 @@ func ParseNodesForProviders(nodes []corev1.Node) (providers, string) {
 	return foundProviders, stringProvider
 }
 
-func analyzeDistribution(analyzer *troubleshootv1beta2.Distribution, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
+func analyzeDistribution(analyzer *troubleshootv1beta2.Distribution, getCollectedFileContents func(string) ([]byte, error), objects *collectedFileCache) (*AnalyzeResult, error) {
 	var unknownDistribution string
-	collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
-	}
-
-	var nodes corev1.NodeList
-	if err := json.Unmarshal(collected, &nodes); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal node list")
+		return nil, err
 	}
 
 	foundProviders, _ := ParseNodesForProviders(nodes.Items)
@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// dnsMaxSearchDomains is the number of search domains that glibc before 2.26 and musl use, any
+// others in resolv.conf are ignored
+const dnsMaxSearchDomains = 6
+
+type AnalyzeDNS struct {
+	analyzer *troubleshootv1beta2.DNSAnalyze
+}
+
+func (a *AnalyzeDNS) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "DNS")
+}
+
+func (a *AnalyzeDNS) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeDNS) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeDNS(a.analyzer, findFiles)
+}
+
+func analyzeDNS(analyzer *troubleshootv1beta2.DNSAnalyze, findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	pathPrefix := collect.GetDNSCollectorFilepath(analyzer.CollectorName)
+
+	var dnsResult *collect.DNSResult
+	resultFiles, err := findFiles(path.Join(pathPrefix, "result.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find dns result")
+	}
+	for name, file := range resultFiles {
+		dnsResult = &collect.DNSResult{}
+		if err := json.Unmarshal(file, dnsResult); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal dns result from %s", name)
+		}
+	}
+
+	var serverPods []collect.DNSServerPod
+	podFiles, err := findFiles(path.Join(pathPrefix, "coredns-pods.json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find coredns pods")
+	}
+	for name, file := range podFiles {
+		serverPods = []collect.DNSServerPod{}
+		if err := json.Unmarshal(file, &serverPods); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal coredns pods from %s", name)
+		}
+	}
+
+	if dnsResult == nil && serverPods == nil {
+		return nil, nil
+	}
+
+	results := []*AnalyzeResult{}
+
+	if dnsResult != nil {
+		results = append(results, analyzeDNSQueries(dnsResult)...)
+
+		if result := analyzeDNSSearchPath(dnsResult); result != nil {
+			results = append(results, result)
+		}
+	} else {
+		results = append(results, &AnalyzeResult{
+			Title:   "DNS Resolution",
+			IsWarn:  true,
+			Message: "DNS resolution was not tested, the pod resolving names did not complete",
+		})
+	}
+
+	if result := analyzeDNSServerPods(serverPods); result != nil {
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		results = append(results, &AnalyzeResult{
+			Title:   analyzerTitleOrDefault(analyzer.AnalyzeMeta, "DNS"),
+			IsPass:  true,
+			Message: "No issues detected in DNS resolution",
+		})
+	}
+
+	return results, nil
+}
+
+// analyzeDNSQueries fails for every name that could not be resolved
+func analyzeDNSQueries(dnsResult *collect.DNSResult) []*AnalyzeResult {
+	results := []*AnalyzeResult{}
+	for _, query := range dnsResult.Queries {
+		result := &AnalyzeResult{
+			Title:  "DNS Resolution",
+			IsFail: true,
+		}
+
+		switch query.Status {
+		case "NOERROR":
+			if len(query.Answers) > 0 {
+				continue
+			}
+			result.IsFail = false
+			result.IsWarn = true
+			result.Message = fmt.Sprintf("%s was resolved, but has no records", query.Name)
+		case "NXDOMAIN":
+			result.Message = fmt.Sprintf("%s does not exist (NXDOMAIN)", query.Name)
+		case "TIMEOUT":
+			result.Message = fmt.Sprintf("Resolving %s timed out, no nameserver in %s answered", query.Name, strings.Join(dnsResult.Nameservers, ", "))
+		case "":
+			result.Message = fmt.Sprintf("%s could not be resolved: %s", query.Name, query.Error)
+		default:
+			result.Message = fmt.Sprintf("%s could not be resolved: %s", query.Name, query.Status)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// analyzeDNSSearchPath warns when names are only resolved as is after being tried with each of the
+// search domains, which multiplies the load on the nameservers and the latency of every lookup
+func analyzeDNSSearchPath(dnsResult *collect.DNSResult) *AnalyzeResult {
+	if len(dnsResult.Search) > dnsMaxSearchDomains {
+		return &AnalyzeResult{
+			Title:   "DNS Search Path",
+			IsWarn:  true,
+			Message: fmt.Sprintf("resolv.conf has %d search domains, only the first %d are used by most resolvers", len(dnsResult.Search), dnsMaxSearchDomains),
+		}
+	}
+
+	for _, query := range dnsResult.Queries {
+		if query.Status != "NOERROR" || query.Queries <= 1 || strings.TrimSuffix(query.AnsweredName, ".") != query.Name {
+			continue
+		}
+
+		return &AnalyzeResult{
+			Title:   "DNS Search Path",
+			IsWarn:  true,
+			Message: fmt.Sprintf("Resolving %s took %d queries, names with fewer than %d dots are tried with each of the %d search domains first", query.Name, query.Queries, dnsResult.Ndots, len(dnsResult.Search)),
+		}
+	}
+
+	return nil
+}
+
+func analyzeDNSServerPods(serverPods []collect.DNSServerPod) *AnalyzeResult {
+	if serverPods == nil {
+		return nil
+	}
+
+	if len(serverPods) == 0 {
+		return &AnalyzeResult{
+			Title:   "CoreDNS Pods",
+			IsWarn:  true,
+			Message: "No CoreDNS pods were found",
+		}
+	}
+
+	for _, pod := range serverPods {
+		if pod.Ready {
+			continue
+		}
+
+		reason := pod.Reason
+		if reason == "" {
+			reason = pod.Phase
+		}
+		return &AnalyzeResult{
+			Title:   "CoreDNS Pods",
+			IsWarn:  true,
+			Message: fmt.Sprintf("CoreDNS pod %s on %s is not ready: %s", pod.Name, pod.Node, reason),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNS(t *testing.T) {
+	healthyPods := `[
+  {"name": "coredns-6d4b75cb6d-8kq2x", "namespace": "kube-system", "node": "node-1", "phase": "Running", "ready": true, "restarts": 0},
+  {"name": "coredns-6d4b75cb6d-t7w9z", "namespace": "kube-system", "node": "node-2", "phase": "Running", "ready": true, "restarts": 0}
+]`
+
+	tests := []struct {
+		name     string
+		analyzer troubleshootv1beta2.DNSAnalyze
+		files    map[string]string
+		expect   []*AnalyzeResult
+	}{
+		{
+			name:     "no issues",
+			analyzer: troubleshootv1beta2.DNSAnalyze{},
+			files: map[string]string{
+				"dns/result.json": `{
+  "nameservers": ["10.96.0.10"],
+  "search": ["default.svc.cluster.local", "svc.cluster.local", "cluster.local"],
+  "ndots": 5,
+  "queries": [
+    {"name": "kubernetes.default", "status": "NOERROR", "answeredName": "kubernetes.default.svc.cluster.local", "answers": ["A 10.96.0.1"], "queries": 2, "latencyMilliseconds": 3},
+    {"name": "api", "status": "NOERROR", "answeredName": "api.default.svc.cluster.local", "answers": ["A 10.96.0.12"], "queries": 5, "latencyMilliseconds": 4012}
+  ]
+}`,
+				"dns/coredns-pods.json": healthyPods,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "DNS",
+					IsPass:  true,
+					Message: "No issues detected in DNS resolution",
+				},
+			},
+		},
+		{
+			name:     "nxdomain, timeout and search path",
+			analyzer: troubleshootv1beta2.DNSAnalyze{CollectorName: "network"},
+			files: map[string]string{
+				"network/dns/result.json": `{
+  "nameservers": ["10.96.0.10"],
+  "search": ["default.svc.cluster.local", "svc.cluster.local", "cluster.local"],
+  "ndots": 5,
+  "queries": [
+    {"name": "api.default.svc", "status": "NXDOMAIN", "answers": [], "queries": 4, "latencyMilliseconds": 4},
+    {"name": "db.internal", "status": "TIMEOUT", "answers": [], "queries": 1},
+    {"name": "replicated.app", "status": "NOERROR", "answeredName": "replicated.app", "answers": ["A 162.159.134.61"], "queries": 4, "latencyMilliseconds": 27}
+  ]
+}`,
+				"network/dns/coredns-pods.json": healthyPods,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "DNS Resolution",
+					IsFail:  true,
+					Message: "api.default.svc does not exist (NXDOMAIN)",
+				},
+				{
+					Title:   "DNS Resolution",
+					IsFail:  true,
+					Message: "Resolving db.internal timed out, no nameserver in 10.96.0.10 answered",
+				},
+				{
+					Title:   "DNS Search Path",
+					IsWarn:  true,
+					Message: "Resolving replicated.app took 4 queries, names with fewer than 5 dots are tried with each of the 3 search domains first",
+				},
+			},
+		},
+		{
+			name:     "too many search domains and coredns not ready",
+			analyzer: troubleshootv1beta2.DNSAnalyze{},
+			files: map[string]string{
+				"dns/result.json": `{
+  "nameservers": ["10.96.0.10"],
+  "search": ["default.svc.cluster.local", "svc.cluster.local", "cluster.local", "us-east-1.compute.internal", "corp.example.com", "example.com", "lab.example.com"],
+  "ndots": 5,
+  "queries": [
+    {"name": "kubernetes.default", "status": "NOERROR", "answeredName": "kubernetes.default.svc.cluster.local", "answers": ["A 10.96.0.1"], "queries": 2}
+  ]
+}`,
+				"dns/coredns-pods.json": `[
+  {"name": "coredns-6d4b75cb6d-8kq2x", "namespace": "kube-system", "node": "node-1", "phase": "Running", "ready": false, "restarts": 12, "reason": "CrashLoopBackOff"}
+]`,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "DNS Search Path",
+					IsWarn:  true,
+					Message: "resolv.conf has 7 search domains, only the first 6 are used by most resolvers",
+				},
+				{
+					Title:   "CoreDNS Pods",
+					IsWarn:  true,
+					Message: "CoreDNS pod coredns-6d4b75cb6d-8kq2x on node-1 is not ready: CrashLoopBackOff",
+				},
+			},
+		},
+		{
+			name:     "dns pod did not complete",
+			analyzer: troubleshootv1beta2.DNSAnalyze{},
+			files: map[string]string{
+				"dns/coredns-pods.json": healthyPods,
+			},
+			expect: []*AnalyzeResult{
+				{
+					Title:   "DNS Resolution",
+					IsWarn:  true,
+					Message: "DNS resolution was not tested, the pod resolving names did not complete",
+				},
+			},
+		},
+		{
+			name:     "not collected",
+			analyzer: troubleshootv1beta2.DNSAnalyze{},
+			files:    map[string]string{},
+			expect:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			findFiles := func(glob string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, contents := range test.files {
+					if ok, _ := filepath.Match(glob, name); ok {
+						matching[name] = []byte(contents)
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeDNS(&test.analyzer, findFiles)
+			req.NoError(err)
+
+			assert.Equal(t, test.expect, actual)
+		})
+	}
+}
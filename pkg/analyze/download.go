@@ -23,6 +23,12 @@ type fileContentProvider struct {
 
 // Analyze local will analyze a locally available (already downloaded) bundle
 func AnalyzeLocal(localBundlePath string, analyzers []*troubleshootv1beta2.Analyze) ([]*AnalyzeResult, error) {
+	return AnalyzeLocalWithConcurrency(localBundlePath, analyzers, 0)
+}
+
+// AnalyzeLocalWithConcurrency is AnalyzeLocal running at most concurrency analyzers at the same
+// time. A concurrency of 0 uses the number of CPUs.
+func AnalyzeLocalWithConcurrency(localBundlePath string, analyzers []*troubleshootv1beta2.Analyze, concurrency int) ([]*AnalyzeResult, error) {
 	rootDir, err := FindBundleRootDir(localBundlePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to find root dir")
@@ -30,12 +36,13 @@ func AnalyzeLocal(localBundlePath string, analyzers []*troubleshootv1beta2.Analy
 
 	fcp := fileContentProvider{rootDir: rootDir}
 
+	results, errs := AnalyzeAll(analyzers, fcp.getFileContents, fcp.getChildFileContents, AnalyzerConcurrencyOrDefault(concurrency))
+
 	analyzeResults := []*AnalyzeResult{}
-	for _, analyzer := range analyzers {
-		analyzeResult, err := Analyze(analyzer, fcp.getFileContents, fcp.getChildFileContents)
-		if err != nil {
-			logger.Printf("An analyzer failed to run: %v", err)
-			continue
+	for i, analyzeResult := range results {
+		if errs[i] != nil {
+			logger.Printf("An analyzer failed to run: %v", errs[i])
+			analyzeResult = NewAnalyzerErrorResult(analyzers[i], errs[i])
 		}
 
 		// Filter nil results to prevent panic
@@ -50,6 +57,12 @@ func AnalyzeLocal(localBundlePath string, analyzers []*troubleshootv1beta2.Analy
 }
 
 func DownloadAndAnalyze(bundleURL string, analyzersSpec string) ([]*AnalyzeResult, error) {
+	return DownloadAndAnalyzeWithConcurrency(bundleURL, analyzersSpec, 0)
+}
+
+// DownloadAndAnalyzeWithConcurrency is DownloadAndAnalyze running at most concurrency analyzers at
+// the same time. A concurrency of 0 uses the number of CPUs.
+func DownloadAndAnalyzeWithConcurrency(bundleURL string, analyzersSpec string, concurrency int) ([]*AnalyzeResult, error) {
 	tmpDir, err := ioutil.TempDir("", "troubleshoot-k8s")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create temp dir")
@@ -86,7 +99,7 @@ func DownloadAndAnalyze(bundleURL string, analyzersSpec string) ([]*AnalyzeResul
 		analyzers = parsedAnalyzers
 	}
 
-	return AnalyzeLocal(rootDir, analyzers)
+	return AnalyzeLocalWithConcurrency(rootDir, analyzers, concurrency)
 }
 
 func downloadTroubleshootBundle(bundleURL string, destDir string) error {
@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type AnalyzeEvents struct {
+	analyzer *troubleshootv1beta2.EventsAnalyze
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeEvents) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Events")
+}
+
+func (a *AnalyzeEvents) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeEvents) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeEvents) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeEvents(a.analyzer, a.Title(), findFiles, a.objects)
+}
+
+// eventGroup aggregates the matching events with the same reason for a single involved object.
+// Outcomes are evaluated once per group, and their messages are templated with it.
+type eventGroup struct {
+	InvolvedObject corev1.ObjectReference
+	Kind           string
+	Namespace      string
+	Name           string
+	Type           string
+	Reason         string
+	// Count is the number of times the events occurred, Events the number of event objects
+	Count    int
+	Events   int
+	LastSeen time.Time
+	// Message is the message of the most recent event
+	Message string
+	// MinutesSinceLastSeen is relative to getReferenceTime
+	MinutesSinceLastSeen int
+}
+
+func analyzeEvents(analyzer *troubleshootv1beta2.EventsAnalyze, title string, getFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	fileNames := make([]string, 0)
+	if analyzer.Namespace != "" {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "events", fmt.Sprintf("%s.json", analyzer.Namespace)))
+	}
+	for _, ns := range analyzer.Namespaces {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "events", fmt.Sprintf("%s.json", ns)))
+	}
+
+	if len(fileNames) == 0 {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "events", "*.json"))
+	}
+
+	var re *regexp.Regexp
+	if analyzer.RegexPattern != "" {
+		var err error
+		re, err = regexp.Compile(analyzer.RegexPattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile regex: %s", analyzer.RegexPattern)
+		}
+	}
+
+	reasons := analyzer.Reasons
+	if analyzer.Reason != "" {
+		reasons = append([]string{analyzer.Reason}, reasons...)
+	}
+
+	groups := map[string]*eventGroup{}
+	for _, fileName := range fileNames {
+		files, err := getFileContents(fileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected events from file")
+		}
+
+		for _, collected := range files {
+			var events corev1.EventList
+			if err := json.Unmarshal(collected, &events); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal event list")
+			}
+
+			for _, event := range events.Items {
+				if !eventMatches(analyzer, reasons, re, &event) {
+					continue
+				}
+
+				key := strings.Join([]string{event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name, event.Reason}, "/")
+				group, ok := groups[key]
+				if !ok {
+					group = &eventGroup{
+						InvolvedObject: event.InvolvedObject,
+						Kind:           event.InvolvedObject.Kind,
+						Namespace:      event.InvolvedObject.Namespace,
+						Name:           event.InvolvedObject.Name,
+						Type:           event.Type,
+						Reason:         event.Reason,
+					}
+					groups[key] = group
+				}
+
+				lastSeen := eventLastSeen(&event)
+				group.Count += eventCount(&event)
+				group.Events++
+				if group.Message == "" || !lastSeen.Before(group.LastSeen) {
+					group.LastSeen = lastSeen
+					group.Message = event.Message
+					group.Type = event.Type
+				}
+			}
+		}
+	}
+
+	now, err := getReferenceTime(objects, getFileContents)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(groups))
+	for key, group := range groups {
+		group.MinutesSinceLastSeen = int(now.Sub(group.LastSeen).Minutes())
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		if len(analyzer.Outcomes) == 0 {
+			return nil, nil
+		}
+
+		// nothing matched, outcomes can still pass on e.g. "count == 0"
+		result, err := eventsStatus(analyzer.Outcomes, title, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process events")
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, key := range keys {
+		group := groups[key]
+		groupTitle := fmt.Sprintf("%s %s/%s Events", group.Kind, group.Namespace, group.Name)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			var err error
+			result, err = eventsStatus(analyzer.Outcomes, groupTitle, group)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process events")
+			}
+		} else {
+			result = getDefaultEventsResult(groupTitle, group)
+		}
+
+		if result != nil {
+			involvedObject := group.InvolvedObject
+			result.InvolvedObject = &involvedObject
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func eventMatches(analyzer *troubleshootv1beta2.EventsAnalyze, reasons []string, re *regexp.Regexp, event *corev1.Event) bool {
+	if analyzer.Kind != "" && !strings.EqualFold(event.InvolvedObject.Kind, analyzer.Kind) {
+		return false
+	}
+	if analyzer.Name != "" && event.InvolvedObject.Name != analyzer.Name {
+		return false
+	}
+	if analyzer.Type != "" && !strings.EqualFold(event.Type, analyzer.Type) {
+		return false
+	}
+	if len(reasons) > 0 {
+		found := false
+		for _, reason := range reasons {
+			if event.Reason == reason {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if re != nil && !re.MatchString(event.Message) {
+		return false
+	}
+	return true
+}
+
+// eventCount returns the number of times an event occurred. Events that occurred only once may not
+// have a count set.
+func eventCount(event *corev1.Event) int {
+	if event.Series != nil && event.Series.Count > 0 {
+		return int(event.Series.Count)
+	}
+	if event.Count > 0 {
+		return int(event.Count)
+	}
+	return 1
+}
+
+func eventLastSeen(event *corev1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+func eventsStatus(outcomes []*troubleshootv1beta2.Outcome, title string, group *eventGroup) (*AnalyzeResult, error) {
+	vars := map[string]interface{}{
+		"count":  0,
+		"events": 0,
+	}
+	if group != nil {
+		vars = map[string]interface{}{
+			"count":                group.Count,
+			"events":               group.Events,
+			"kind":                 group.Kind,
+			"namespace":            group.Namespace,
+			"name":                 group.Name,
+			"type":                 group.Type,
+			"reason":               group.Reason,
+			"minutesSinceLastSeen": group.MinutesSinceLastSeen,
+		}
+	} else {
+		group = &eventGroup{}
+	}
+
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, title, "kubernetes_text_analyze", "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg", compareWhen, group)
+}
+
+func getDefaultEventsResult(title string, group *eventGroup) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_text_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+		Message: fmt.Sprintf("The %s %s/%s has %d %s events: %s", group.Kind, group.Namespace, group.Name, group.Count, group.Reason, group.Message),
+	}
+	if group.Type == corev1.EventTypeWarning {
+		result.IsWarn = true
+	} else {
+		result.IsPass = true
+	}
+	return result
+}
@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeEvents(t *testing.T) {
+	apiPod := &corev1.ObjectReference{
+		Kind:       "Pod",
+		Namespace:  "default",
+		Name:       "api-6d4cf56db6-2xk8p",
+		APIVersion: "v1",
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.EventsAnalyze
+		expectResult []*AnalyzeResult
+		files        map[string][]byte
+	}{
+		{
+			name: "warning events without outcomes",
+			analyzer: troubleshootv1beta2.EventsAnalyze{
+				Type: "Warning",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "Pod default/api-6d4cf56db6-2xk8p Events",
+					Message:        "The Pod default/api-6d4cf56db6-2xk8p has 12 BackOff events: Back-off restarting failed container",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: apiPod,
+				},
+				{
+					IsWarn:  true,
+					Title:   "Pod default/db-0 Events",
+					Message: "The Pod default/db-0 has 3 FailedScheduling events: 0/3 nodes are available: 3 Insufficient memory.",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: &corev1.ObjectReference{
+						Kind:       "Pod",
+						Namespace:  "default",
+						Name:       "db-0",
+						APIVersion: "v1",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/events/default.json": []byte(defaultEvents),
+			},
+		},
+		{
+			name: "count and recency in when",
+			analyzer: troubleshootv1beta2.EventsAnalyze{
+				Namespace: "default",
+				Kind:      "Pod",
+				Reasons:   []string{"BackOff", "FailedScheduling"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count > 10 && minutesSinceLastSeen < 30",
+							Message: "{{ .Name }} is crash looping: {{ .Message }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count >= 1",
+							Message: "{{ .Name }} had {{ .Count }} {{ .Reason }} events",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "Pod default/api-6d4cf56db6-2xk8p Events",
+					Message:        "api-6d4cf56db6-2xk8p is crash looping: Back-off restarting failed container",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: apiPod,
+				},
+				{
+					IsWarn:  true,
+					Title:   "Pod default/db-0 Events",
+					Message: "db-0 had 3 FailedScheduling events",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: &corev1.ObjectReference{
+						Kind:       "Pod",
+						Namespace:  "default",
+						Name:       "db-0",
+						APIVersion: "v1",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/events/default.json": []byte(defaultEvents),
+			},
+		},
+		{
+			name: "regex on message",
+			analyzer: troubleshootv1beta2.EventsAnalyze{
+				Reason:       "FailedScheduling",
+				RegexPattern: "Insufficient (cpu|memory)",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Message }}",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "Pod default/db-0 Events",
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: &corev1.ObjectReference{
+						Kind:       "Pod",
+						Namespace:  "default",
+						Name:       "db-0",
+						APIVersion: "v1",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/events/default.json": []byte(defaultEvents),
+			},
+		},
+		{
+			name: "no matching events",
+			analyzer: troubleshootv1beta2.EventsAnalyze{
+				Reason: "OOMKilling",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count > 0 && minutesSinceLastSeen < 60",
+							Message: "{{ .Name }} was OOM killed",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "No OOM kills",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "Events",
+					Message: "No OOM kills",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/events/default.json": []byte(defaultEvents),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFiles := func(n string) (map[string][]byte, error) {
+				if file, ok := test.files[n]; ok {
+					return map[string][]byte{n: file}, nil
+				}
+				return test.files, nil
+			}
+
+			actual, err := analyzeEvents(&test.analyzer, "Events", getFiles, nil)
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var defaultEvents = `{
+  "kind": "EventList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "api-6d4cf56db6-2xk8p.16b1f1ff7d4e1d2a",
+        "namespace": "default"
+      },
+      "involvedObject": {
+        "kind": "Pod",
+        "namespace": "default",
+        "name": "api-6d4cf56db6-2xk8p",
+        "apiVersion": "v1"
+      },
+      "reason": "BackOff",
+      "message": "Back-off restarting failed container",
+      "firstTimestamp": "2022-03-01T10:00:00Z",
+      "lastTimestamp": "2022-03-01T11:55:00Z",
+      "count": 12,
+      "type": "Warning"
+    },
+    {
+      "metadata": {
+        "name": "db-0.16b1f1ff7d4e1d2b",
+        "namespace": "default"
+      },
+      "involvedObject": {
+        "kind": "Pod",
+        "namespace": "default",
+        "name": "db-0",
+        "apiVersion": "v1"
+      },
+      "reason": "FailedScheduling",
+      "message": "0/3 nodes are available: 3 Insufficient memory.",
+      "firstTimestamp": "2022-03-01T09:00:00Z",
+      "lastTimestamp": "2022-03-01T10:00:00Z",
+      "count": 3,
+      "type": "Warning"
+    },
+    {
+      "metadata": {
+        "name": "api-6d4cf56db6-2xk8p.16b1f1ff7d4e1d2c",
+        "namespace": "default"
+      },
+      "involvedObject": {
+        "kind": "Pod",
+        "namespace": "default",
+        "name": "api-6d4cf56db6-2xk8p",
+        "apiVersion": "v1"
+      },
+      "reason": "Pulled",
+      "message": "Container image \"api:1.0.0\" already present on machine",
+      "firstTimestamp": "2022-03-01T10:00:00Z",
+      "lastTimestamp": "2022-03-01T12:00:00Z",
+      "count": 13,
+      "type": "Normal"
+    }
+  ]
+}`
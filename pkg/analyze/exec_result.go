@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type AnalyzeExecResult struct {
+	analyzer *troubleshootv1beta2.ExecResultAnalyze
+}
+
+func (a *AnalyzeExecResult) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Exec Result")
+}
+
+func (a *AnalyzeExecResult) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeExecResult) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeExecResult(a.analyzer, getFile, findFiles)
+}
+
+// execResultState is what outcomes of the execResult analyzer are evaluated against, and what their
+// messages are templated with, for every pod the command ran in.
+type execResultState struct {
+	Namespace       string
+	Pod             string
+	Container       string
+	ExitCode        int
+	Signal          int
+	DurationSeconds float64
+	TimedOut        bool
+	ErrorMessage    string
+	Stdout          string
+}
+
+func (s execResultState) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"namespace":       s.Namespace,
+		"pod":             s.Pod,
+		"container":       s.Container,
+		"exitCode":        s.ExitCode,
+		"signal":          s.Signal,
+		"durationSeconds": s.DurationSeconds,
+		"timedOut":        s.TimedOut,
+		"errorMessage":    s.ErrorMessage,
+		"stdout":          s.Stdout,
+	}
+}
+
+// getExecResultFiles returns the results written by the exec and run collectors with the given name.
+// The exec collector writes <name>/<namespace>/<pod>/<collectorName>-result.json, and the run
+// collector writes <name>/<pod>-result.json, where the pod is named after the collector.
+func getExecResultFiles(analyzer *troubleshootv1beta2.ExecResultAnalyze, findFiles func(string) (map[string][]byte, error)) (map[string][]byte, error) {
+	results := map[string][]byte{}
+	for _, glob := range []string{
+		path.Join(analyzer.Name, "*-result.json"),
+		path.Join(analyzer.Name, "*", "*", "*-result.json"),
+	} {
+		files, err := findFiles(glob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find files matching %s", glob)
+		}
+		for name, contents := range files {
+			if analyzer.CollectorName != "" && filepath.Base(name) != analyzer.CollectorName+"-result.json" {
+				continue
+			}
+			results[name] = contents
+		}
+	}
+	return results, nil
+}
+
+func analyzeExecResult(analyzer *troubleshootv1beta2.ExecResultAnalyze, getCollectedFileContents func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	files, err := getExecResultFiles(analyzer, findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	states := []execResultState{}
+	for name, contents := range files {
+		execResult := collect.ExecResult{}
+		if err := json.Unmarshal(contents, &execResult); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		// other collectors may write files with the same suffix
+		if execResult.Pod == "" {
+			continue
+		}
+
+		state := execResultState{
+			Namespace:       execResult.Namespace,
+			Pod:             execResult.Pod,
+			Container:       execResult.Container,
+			ExitCode:        execResult.ExitCode,
+			Signal:          execResult.Signal,
+			DurationSeconds: execResult.DurationSeconds,
+			TimedOut:        execResult.TimedOut,
+			ErrorMessage:    execResult.Error,
+		}
+		if execResult.StdoutFile != "" {
+			stdout, err := getCollectedFileContents(execResult.StdoutFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get contents of %s", execResult.StdoutFile)
+			}
+			state.Stdout = string(stdout)
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Namespace != states[j].Namespace {
+			return states[i].Namespace < states[j].Namespace
+		}
+		return states[i].Pod < states[j].Pod
+	})
+
+	if len(states) == 0 {
+		return []*AnalyzeResult{
+			{
+				Title:   analyzerTitleOrDefault(analyzer.AnalyzeMeta, "Exec Result"),
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+				IsWarn:  true,
+				Message: "No exec results were collected",
+			},
+		}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, state := range states {
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = execResultStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process exec result")
+			}
+		} else {
+			result = getDefaultExecResultResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Namespace:  state.Namespace,
+				Name:       state.Pod,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func execResultStatus(outcomes []*troubleshootv1beta2.Outcome, state execResultState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s Exec Result", state.Namespace, state.Pod), "kubernetes_text_analyze", "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg", compareWhen, state)
+}
+
+// getDefaultExecResultResult fails when the command did not exit successfully
+func getDefaultExecResultResult(state execResultState) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title:   fmt.Sprintf("%s/%s Exec Result", state.Namespace, state.Pod),
+		IconKey: "kubernetes_text_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+		IsFail:  true,
+	}
+
+	switch {
+	case state.TimedOut:
+		result.Message = fmt.Sprintf("The command in pod %s/%s timed out", state.Namespace, state.Pod)
+	case state.ExitCode < 0:
+		result.Message = fmt.Sprintf("The command in pod %s/%s did not complete: %s", state.Namespace, state.Pod, state.ErrorMessage)
+	case state.Signal > 0:
+		result.Message = fmt.Sprintf("The command in pod %s/%s was killed by signal %d", state.Namespace, state.Pod, state.Signal)
+	case state.ExitCode > 0:
+		result.Message = fmt.Sprintf("The command in pod %s/%s exited with code %d", state.Namespace, state.Pod, state.ExitCode)
+	default:
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,234 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeExecResult(t *testing.T) {
+	podRef := func(namespace string, name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: namespace, Name: name}
+	}
+
+	files := map[string][]byte{
+		"check/app/api-0/disk-result.json": []byte(`{
+  "namespace": "app",
+  "pod": "api-0",
+  "container": "api",
+  "exitCode": 0,
+  "durationSeconds": 0.4,
+  "timedOut": false,
+  "stdoutFile": "check/app/api-0/disk-stdout.txt"
+}`),
+		"check/app/api-0/disk-stdout.txt": []byte("OK: 35% used\n"),
+		"check/app/api-1/disk-result.json": []byte(`{
+  "namespace": "app",
+  "pod": "api-1",
+  "container": "api",
+  "exitCode": 2,
+  "durationSeconds": 0.6,
+  "timedOut": false,
+  "stdoutFile": "check/app/api-1/disk-stdout.txt"
+}`),
+		"check/app/api-1/disk-stdout.txt": []byte("CRITICAL: 97% used\n"),
+		"check/app/api-2/disk-result.json": []byte(`{
+  "namespace": "app",
+  "pod": "api-2",
+  "container": "api",
+  "exitCode": -1,
+  "durationSeconds": 30,
+  "timedOut": true,
+  "error": "timeout"
+}`),
+		"check/app/api-0/other-result.json": []byte(`{"namespace": "app", "pod": "api-0", "exitCode": 1}`),
+		"diagnostics/diagnostics-result.json": []byte(`{
+  "namespace": "default",
+  "pod": "diagnostics",
+  "container": "collector",
+  "exitCode": 137,
+  "signal": 9,
+  "durationSeconds": 12.5,
+  "timedOut": false,
+  "stdoutFile": "diagnostics/diagnostics.log"
+}`),
+		"diagnostics/diagnostics.log": []byte("checking network\n"),
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ExecResultAnalyze
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name: "default results",
+			analyzer: troubleshootv1beta2.ExecResultAnalyze{
+				CollectorName: "disk",
+				Name:          "check",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "app/api-1 Exec Result",
+					Message:        "The command in pod app/api-1 exited with code 2",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("app", "api-1"),
+				},
+				{
+					IsFail:         true,
+					Title:          "app/api-2 Exec Result",
+					Message:        "The command in pod app/api-2 timed out",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("app", "api-2"),
+				},
+			},
+		},
+		{
+			name: "exit codes and stdout",
+			analyzer: troubleshootv1beta2.ExecResultAnalyze{
+				CollectorName: "disk",
+				Name:          "check",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "timedOut",
+							Message: "The disk check in {{ .Pod }} did not finish in {{ .DurationSeconds }}s",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "exitCode == 2",
+							Message: "{{ .Pod }} is running out of disk space",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    `stdout matches "^OK: [0-9]+% used"`,
+							Message: "{{ .Pod }} has enough disk space",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "app/api-0 Exec Result",
+					Message:        "api-0 has enough disk space",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("app", "api-0"),
+				},
+				{
+					IsFail:         true,
+					Title:          "app/api-1 Exec Result",
+					Message:        "api-1 is running out of disk space",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("app", "api-1"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "app/api-2 Exec Result",
+					Message:        "The disk check in api-2 did not finish in 30s",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("app", "api-2"),
+				},
+			},
+		},
+		{
+			name: "run collector with an expression",
+			analyzer: troubleshootv1beta2.ExecResultAnalyze{
+				CollectorName: "diagnostics",
+				Name:          "diagnostics",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "signal == 9 && durationSeconds > 10",
+							Message: "The diagnostics were killed after {{ .DurationSeconds }}s",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/diagnostics Exec Result",
+					Message:        "The diagnostics were killed after 12.5s",
+					IconKey:        "kubernetes_text_analyze",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+					InvolvedObject: podRef("default", "diagnostics"),
+				},
+			},
+		},
+		{
+			name: "no results",
+			analyzer: troubleshootv1beta2.ExecResultAnalyze{
+				CollectorName: "missing",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "Exec Result",
+					Message: "No exec results were collected",
+					IconKey: "kubernetes_text_analyze",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+				},
+			},
+		},
+		{
+			name: "unknown field",
+			analyzer: troubleshootv1beta2.ExecResultAnalyze{
+				CollectorName: "disk",
+				Name:          "check",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "stderr contains error",
+							Message: "failed",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(n string) ([]byte, error) {
+				return files[n], nil
+			}
+			findFiles := func(glob string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, contents := range files {
+					if ok, _ := filepath.Match(glob, name); ok {
+						matching[name] = contents
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeExecResult(&test.analyzer, getFile, findFiles)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
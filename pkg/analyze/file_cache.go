@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// collectedFileCache memoizes the results of getFile and findFiles so that analyzers running
+// against the same bundle read and load each file only once. It is safe for concurrent use.
+type collectedFileCache struct {
+	getFile   getCollectedFileContents
+	findFiles getChildCollectedFileContents
+
+	mut     sync.Mutex
+	files   map[string]*cachedFile
+	globs   map[string]*cachedGlob
+	objects map[cachedObjectKey]*cachedObject
+}
+
+type cachedFile struct {
+	once     sync.Once
+	contents []byte
+	err      error
+}
+
+type cachedGlob struct {
+	once  sync.Once
+	files map[string][]byte
+	err   error
+}
+
+type cachedObjectKey struct {
+	fileName   string
+	objectType reflect.Type
+}
+
+type cachedObject struct {
+	once   sync.Once
+	object interface{}
+	err    error
+}
+
+func newCollectedFileCache(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) *collectedFileCache {
+	return &collectedFileCache{
+		getFile:   getFile,
+		findFiles: findFiles,
+		files:     map[string]*cachedFile{},
+		globs:     map[string]*cachedGlob{},
+		objects:   map[cachedObjectKey]*cachedObject{},
+	}
+}
+
+func (c *collectedFileCache) GetFile(fileName string) ([]byte, error) {
+	c.mut.Lock()
+	f, ok := c.files[fileName]
+	if !ok {
+		f = &cachedFile{}
+		c.files[fileName] = f
+	}
+	c.mut.Unlock()
+
+	f.once.Do(func() {
+		f.contents, f.err = c.getFile(fileName)
+	})
+	return f.contents, f.err
+}
+
+func (c *collectedFileCache) FindFiles(glob string) (map[string][]byte, error) {
+	c.mut.Lock()
+	g, ok := c.globs[glob]
+	if !ok {
+		g = &cachedGlob{}
+		c.globs[glob] = g
+	}
+	c.mut.Unlock()
+
+	g.once.Do(func() {
+		g.files, g.err = c.findFiles(glob)
+	})
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	// analyzers are free to modify the map they are given, so hand each one its own copy
+	files := make(map[string][]byte, len(g.files))
+	for k, v := range g.files {
+		files[k] = v
+	}
+	return files, nil
+}
+
+// getObject returns the object of type objectType that load parses from fileName, calling load only
+// once per file and type. Objects are shared by every analyzer in the run and must not be modified.
+// A nil cache calls load every time, for analyzers that are run on their own.
+func (c *collectedFileCache) getObject(fileName string, objectType reflect.Type, load func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return load()
+	}
+
+	key := cachedObjectKey{fileName: fileName, objectType: objectType}
+
+	c.mut.Lock()
+	o, ok := c.objects[key]
+	if !ok {
+		o = &cachedObject{}
+		c.objects[key] = o
+	}
+	c.mut.Unlock()
+
+	o.once.Do(func() {
+		o.object, o.err = load()
+	})
+	return o.object, o.err
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type AnalyzeHelmRelease struct {
+	analyzer *troubleshootv1beta2.HelmReleaseAnalyze
+}
+
+func (a *AnalyzeHelmRelease) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Helm Release")
+}
+
+func (a *AnalyzeHelmRelease) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeHelmRelease) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeHelmRelease(a.analyzer, findFiles)
+}
+
+// helmReleaseState is what outcomes of the helmRelease analyzer are evaluated against, and what
+// their messages are templated with.
+type helmReleaseState struct {
+	Namespace    string
+	Name         string
+	Chart        string
+	ChartVersion string
+	AppVersion   string
+	Status       string
+	Revision     int
+	Description  string
+	// FailedRevisions is the number of revisions in the release history that failed
+	FailedRevisions int
+}
+
+func (s helmReleaseState) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"namespace":       s.Namespace,
+		"name":            s.Name,
+		"chart":           s.Chart,
+		"chartVersion":    s.ChartVersion,
+		"appVersion":      s.AppVersion,
+		"status":          s.Status,
+		"revision":        s.Revision,
+		"deployed":        s.Status == "deployed",
+		"failed":          s.Status == "failed",
+		"pending":         strings.HasPrefix(s.Status, "pending-"),
+		"failedRevisions": s.FailedRevisions,
+	}
+}
+
+func analyzeHelmRelease(analyzer *troubleshootv1beta2.HelmReleaseAnalyze, getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	namespace, releaseName := "*", "*"
+	if analyzer.Namespace != "" {
+		namespace = analyzer.Namespace
+	}
+	if analyzer.ReleaseName != "" {
+		releaseName = analyzer.ReleaseName
+	}
+
+	fileName := path.Join(collect.GetHelmCollectorFilepath(analyzer.CollectorName), namespace, fmt.Sprintf("%s.json", releaseName))
+	files, err := getFileContents(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected helm releases")
+	}
+
+	fileNames := []string{}
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	results := []*AnalyzeResult{}
+	for _, fileName := range fileNames {
+		var release collect.HelmReleaseInfo
+		if err := json.Unmarshal(files[fileName], &release); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+		}
+
+		state := getHelmReleaseState(&release)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = helmReleaseStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process helm release")
+			}
+		} else {
+			result = getDefaultHelmReleaseResult(state)
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	if analyzer.ReleaseName != "" && len(files) == 0 {
+		// there's not an error, but maybe the requested release is not even installed
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s Helm Release", analyzer.ReleaseName),
+				IsFail:  true,
+				Message: fmt.Sprintf("The Helm release %q was not found", analyzer.ReleaseName),
+			},
+		}, nil
+	}
+
+	return results, nil
+}
+
+func getHelmReleaseState(release *collect.HelmReleaseInfo) helmReleaseState {
+	state := helmReleaseState{
+		Namespace:    release.Namespace,
+		Name:         release.Name,
+		Chart:        release.Chart,
+		ChartVersion: release.ChartVersion,
+		AppVersion:   release.AppVersion,
+		Status:       release.Status,
+		Revision:     release.Revision,
+		Description:  release.Description,
+	}
+
+	for _, revision := range release.History {
+		if revision.Status == "failed" {
+			state.FailedRevisions++
+		}
+	}
+
+	return state
+}
+
+func helmReleaseStatus(outcomes []*troubleshootv1beta2.Outcome, state helmReleaseState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s Helm Release", state.Namespace, state.Name), "", "", compareWhen, state)
+}
+
+func getDefaultHelmReleaseResult(state helmReleaseState) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title: fmt.Sprintf("%s/%s Helm Release", state.Namespace, state.Name),
+	}
+
+	switch {
+	case state.Status == "failed":
+		result.IsFail = true
+		result.Message = fmt.Sprintf("The Helm release %s/%s (%s %s) failed at revision %d", state.Namespace, state.Name, state.Chart, state.ChartVersion, state.Revision)
+		if state.Description != "" {
+			result.Message = fmt.Sprintf("%s: %s", result.Message, state.Description)
+		}
+	case strings.HasPrefix(state.Status, "pending-"), state.Status == "uninstalling", state.Status == "unknown":
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("The Helm release %s/%s (%s %s) is %s at revision %d", state.Namespace, state.Name, state.Chart, state.ChartVersion, state.Status, state.Revision)
+	default:
+		return nil
+	}
+
+	return result
+}
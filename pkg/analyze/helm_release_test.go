@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_analyzeHelmRelease(t *testing.T) {
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.HelmReleaseAnalyze
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "default results",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "default/api Helm Release",
+					Message: "The Helm release default/api (api 1.4.0) failed at revision 3: Upgrade \"api\" failed: timed out waiting for the condition",
+				},
+				{
+					IsWarn:  true,
+					Title:   "monitoring/metrics Helm Release",
+					Message: "The Helm release monitoring/metrics (metrics 0.9.2) is pending-upgrade at revision 2",
+				},
+			},
+		},
+		{
+			name: "status and chart version",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "failed",
+							Message: "{{ .Name }} failed: {{ .Description }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "chartVersion < 1.0.0",
+							Message: "{{ .Name }} {{ .ChartVersion }} is too old",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Name }} is {{ .Status }}",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "default/api Helm Release",
+					Message: "api failed: Upgrade \"api\" failed: timed out waiting for the condition",
+				},
+				{
+					IsWarn:  true,
+					Title:   "monitoring/metrics Helm Release",
+					Message: "metrics 0.9.2 is too old",
+				},
+			},
+		},
+		{
+			name: "pending and failed revisions",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "pending",
+							Message: "{{ .Name }} is {{ .Status }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "failedRevisions > 1",
+							Message: "{{ .Name }} failed {{ .FailedRevisions }} times",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "default/api Helm Release",
+					Message: "api failed 2 times",
+				},
+				{
+					IsWarn:  true,
+					Title:   "monitoring/metrics Helm Release",
+					Message: "metrics is pending-upgrade",
+				},
+			},
+		},
+		{
+			name: "single release",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{
+				Namespace:   "monitoring",
+				ReleaseName: "metrics",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "!deployed",
+							Message: "{{ .Name }} is not deployed",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "monitoring/metrics Helm Release",
+					Message: "metrics is not deployed",
+				},
+			},
+		},
+		{
+			name: "release not found",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{
+				ReleaseName: "ingress",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "ingress Helm Release",
+					Message: "The Helm release \"ingress\" was not found",
+				},
+			},
+		},
+		{
+			name: "when is not a condition",
+			analyzer: troubleshootv1beta2.HelmReleaseAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "chartVersion",
+							Message: "invalid",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	files := map[string][]byte{
+		"helm/default/api.json":               []byte(apiHelmRelease),
+		"helm/default/api-values.yaml":        []byte("replicas: 2\n"),
+		"helm/monitoring/metrics.json":        []byte(metricsHelmRelease),
+		"helm/monitoring/metrics-values.yaml": []byte("{}\n"),
+		"helm/errors.json":                    []byte("[]"),
+	}
+	getFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := analyzeHelmRelease(&test.analyzer, getFiles)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var apiHelmRelease = `{
+  "name": "api",
+  "namespace": "default",
+  "chart": "api",
+  "chartVersion": "1.4.0",
+  "appVersion": "2.1.0",
+  "status": "failed",
+  "revision": 3,
+  "updated": "2022-03-01T12:00:00Z",
+  "description": "Upgrade \"api\" failed: timed out waiting for the condition",
+  "history": [
+    {"revision": 3, "status": "failed", "chart": "api", "chartVersion": "1.4.0", "appVersion": "2.1.0", "updated": "2022-03-01T12:00:00Z", "description": "Upgrade \"api\" failed: timed out waiting for the condition"},
+    {"revision": 2, "status": "failed", "chart": "api", "chartVersion": "1.3.0", "appVersion": "2.0.0", "updated": "2022-02-01T12:00:00Z", "description": "Upgrade \"api\" failed: pre-upgrade hooks failed"},
+    {"revision": 1, "status": "deployed", "chart": "api", "chartVersion": "1.2.0", "appVersion": "2.0.0", "updated": "2022-01-01T12:00:00Z", "description": "Install complete"}
+  ]
+}`
+
+var metricsHelmRelease = `{
+  "name": "metrics",
+  "namespace": "monitoring",
+  "chart": "metrics",
+  "chartVersion": "0.9.2",
+  "status": "pending-upgrade",
+  "revision": 2,
+  "updated": "2022-03-02T12:00:00Z",
+  "description": "Preparing upgrade",
+  "history": [
+    {"revision": 2, "status": "pending-upgrade", "chart": "metrics", "chartVersion": "0.9.2", "updated": "2022-03-02T12:00:00Z", "description": "Preparing upgrade"},
+    {"revision": 1, "status": "superseded", "chart": "metrics", "chartVersion": "0.9.1", "updated": "2022-01-02T12:00:00Z", "description": "Install complete"}
+  ]
+}`
@@ -50,10 +50,7 @@ func GetHostAnalyzer(analyzer *troubleshootv1beta2.HostAnalyze) (HostAnalyzer, b
 }
 
 func hostAnalyzerTitleOrDefault(meta troubleshootv1beta2.AnalyzeMeta, defaultTitle string) string {
-	if meta.CheckName != "" {
-		return meta.CheckName
-	}
-	return defaultTitle
+	return analyzerTitleOrDefault(meta, defaultTitle)
 }
 
 type resultCollector struct {
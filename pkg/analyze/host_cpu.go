@@ -39,6 +39,12 @@ func (a *AnalyzeHostCPU) Analyze(getCollectedFileContents func(string) ([]byte,
 		Title: a.Title(),
 	}
 
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, getCollectedFileContents, hostCPUWhenVariables(cpuInfo), func(when string) (bool, error) {
+			return compareHostCPUConditionalToActual(when, cpuInfo.LogicalCount, cpuInfo.PhysicalCount)
+		})
+	}
+
 	for _, outcome := range hostAnalyzer.Outcomes {
 
 		if outcome.Fail != nil {
@@ -50,7 +56,7 @@ func (a *AnalyzeHostCPU) Analyze(getCollectedFileContents func(string) ([]byte,
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostCPUConditionalToActual(outcome.Fail.When, cpuInfo.LogicalCount, cpuInfo.PhysicalCount)
+			isMatch, err := compareWhen(outcome.Fail.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare")
 			}
@@ -71,7 +77,7 @@ func (a *AnalyzeHostCPU) Analyze(getCollectedFileContents func(string) ([]byte,
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostCPUConditionalToActual(outcome.Warn.When, cpuInfo.LogicalCount, cpuInfo.PhysicalCount)
+			isMatch, err := compareWhen(outcome.Warn.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare")
 			}
@@ -92,7 +98,7 @@ func (a *AnalyzeHostCPU) Analyze(getCollectedFileContents func(string) ([]byte,
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostCPUConditionalToActual(outcome.Pass.When, cpuInfo.LogicalCount, cpuInfo.PhysicalCount)
+			isMatch, err := compareWhen(outcome.Pass.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to compare")
 			}
@@ -110,6 +116,20 @@ func (a *AnalyzeHostCPU) Analyze(getCollectedFileContents func(string) ([]byte,
 	return []*AnalyzeResult{&result}, nil
 }
 
+// hostCPUWhenVariables are the variables available to when expressions, both unqualified in the
+// cpu analyzer and as cpu.<name> in every other analyzer.
+func hostCPUWhenVariables(cpuInfo collect.CPUInfo) map[string]interface{} {
+	count := cpuInfo.LogicalCount
+	if cpuInfo.PhysicalCount > count {
+		count = cpuInfo.PhysicalCount
+	}
+	return map[string]interface{}{
+		"logical":  cpuInfo.LogicalCount,
+		"physical": cpuInfo.PhysicalCount,
+		"count":    count,
+	}
+}
+
 func compareHostCPUConditionalToActual(conditional string, logicalCount int, physicalCount int) (res bool, err error) {
 	compareLogical := false
 	comparePhysical := false
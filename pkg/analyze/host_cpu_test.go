@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/stretchr/testify/assert"
@@ -214,3 +215,87 @@ func TestHostCpuAnalyze(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeHostCPUWhenExpression(t *testing.T) {
+	cpuInfo, err := json.Marshal(collect.CPUInfo{LogicalCount: 8, PhysicalCount: 4})
+	require.NoError(t, err)
+	memoryInfo, err := json.Marshal(collect.MemoryInfo{Total: 8 * 1024 * 1024 * 1024})
+	require.NoError(t, err)
+
+	getCollectedFileContents := func(path string) ([]byte, error) {
+		switch path {
+		case collect.HostCPUPath:
+			return cpuInfo, nil
+		case collect.HostMemoryPath:
+			return memoryInfo, nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	tests := []struct {
+		name    string
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{
+			name: "legacy syntax",
+			when: "logical >= 8",
+			want: true,
+		},
+		{
+			name: "unqualified variables",
+			when: "logical >= 8 && physical >= 4",
+			want: true,
+		},
+		{
+			name: "qualified variable",
+			when: "cpu.logical >= 8",
+			want: true,
+		},
+		{
+			name: "across collectors",
+			when: "cpu.logical >= 4 && memory.total >= 16Gi",
+			want: false,
+		},
+		{
+			name: "across collectors, or",
+			when: "cpu.logical >= 4 || memory.total >= 16Gi",
+			want: true,
+		},
+		{
+			name: "keywords",
+			when: "logical >= 8 and physical >= 4",
+			want: true,
+		},
+		{
+			name:    "undefined variable",
+			when:    "cpu.sockets >= 2",
+			wantErr: "undefined variable cpu.sockets",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			hostAnalyzer := &troubleshootv1beta2.CPUAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: test.when, Message: "pass"}},
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "fail"}},
+				},
+			}
+
+			result, err := (&AnalyzeHostCPU{hostAnalyzer}).Analyze(getCollectedFileContents)
+			if test.wantErr != "" {
+				req.Error(err)
+				assert.Contains(t, err.Error(), test.wantErr)
+				return
+			}
+			req.NoError(err)
+			req.Len(result, 1)
+
+			assert.Equal(t, test.want, result[0].IsPass)
+		})
+	}
+}
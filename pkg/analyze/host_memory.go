@@ -40,6 +40,12 @@ func (a *AnalyzeHostMemory) Analyze(getCollectedFileContents func(string) ([]byt
 		Title: a.Title(),
 	}
 
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, getCollectedFileContents, hostMemoryWhenVariables(memoryInfo), func(when string) (bool, error) {
+			return compareHostMemoryConditionalToActual(when, memoryInfo.Total)
+		})
+	}
+
 	for _, outcome := range hostAnalyzer.Outcomes {
 
 		if outcome.Fail != nil {
@@ -51,7 +57,7 @@ func (a *AnalyzeHostMemory) Analyze(getCollectedFileContents func(string) ([]byt
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostMemoryConditionalToActual(outcome.Fail.When, memoryInfo.Total)
+			isMatch, err := compareWhen(outcome.Fail.When)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to compare %s", outcome.Fail.When)
 			}
@@ -72,7 +78,7 @@ func (a *AnalyzeHostMemory) Analyze(getCollectedFileContents func(string) ([]byt
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostMemoryConditionalToActual(outcome.Warn.When, memoryInfo.Total)
+			isMatch, err := compareWhen(outcome.Warn.When)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to compare %s", outcome.Warn.When)
 			}
@@ -93,7 +99,7 @@ func (a *AnalyzeHostMemory) Analyze(getCollectedFileContents func(string) ([]byt
 				return []*AnalyzeResult{&result}, nil
 			}
 
-			isMatch, err := compareHostMemoryConditionalToActual(outcome.Pass.When, memoryInfo.Total)
+			isMatch, err := compareWhen(outcome.Pass.When)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to compare %s", outcome.Pass.When)
 			}
@@ -111,6 +117,14 @@ func (a *AnalyzeHostMemory) Analyze(getCollectedFileContents func(string) ([]byt
 	return []*AnalyzeResult{&result}, nil
 }
 
+// hostMemoryWhenVariables are the variables available to when expressions, both unqualified in the
+// memory analyzer and as memory.<name> in every other analyzer.
+func hostMemoryWhenVariables(memoryInfo collect.MemoryInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"total": memoryInfo.Total,
+	}
+}
+
 func compareHostMemoryConditionalToActual(conditional string, total uint64) (res bool, err error) {
 	parts := strings.Split(conditional, " ")
 	if len(parts) != 2 {
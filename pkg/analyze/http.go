@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+type AnalyzeHTTP struct {
+	analyzer *troubleshootv1beta2.ClusterHTTPAnalyze
+}
+
+func (a *AnalyzeHTTP) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "HTTP Request")
+}
+
+func (a *AnalyzeHTTP) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeHTTP) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeHTTP(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// httpState is what outcomes of the http analyzer are evaluated against, and what their messages
+// are templated with. StatusCode is 0 when the request failed, JSON is the decoded body, or nil
+// when the body is not JSON, and Values are what the analyzer's JSONPath expression selects from it.
+type httpState struct {
+	StatusCode   int
+	Headers      map[string]string
+	Body         string
+	JSON         interface{}
+	Values       []interface{}
+	Error        bool
+	ErrorMessage string
+}
+
+func (s httpState) vars() map[string]interface{} {
+	headers := map[string]interface{}{}
+	for name, value := range s.Headers {
+		headers[name] = value
+	}
+	vars := map[string]interface{}{
+		"statusCode":   s.StatusCode,
+		"headers":      headers,
+		"body":         s.Body,
+		"error":        s.Error,
+		"errorMessage": s.ErrorMessage,
+	}
+	if s.JSON != nil {
+		vars["json"] = s.JSON
+	}
+	if s.Values != nil {
+		vars["values"] = s.Values
+		if len(s.Values) == 1 {
+			vars["value"] = s.Values[0]
+		}
+	}
+	return vars
+}
+
+// getHTTPCollectorFilePath returns the path of the result of an http collector. The collector writes
+// <collectorName>.json, or result.json without a collector name, to the directory in its name field.
+func getHTTPCollectorFilePath(name string, collectorName string, fileName string) string {
+	if fileName != "" {
+		return fileName
+	}
+	if collectorName != "" {
+		return filepath.Join(name, collectorName+".json")
+	}
+	return filepath.Join(name, "result.json")
+}
+
+func analyzeHTTP(analyzer *troubleshootv1beta2.ClusterHTTPAnalyze, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
+	fileName := getHTTPCollectorFilePath(analyzer.Name, analyzer.CollectorName, analyzer.FileName)
+	contents, err := getCollectedFileContents(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get contents of %s", fileName)
+	}
+
+	httpInfo := &httpResult{}
+	if err := json.Unmarshal(contents, httpInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal http result")
+	}
+
+	state, err := getHTTPState(httpInfo, analyzer.JsonPath)
+	if err != nil {
+		return nil, err
+	}
+	title := analyzerTitleOrDefault(analyzer.AnalyzeMeta, "HTTP Request")
+
+	if len(analyzer.Outcomes) == 0 {
+		return getDefaultHTTPResult(title, state), nil
+	}
+
+	result, err := httpStatus(analyzer.Outcomes, title, state)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to process http result")
+	}
+	return result, nil
+}
+
+func getHTTPState(httpInfo *httpResult, jsonPath string) (httpState, error) {
+	state := httpState{
+		Headers: map[string]string{},
+	}
+
+	if httpInfo.Error != nil {
+		state.Error = true
+		state.ErrorMessage = httpInfo.Error.Message
+	}
+
+	if httpInfo.Response != nil {
+		state.StatusCode = httpInfo.Response.Status
+		state.Body = httpInfo.Response.Body
+		for name, value := range httpInfo.Response.Headers {
+			state.Headers[name] = value
+		}
+
+		var body interface{}
+		if err := json.Unmarshal([]byte(httpInfo.Response.Body), &body); err == nil {
+			state.JSON = body
+		}
+	}
+
+	if jsonPath != "" {
+		state.Values = []interface{}{}
+		if state.JSON != nil {
+			values, err := findJSONPathValues(state.JSON, jsonPath)
+			if err != nil {
+				return httpState{}, err
+			}
+			state.Values = values
+		}
+	}
+
+	return state, nil
+}
+
+func httpStatus(outcomes []*troubleshootv1beta2.Outcome, title string, state httpState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, title, "kubernetes_text_analyze", "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg", compareWhen, state)
+}
+
+// getDefaultHTTPResult fails when the request failed or the response has an error status code
+func getDefaultHTTPResult(title string, state httpState) *AnalyzeResult {
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_text_analyze",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+		IsFail:  true,
+	}
+
+	switch {
+	case state.Error:
+		result.Message = fmt.Sprintf("The request failed: %s", state.ErrorMessage)
+	case state.StatusCode >= 400:
+		result.Message = fmt.Sprintf("The request returned status code %d", state.StatusCode)
+	default:
+		return nil
+	}
+
+	return result
+}
@@ -0,0 +1,242 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_analyzeHTTP(t *testing.T) {
+	healthy := `{
+  "response": {
+    "status": 200,
+    "body": "{\"status\": \"ok\", \"checks\": [{\"name\": \"db\", \"status\": \"ok\"}, {\"name\": \"cache\", \"status\": \"degraded\"}]}",
+    "headers": {"Content-Type": "application/json; charset=utf-8", "X-Request-Id": "abc"}
+  }
+}`
+	unavailable := `{
+  "response": {
+    "status": 503,
+    "body": "upstream connect error",
+    "headers": {"Content-Type": "text/plain"}
+  }
+}`
+	refused := `{
+  "error": {
+    "message": "Get \"http://api.default.svc:8080/healthz\": dial tcp 10.96.0.12:8080: connect: connection refused"
+  }
+}`
+
+	healthOutcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "error",
+				Message: "The health endpoint could not be reached: {{ .ErrorMessage }}",
+			},
+		},
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "statusCode != 200",
+				Message: "The health endpoint returned {{ .StatusCode }}",
+			},
+		},
+		{
+			Warn: &troubleshootv1beta2.SingleOutcome{
+				When:    `any values != "ok"`,
+				Message: "Some checks are not ok",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				When:    `json.status == "ok"`,
+				Message: "The health endpoint returned {{ .JSON.status }}",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ClusterHTTPAnalyze
+		fileName     string
+		contents     string
+		expectResult *AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "default result for a failed request",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{},
+			fileName: "result.json",
+			contents: refused,
+			expectResult: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "HTTP Request",
+				Message: `The request failed: Get "http://api.default.svc:8080/healthz": dial tcp 10.96.0.12:8080: connect: connection refused`,
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name:     "default result for an error status code",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{CollectorName: "api", Name: "healthz"},
+			fileName: "healthz/api.json",
+			contents: unavailable,
+			expectResult: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "HTTP Request",
+				Message: "The request returned status code 503",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name:         "no default result for a successful request",
+			analyzer:     troubleshootv1beta2.ClusterHTTPAnalyze{},
+			fileName:     "result.json",
+			contents:     healthy,
+			expectResult: nil,
+		},
+		{
+			name:         "result without a collector name",
+			analyzer:     troubleshootv1beta2.ClusterHTTPAnalyze{Name: "healthz"},
+			fileName:     "healthz/result.json",
+			contents:     healthy,
+			expectResult: nil,
+		},
+		{
+			name: "error",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				FileName: "healthz/api.json",
+				Outcomes: healthOutcomes,
+			},
+			fileName: "healthz/api.json",
+			contents: refused,
+			expectResult: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "HTTP Request",
+				Message: `The health endpoint could not be reached: Get "http://api.default.svc:8080/healthz": dial tcp 10.96.0.12:8080: connect: connection refused`,
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "status code",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				Outcomes: healthOutcomes,
+			},
+			fileName: "result.json",
+			contents: unavailable,
+			expectResult: &AnalyzeResult{
+				IsFail:  true,
+				Title:   "HTTP Request",
+				Message: "The health endpoint returned 503",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "json path",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				JsonPath: "{.checks[*].status}",
+				Outcomes: healthOutcomes,
+			},
+			fileName: "result.json",
+			contents: healthy,
+			expectResult: &AnalyzeResult{
+				IsWarn:  true,
+				Title:   "HTTP Request",
+				Message: "Some checks are not ok",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "headers and error messages",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "API Health"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    `errorMessage contains "connection refused"`,
+							Message: "The API is not listening",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    `headers.Content-Type contains "application/json"`,
+							Message: "The API returned JSON",
+						},
+					},
+				},
+			},
+			fileName: "result.json",
+			contents: healthy,
+			expectResult: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "API Health",
+				Message: "The API returned JSON",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "expression",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    `statusCode == 200 && json.status == "ok" && headers.X-Request-Id == "abc"`,
+							Message: "ok",
+						},
+					},
+				},
+			},
+			fileName: "result.json",
+			contents: healthy,
+			expectResult: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "HTTP Request",
+				Message: "ok",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "unknown field",
+			analyzer: troubleshootv1beta2.ClusterHTTPAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "latency > 1s",
+							Message: "slow",
+						},
+					},
+				},
+			},
+			fileName: "result.json",
+			contents: healthy,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(n string) ([]byte, error) {
+				req.Equal(test.fileName, n)
+				return []byte(test.contents), nil
+			}
+
+			actual, err := analyzeHTTP(&test.analyzer, getFile)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.expectResult, actual)
+		})
+	}
+}
@@ -8,6 +8,26 @@ import (
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
+type AnalyzeImagePullSecret struct {
+	analyzer *troubleshootv1beta2.ImagePullSecret
+}
+
+func (a *AnalyzeImagePullSecret) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Image Pull Secrets")
+}
+
+func (a *AnalyzeImagePullSecret) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeImagePullSecret) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeImagePullSecret(a.analyzer, findFiles)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeImagePullSecret(analyzer *troubleshootv1beta2.ImagePullSecret, getChildCollectedFileContents func(string) (map[string][]byte, error)) (*AnalyzeResult, error) {
 	imagePullSecrets, err := getChildCollectedFileContents("cluster-resources/image-pull-secrets")
 	if err != nil {
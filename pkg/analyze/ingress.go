@@ -9,6 +9,26 @@ import (
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 )
 
+type AnalyzeIngress struct {
+	analyzer *troubleshootv1beta2.Ingress
+}
+
+func (a *AnalyzeIngress) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, fmt.Sprintf("Ingress %s", a.analyzer.IngressName))
+}
+
+func (a *AnalyzeIngress) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeIngress) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeIngress(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeIngress(analyzer *troubleshootv1beta2.Ingress, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	ingressData, err := getCollectedFileContents(filepath.Join("cluster-resources", "ingress", fmt.Sprintf("%s.json", analyzer.Namespace)))
 	if err != nil {
@@ -12,6 +12,22 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 )
 
+type AnalyzeJobStatus struct {
+	analyzer *troubleshootv1beta2.JobStatus
+}
+
+func (a *AnalyzeJobStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Job Status")
+}
+
+func (a *AnalyzeJobStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeJobStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeJobStatus(a.analyzer, findFiles)
+}
+
 func analyzeJobStatus(analyzer *troubleshootv1beta2.JobStatus, getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	if analyzer.Name == "" {
 		return analyzeAllJobStatuses(analyzer, getFileContents)
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"path/filepath"
 	"testing"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -551,7 +552,189 @@ func Test_jsonCompare(t *testing.T) {
 				return test.fileContents, nil
 			}
 
-			actual, err := analyzeJsonCompare(&test.analyzer, getCollectedFileContents)
+			actual, err := analyzeJsonCompare(&test.analyzer, getCollectedFileContents, nil)
+			if !test.isError {
+				req.NoError(err)
+				req.Equal(test.expectResult, *actual)
+			} else {
+				req.Error(err)
+			}
+		})
+	}
+}
+
+func Test_jsonCompareJsonPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		isError      bool
+		analyzer     troubleshootv1beta2.JsonCompare
+		expectResult AnalyzeResult
+	}{
+		{
+			name: "values from a glob of files",
+			analyzer: troubleshootv1beta2.JsonCompare{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "any values < 2",
+							Message: "fail",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "pass",
+						},
+					},
+				},
+				CollectorName: "cluster-resources",
+				FileName:      "deployments/*.json",
+				JsonPath:      "{.items[*].spec.replicas}",
+			},
+			expectResult: AnalyzeResult{
+				IsFail:  true,
+				Title:   "cluster-resources",
+				Message: "fail",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "jsonpath filter with semver comparison",
+			analyzer: troubleshootv1beta2.JsonCompare{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "value >= 1.10.0",
+							Message: "pass",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "fail",
+						},
+					},
+				},
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: "API version",
+				},
+				CollectorName: "cluster-resources",
+				FileName:      "deployments/*.json",
+				JsonPath:      `.items[?(@.metadata.name=="api")].metadata.labels.version`,
+			},
+			expectResult: AnalyzeResult{
+				IsPass:  true,
+				Title:   "API version",
+				Message: "pass",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "jsonpath compared to value",
+			analyzer: troubleshootv1beta2.JsonCompare{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "pass",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "fail",
+						},
+					},
+				},
+				CollectorName: "cluster-resources",
+				FileName:      "deployments/default.json",
+				JsonPath:      "{.items[*].spec.template.spec.containers[*].imagePullPolicy}",
+				Value:         `"IfNotPresent"`,
+			},
+			expectResult: AnalyzeResult{
+				IsPass:  true,
+				Title:   "cluster-resources",
+				Message: "pass",
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			},
+		},
+		{
+			name: "no matching files",
+			analyzer: troubleshootv1beta2.JsonCompare{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "all values > 0",
+							Message: "pass",
+						},
+					},
+				},
+				CollectorName: "cluster-resources",
+				FileName:      "statefulsets/*.json",
+				JsonPath:      "{.items[*].spec.replicas}",
+			},
+			isError: true,
+		},
+		{
+			name: "true without a value",
+			analyzer: troubleshootv1beta2.JsonCompare{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "true",
+							Message: "pass",
+						},
+					},
+				},
+				CollectorName: "cluster-resources",
+				FileName:      "deployments/*.json",
+				JsonPath:      "{.items[*].spec.replicas}",
+			},
+			isError: true,
+		},
+	}
+
+	files := map[string][]byte{
+		"cluster-resources/deployments/default.json": []byte(`{
+			"items": [
+				{
+					"metadata": {"name": "api", "labels": {"version": "1.12.1"}},
+					"spec": {
+						"replicas": 3,
+						"template": {"spec": {"containers": [{"name": "api", "imagePullPolicy": "IfNotPresent"}]}}
+					}
+				}
+			]
+		}`),
+		"cluster-resources/deployments/monitoring.json": []byte(`{
+			"items": [
+				{
+					"metadata": {"name": "grafana", "labels": {"version": "1.9.0"}},
+					"spec": {
+						"replicas": 1,
+						"template": {"spec": {"containers": [{"name": "grafana", "imagePullPolicy": "Always"}]}}
+					}
+				}
+			]
+		}`),
+	}
+	getCollectedFileContents := func(n string) ([]byte, error) {
+		return files[n], nil
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := analyzeJsonCompare(&test.analyzer, getCollectedFileContents, findFiles)
 			if !test.isError {
 				req.NoError(err)
 				req.Equal(test.expectResult, *actual)
@@ -17,6 +17,22 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+type AnalyzeLonghorn struct {
+	analyzer *troubleshootv1beta2.LonghornAnalyze
+}
+
+func (a *AnalyzeLonghorn) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Longhorn")
+}
+
+func (a *AnalyzeLonghorn) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeLonghorn) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return longhorn(a.analyzer, getFile, findFiles)
+}
+
 func longhorn(analyzer *troubleshootv1beta2.LonghornAnalyze, getCollectedFileContents func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	ns := collect.DefaultLonghornNamespace
 	if analyzer.Namespace != "" {
@@ -0,0 +1,218 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// nodeConditionVariables are the conditions available in nodeConditions `when` clauses. When
+// evaluating a single node they are booleans, when aggregating they are the number of nodes for
+// which the condition is true.
+var nodeConditionVariables = []string{
+	"ready",
+	"memoryPressure",
+	"diskPressure",
+	"pidPressure",
+	"networkUnavailable",
+	"unschedulable",
+	"tainted",
+	"schedulable",
+}
+
+type AnalyzeNodeConditions struct {
+	analyzer *troubleshootv1beta2.NodeConditions
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeNodeConditions) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Node Conditions")
+}
+
+func (a *AnalyzeNodeConditions) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeNodeConditions) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeNodeConditions) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeNodeConditions(a.analyzer, a.Title(), getFile, a.objects)
+}
+
+func analyzeNodeConditions(analyzer *troubleshootv1beta2.NodeConditions, title string, getCollectedFileContents func(string) ([]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	matchingNodes := []corev1.Node{}
+	for _, node := range nodes.Items {
+		if labelSelector.Matches(labels.Set(node.Labels)) {
+			matchingNodes = append(matchingNodes, node)
+		}
+	}
+
+	if analyzer.Aggregate {
+		if len(analyzer.Outcomes) == 0 {
+			return nil, errors.New("outcomes are required when aggregating node conditions")
+		}
+
+		vars := map[string]interface{}{
+			"count": len(matchingNodes),
+		}
+		for _, name := range nodeConditionVariables {
+			vars[name] = 0
+		}
+		for _, node := range matchingNodes {
+			for name, value := range getNodeConditionVariables(&node, analyzer.Taints) {
+				if isTrue, ok := value.(bool); ok && isTrue {
+					vars[name] = vars[name].(int) + 1
+				}
+			}
+		}
+
+		result, err := nodeConditionsStatus(analyzer.Outcomes, title, vars)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to process node conditions")
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, node := range matchingNodes {
+		vars := getNodeConditionVariables(&node, analyzer.Taints)
+		nodeTitle := fmt.Sprintf("Node %s Conditions", node.Name)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = nodeConditionsStatus(analyzer.Outcomes, nodeTitle, vars)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process node conditions")
+			}
+		} else {
+			result = getDefaultNodeConditionsResult(nodeTitle, vars)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func getNodeConditionVariables(node *corev1.Node, taints []troubleshootv1beta2.NodeTaint) map[string]interface{} {
+	vars := map[string]interface{}{
+		"name":               node.Name,
+		"ready":              nodeConditionIsTrue(node, corev1.NodeReady),
+		"memoryPressure":     nodeConditionIsTrue(node, corev1.NodeMemoryPressure),
+		"diskPressure":       nodeConditionIsTrue(node, corev1.NodeDiskPressure),
+		"pidPressure":        nodeConditionIsTrue(node, corev1.NodePIDPressure),
+		"networkUnavailable": nodeConditionIsTrue(node, corev1.NodeNetworkUnavailable),
+		"unschedulable":      node.Spec.Unschedulable,
+		"tainted":            nodeHasTaint(node, taints),
+	}
+	// whether pods can be scheduled does not depend on the taints the spec looks for
+	vars["schedulable"] = vars["ready"].(bool) && !node.Spec.Unschedulable && !nodeHasTaint(node, nil)
+	return vars
+}
+
+func nodeConditionIsTrue(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeHasTaint reports whether the node has one of the given taints. Without any taints to look for,
+// any taint that keeps pods from being scheduled on the node counts.
+func nodeHasTaint(node *corev1.Node, taints []troubleshootv1beta2.NodeTaint) bool {
+	for _, nodeTaint := range node.Spec.Taints {
+		if len(taints) == 0 {
+			if nodeTaint.Effect == corev1.TaintEffectNoSchedule || nodeTaint.Effect == corev1.TaintEffectNoExecute {
+				return true
+			}
+			continue
+		}
+
+		for _, taint := range taints {
+			if taint.Key != nodeTaint.Key {
+				continue
+			}
+			if taint.Value != "" && taint.Value != nodeTaint.Value {
+				continue
+			}
+			if taint.Effect != "" && taint.Effect != string(nodeTaint.Effect) {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func nodeConditionsStatus(outcomes []*troubleshootv1beta2.Outcome, title string, vars map[string]interface{}) (*AnalyzeResult, error) {
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, title, "kubernetes_node_resources", "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18", compareWhen, vars)
+}
+
+func getDefaultNodeConditionsResult(title string, vars map[string]interface{}) *AnalyzeResult {
+	problems := []string{}
+	if !vars["ready"].(bool) {
+		problems = append(problems, "is not ready")
+	}
+	for _, name := range []string{"memoryPressure", "diskPressure", "pidPressure", "networkUnavailable"} {
+		if vars[name].(bool) {
+			problems = append(problems, fmt.Sprintf("has %s", name))
+		}
+	}
+
+	warnings := []string{}
+	if vars["unschedulable"].(bool) {
+		warnings = append(warnings, "is cordoned")
+	}
+	if vars["tainted"].(bool) {
+		warnings = append(warnings, "is tainted")
+	}
+
+	result := &AnalyzeResult{
+		Title:   title,
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}
+	switch {
+	case len(problems) > 0:
+		result.IsFail = true
+		result.Message = fmt.Sprintf("Node %s %s", vars["name"], strings.Join(append(problems, warnings...), ", "))
+	case len(warnings) > 0:
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("Node %s %s", vars["name"], strings.Join(warnings, ", "))
+	default:
+		return nil
+	}
+	return result
+}
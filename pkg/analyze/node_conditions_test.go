@@ -0,0 +1,281 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeNodeConditions(t *testing.T) {
+	nodeRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: name}
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.NodeConditions
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "default results for unhealthy nodes",
+			analyzer: troubleshootv1beta2.NodeConditions{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "Node worker-2 Conditions",
+					Message:        "Node worker-2 has diskPressure, is tainted",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("worker-2"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "Node control-plane Conditions",
+					Message:        "Node control-plane is tainted",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("control-plane"),
+				},
+				{
+					IsFail:         true,
+					Title:          "Node worker-3 Conditions",
+					Message:        "Node worker-3 is not ready, is cordoned",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("worker-3"),
+				},
+			},
+		},
+		{
+			name: "per node outcomes",
+			analyzer: troubleshootv1beta2.NodeConditions{
+				Selector: []string{"node-role.kubernetes.io/worker"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "!ready",
+							Message: "{{ .name }} is not ready",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "diskPressure || memoryPressure",
+							Message: "{{ .name }} is under pressure",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .name }} is healthy",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "Node worker-1 Conditions",
+					Message:        "worker-1 is healthy",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("worker-1"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "Node worker-2 Conditions",
+					Message:        "worker-2 is under pressure",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("worker-2"),
+				},
+				{
+					IsFail:         true,
+					Title:          "Node worker-3 Conditions",
+					Message:        "worker-3 is not ready",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: nodeRef("worker-3"),
+				},
+			},
+		},
+		{
+			name: "aggregate schedulable nodes",
+			analyzer: troubleshootv1beta2.NodeConditions{
+				Aggregate: true,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "schedulable < 3",
+							Message: "Only {{ .schedulable }} of {{ .count }} nodes are schedulable",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "pass",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "Node Conditions",
+					Message: "Only 1 of 4 nodes are schedulable",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "aggregate with specific taints",
+			analyzer: troubleshootv1beta2.NodeConditions{
+				Aggregate: true,
+				Taints: []troubleshootv1beta2.NodeTaint{
+					{Key: "dedicated", Value: "gpu"},
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "tainted == 0 && schedulable == 1",
+							Message: "pass",
+						},
+					},
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							Message: "fail",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "Node Conditions",
+					Message: "pass",
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+		{
+			name: "aggregate without outcomes",
+			analyzer: troubleshootv1beta2.NodeConditions{
+				Aggregate: true,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(n string) ([]byte, error) {
+				return []byte(nodeConditionsNodes), nil
+			}
+
+			actual, err := analyzeNodeConditions(&test.analyzer, "Node Conditions", getFile, nil)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var nodeConditionsNodes = `{
+  "kind": "NodeList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "control-plane",
+        "labels": {
+          "node-role.kubernetes.io/control-plane": ""
+        }
+      },
+      "spec": {
+        "taints": [
+          {
+            "key": "node-role.kubernetes.io/control-plane",
+            "effect": "NoSchedule"
+          }
+        ]
+      },
+      "status": {
+        "conditions": [
+          {"type": "MemoryPressure", "status": "False"},
+          {"type": "DiskPressure", "status": "False"},
+          {"type": "PIDPressure", "status": "False"},
+          {"type": "Ready", "status": "True"}
+        ]
+      }
+    },
+    {
+      "metadata": {
+        "name": "worker-1",
+        "labels": {
+          "node-role.kubernetes.io/worker": ""
+        }
+      },
+      "spec": {},
+      "status": {
+        "conditions": [
+          {"type": "MemoryPressure", "status": "False"},
+          {"type": "DiskPressure", "status": "False"},
+          {"type": "PIDPressure", "status": "False"},
+          {"type": "Ready", "status": "True"}
+        ]
+      }
+    },
+    {
+      "metadata": {
+        "name": "worker-2",
+        "labels": {
+          "node-role.kubernetes.io/worker": ""
+        }
+      },
+      "spec": {
+        "taints": [
+          {
+            "key": "node.kubernetes.io/disk-pressure",
+            "effect": "NoSchedule"
+          }
+        ]
+      },
+      "status": {
+        "conditions": [
+          {"type": "MemoryPressure", "status": "False"},
+          {"type": "DiskPressure", "status": "True"},
+          {"type": "PIDPressure", "status": "False"},
+          {"type": "Ready", "status": "True"}
+        ]
+      }
+    },
+    {
+      "metadata": {
+        "name": "worker-3",
+        "labels": {
+          "node-role.kubernetes.io/worker": ""
+        }
+      },
+      "spec": {
+        "unschedulable": true
+      },
+      "status": {
+        "conditions": [
+          {"type": "Ready", "status": "Unknown"}
+        ]
+      }
+    }
+  ]
+}`
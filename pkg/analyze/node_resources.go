@@ -3,6 +3,7 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,15 +14,55 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
-	collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+type AnalyzeNodeResources struct {
+	analyzer *troubleshootv1beta2.NodeResources
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeNodeResources) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Node Resources")
+}
+
+func (a *AnalyzeNodeResources) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeNodeResources) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeNodeResources) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeNodeResources(a.analyzer, getFile, a.objects)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+		return nil, err
 	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// readCollectedNodes returns the collected nodes, which are parsed once per objects cache.
+func readCollectedNodes(objects *collectedFileCache, getCollectedFileContents func(string) ([]byte, error)) (*corev1.NodeList, error) {
+	nodes, err := objects.getObject("cluster-resources/nodes.json", reflect.TypeOf(corev1.NodeList{}), func() (interface{}, error) {
+		collected, err := getCollectedFileContents("cluster-resources/nodes.json")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+		}
 
-	var nodes corev1.NodeList
-	if err := json.Unmarshal(collected, &nodes); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal node list")
+		var nodes corev1.NodeList
+		if err := json.Unmarshal(collected, &nodes); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal node list")
+		}
+		return &nodes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.(*corev1.NodeList), nil
+}
+
+func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollectedFileContents func(string) ([]byte, error), objects *collectedFileCache) (*AnalyzeResult, error) {
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
+	if err != nil {
+		return nil, err
 	}
 
 	matchingNodes := []corev1.Node{}
@@ -48,9 +89,16 @@ func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollec
 		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
 	}
 
+	vars := nodeResourcesWhenVariables(matchingNodes)
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, func(when string) (bool, error) {
+			return compareNodeResourceConditionalToActual(when, matchingNodes)
+		})
+	}
+
 	for _, outcome := range analyzer.Outcomes {
 		if outcome.Fail != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Fail.When, matchingNodes)
+			isWhenMatch, err := compareWhen(outcome.Fail.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse when")
 			}
@@ -63,7 +111,7 @@ func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollec
 				return result, nil
 			}
 		} else if outcome.Warn != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Warn.When, matchingNodes)
+			isWhenMatch, err := compareWhen(outcome.Warn.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse when")
 			}
@@ -76,7 +124,7 @@ func analyzeNodeResources(analyzer *troubleshootv1beta2.NodeResources, getCollec
 				return result, nil
 			}
 		} else if outcome.Pass != nil {
-			isWhenMatch, err := compareNodeResourceConditionalToActual(outcome.Pass.When, matchingNodes)
+			isWhenMatch, err := compareWhen(outcome.Pass.When)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to parse when")
 			}
@@ -268,6 +316,41 @@ func compareNodeResourceConditionalToActual(conditional string, matchingNodes []
 	return
 }
 
+// nodeResourceProperties are the properties of a node that the min, max and sum functions accept.
+var nodeResourceProperties = []string{
+	"cpuCapacity",
+	"cpuAllocatable",
+	"memoryCapacity",
+	"memoryAllocatable",
+	"podCapacity",
+	"podAllocatable",
+	"ephemeralStorageCapacity",
+	"ephemeralStorageAllocatable",
+}
+
+// nodeResourcesWhenVariables exposes the functions of the legacy conditionals to the expression
+// language, e.g. count, min.memoryCapacity or sum.cpuAllocatable. min and max are undefined for a
+// property that none of the nodes has.
+func nodeResourcesWhenVariables(nodes []corev1.Node) map[string]interface{} {
+	min, max, sum := map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}
+	for _, property := range nodeResourceProperties {
+		if quantity := findMin(nodes, property); quantity != nil {
+			min[property] = quantity
+		}
+		if quantity := findMax(nodes, property); quantity != nil {
+			max[property] = quantity
+		}
+		sum[property] = findSum(nodes, property)
+	}
+
+	return map[string]interface{}{
+		"count": len(nodes),
+		"min":   min,
+		"max":   max,
+		"sum":   sum,
+	}
+}
+
 func getQuantity(node corev1.Node, property string) *resource.Quantity {
 	switch property {
 	case "cpuCapacity":
@@ -709,6 +709,35 @@ func Test_analyzeNodeResources(t *testing.T) {
 				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
 			},
 		},
+		{
+			name: "expression combining count and max", // shared expression language
+			analyzer: &troubleshootv1beta2.NodeResources{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: "bignode-exists",
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "count < 3 || max.cpuCapacity < 8",
+							Message: "There are fewer than 3 nodes, or none with 8 cores",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "count >= 3 and max.cpuCapacity >= 8",
+							Message: "There are at least 3 nodes, and one with 8 cores",
+						},
+					},
+				},
+			},
+			want: &AnalyzeResult{
+				IsPass:  true,
+				Title:   "bignode-exists",
+				Message: "There are at least 3 nodes, and one with 8 cores",
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+			},
+		},
 		{
 			name: "unfiltered CPU totals",
 			analyzer: &troubleshootv1beta2.NodeResources{
@@ -880,7 +909,7 @@ func Test_analyzeNodeResources(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			req := require.New(t)
 
-			got, err := analyzeNodeResources(tt.analyzer, getExampleNodeContents)
+			got, err := analyzeNodeResources(tt.analyzer, getExampleNodeContents, nil)
 			req.NoError(err)
 			req.Equal(tt.want, got)
 		})
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// evaluateOutcomes returns the result of the first outcome whose when clause matches, or nil if none
+// does. Outcomes without a when clause always match. The message of the matching outcome is a
+// template that is executed with templateData, or used as is when templateData is nil.
+func evaluateOutcomes(outcomes []*troubleshootv1beta2.Outcome, title, iconKey, iconURI string, compareWhen func(when string) (bool, error), templateData interface{}) (*AnalyzeResult, error) {
+	// ordering from the spec is important, the first one that matches returns
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			Title:   title,
+			IconKey: iconKey,
+			IconURI: iconURI,
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		if outcome.Fail != nil {
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		} else if outcome.Warn != nil {
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		} else if outcome.Pass != nil {
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		} else {
+			continue
+		}
+
+		if strings.TrimSpace(singleOutcome.When) != "" {
+			match, err := compareWhen(singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to compare %s", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		message, err := templateOutcomeMessage(singleOutcome.Message, templateData)
+		if err != nil {
+			return nil, err
+		}
+		result.Message = message
+		result.URI = singleOutcome.URI
+
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+func templateOutcomeMessage(message string, templateData interface{}) (string, error) {
+	if templateData == nil {
+		return message, nil
+	}
+
+	tmpl, err := template.New("message").Parse(message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse message template")
+	}
+	var m bytes.Buffer
+	if err := tmpl.Execute(&m, templateData); err != nil {
+		return "", errors.Wrap(err, "failed to template message")
+	}
+	return m.String(), nil
+}
+
+// withDefaultWhen returns a copy of outcomes in which outcomes without a when clause get the one
+// returned by defaultWhen, for analyzers where an outcome without a when clause does not simply match.
+func withDefaultWhen(outcomes []*troubleshootv1beta2.Outcome, defaultWhen func(outcome *troubleshootv1beta2.Outcome) string) []*troubleshootv1beta2.Outcome {
+	withDefaults := make([]*troubleshootv1beta2.Outcome, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		copied := *outcome
+		for _, single := range []**troubleshootv1beta2.SingleOutcome{&copied.Fail, &copied.Warn, &copied.Pass} {
+			if *single != nil && strings.TrimSpace((*single).When) == "" {
+				withWhen := **single
+				withWhen.When = defaultWhen(outcome)
+				*single = &withWhen
+			}
+		}
+		withDefaults = append(withDefaults, &copied)
+	}
+	return withDefaults
+}
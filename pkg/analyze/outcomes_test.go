@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluateOutcomes(t *testing.T) {
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Fail: &troubleshootv1beta2.SingleOutcome{
+				When:    "count > 10",
+				Message: "{{ .Name }} has too many",
+			},
+		},
+		{
+			Warn: &troubleshootv1beta2.SingleOutcome{
+				When:    "count > 5",
+				Message: "{{ .Name }} has many",
+				URI:     "https://example.com/warn",
+			},
+		},
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "{{ .Name }} is fine",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		outcomes     []*troubleshootv1beta2.Outcome
+		count        int
+		templateData interface{}
+		want         *AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:         "first match wins",
+			outcomes:     outcomes,
+			count:        20,
+			templateData: struct{ Name string }{"thing"},
+			want: &AnalyzeResult{
+				Title:   "Things",
+				IconKey: "icon",
+				IconURI: "https://example.com/icon.svg",
+				IsFail:  true,
+				Message: "thing has too many",
+			},
+		},
+		{
+			name:         "later match",
+			outcomes:     outcomes,
+			count:        6,
+			templateData: struct{ Name string }{"thing"},
+			want: &AnalyzeResult{
+				Title:   "Things",
+				IconKey: "icon",
+				IconURI: "https://example.com/icon.svg",
+				IsWarn:  true,
+				Message: "thing has many",
+				URI:     "https://example.com/warn",
+			},
+		},
+		{
+			name:     "outcome without when matches, message used as is",
+			outcomes: outcomes,
+			count:    1,
+			want: &AnalyzeResult{
+				Title:   "Things",
+				IconKey: "icon",
+				IconURI: "https://example.com/icon.svg",
+				IsPass:  true,
+				Message: "{{ .Name }} is fine",
+			},
+		},
+		{
+			name:     "nothing matches",
+			outcomes: outcomes[:2],
+			count:    1,
+			want:     nil,
+		},
+		{
+			name: "bad when",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: "count >"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			vars := map[string]interface{}{"count": test.count}
+			compareWhen := func(when string) (bool, error) {
+				return evaluateWhen(when, nil, vars, nil)
+			}
+
+			result, err := evaluateOutcomes(test.outcomes, "Things", "icon", "https://example.com/icon.svg", compareWhen, test.templateData)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.want, result)
+		})
+	}
+}
+
+func Test_withDefaultWhen(t *testing.T) {
+	outcomes := []*troubleshootv1beta2.Outcome{
+		{Fail: &troubleshootv1beta2.SingleOutcome{Message: "fail"}},
+		{Warn: &troubleshootv1beta2.SingleOutcome{When: "> 1", Message: "warn"}},
+		{Pass: &troubleshootv1beta2.SingleOutcome{When: " ", Message: "pass"}},
+	}
+
+	actual := withDefaultWhen(outcomes, func(outcome *troubleshootv1beta2.Outcome) string {
+		if outcome.Pass != nil {
+			return "true"
+		}
+		return "false"
+	})
+
+	require.Len(t, actual, 3)
+	assert.Equal(t, "false", actual[0].Fail.When)
+	assert.Equal(t, "> 1", actual[1].Warn.When)
+	assert.Equal(t, "true", actual[2].Pass.When)
+	assert.Equal(t, "pass", actual[2].Pass.Message)
+
+	// the outcomes of the spec are left alone
+	assert.Equal(t, "", outcomes[0].Fail.When)
+	assert.Equal(t, " ", outcomes[2].Pass.When)
+}
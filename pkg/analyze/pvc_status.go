@@ -0,0 +1,243 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AnalyzePVCStatus struct {
+	analyzer *troubleshootv1beta2.PVCStatus
+}
+
+func (a *AnalyzePVCStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "PVC Status")
+}
+
+func (a *AnalyzePVCStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzePVCStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzePVCStatus(a.analyzer, getFile, findFiles)
+}
+
+// pvcState is what outcomes of the pvcStatus analyzer are evaluated against, and what their
+// messages are templated with.
+type pvcState struct {
+	Namespace           string
+	Name                string
+	Phase               string
+	StorageClass        string
+	StorageClassMissing bool
+	Requested           *resource.Quantity
+	Capacity            *resource.Quantity
+	CapacityMismatch    bool
+	// Problems lists everything that is wrong with the claim, e.g. "is Pending"
+	Problems []string
+}
+
+func (s pvcState) healthy() bool {
+	return len(s.Problems) == 0
+}
+
+func analyzePVCStatus(analyzer *troubleshootv1beta2.PVCStatus, getFile func(string) ([]byte, error), getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	fileNames := make([]string, 0)
+	if analyzer.Namespace != "" {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "pvcs", fmt.Sprintf("%s.json", analyzer.Namespace)))
+	}
+	for _, ns := range analyzer.Namespaces {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "pvcs", fmt.Sprintf("%s.json", ns)))
+	}
+
+	if len(fileNames) == 0 {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "pvcs", "*.json"))
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	// storage classes are optional, without them claims can't be checked for missing classes
+	storageClasses, err := getStorageClasses(getFile)
+	if err != nil {
+		storageClasses = nil
+	}
+
+	results := []*AnalyzeResult{}
+	found := false
+	for _, fileName := range fileNames {
+		files, err := getFileContents(fileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected pvcs from file")
+		}
+
+		for _, collected := range files {
+			var pvcs corev1.PersistentVolumeClaimList
+			if err := json.Unmarshal(collected, &pvcs); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal pvc list")
+			}
+
+			for _, pvc := range pvcs.Items {
+				if analyzer.Name != "" && pvc.Name != analyzer.Name {
+					continue
+				}
+				if !labelSelector.Matches(labels.Set(pvc.Labels)) {
+					continue
+				}
+				found = true
+
+				state := getPVCState(&pvc, storageClasses)
+
+				var result *AnalyzeResult
+				if len(analyzer.Outcomes) > 0 {
+					result, err = pvcStatus(analyzer.Outcomes, state)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to process status")
+					}
+				} else {
+					result = getDefaultPVCResult(state)
+				}
+
+				if result != nil {
+					result.InvolvedObject = &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  pvc.Namespace,
+						Name:       pvc.Name,
+					}
+					results = append(results, result)
+				}
+			}
+		}
+	}
+
+	if analyzer.Name != "" && !found {
+		// there's not an error, but maybe the requested claim was never created
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s PVC Status", analyzer.Name),
+				IconKey: "kubernetes_storage_class",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+				IsFail:  true,
+				Message: fmt.Sprintf("The persistent volume claim %q was not found", analyzer.Name),
+			},
+		}, nil
+	}
+
+	return results, nil
+}
+
+// getStorageClasses returns the names of the collected storage classes, and the name of the
+// default storage class under the empty key.
+func getStorageClasses(getFile func(string) ([]byte, error)) (map[string]bool, error) {
+	storageClassesData, err := getFile("cluster-resources/storage-classes.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var storageClasses storagev1.StorageClassList
+	if err := json.Unmarshal(storageClassesData, &storageClasses); err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, storageClass := range storageClasses.Items {
+		names[storageClass.Name] = true
+		if storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			names[""] = true
+		}
+	}
+	return names, nil
+}
+
+func getPVCState(pvc *corev1.PersistentVolumeClaim, storageClasses map[string]bool) pvcState {
+	state := pvcState{
+		Namespace: pvc.Namespace,
+		Name:      pvc.Name,
+		Phase:     string(pvc.Status.Phase),
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimPending, corev1.ClaimLost:
+		state.Problems = append(state.Problems, fmt.Sprintf("is %s", pvc.Status.Phase))
+	}
+
+	explicitClass := false
+	if pvc.Spec.StorageClassName != nil {
+		state.StorageClass = *pvc.Spec.StorageClassName
+		explicitClass = true
+	} else if class, ok := pvc.Annotations[corev1.BetaStorageClassAnnotation]; ok {
+		state.StorageClass = class
+		explicitClass = true
+	}
+
+	if storageClasses != nil {
+		if explicitClass && state.StorageClass != "" && !storageClasses[state.StorageClass] {
+			// an empty class explicitly asks for no dynamic provisioning, so only named classes can be missing
+			state.StorageClassMissing = true
+			state.Problems = append(state.Problems, fmt.Sprintf("uses storage class %q which does not exist", state.StorageClass))
+		} else if !explicitClass && !storageClasses[""] && pvc.Status.Phase == corev1.ClaimPending {
+			state.StorageClassMissing = true
+			state.Problems = append(state.Problems, "uses the default storage class but there is none")
+		}
+	}
+
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		state.Requested = &requested
+	}
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		state.Capacity = &capacity
+	}
+	if pvc.Status.Phase == corev1.ClaimBound && state.Requested != nil && state.Capacity != nil && state.Capacity.Cmp(*state.Requested) < 0 {
+		state.CapacityMismatch = true
+		state.Problems = append(state.Problems, fmt.Sprintf("has a capacity of %s but requested %s", state.Capacity.String(), state.Requested.String()))
+	}
+
+	return state
+}
+
+func pvcStatus(outcomes []*troubleshootv1beta2.Outcome, state pvcState) (*AnalyzeResult, error) {
+	vars := map[string]interface{}{
+		"phase":               state.Phase,
+		"storageClass":        state.StorageClass,
+		"storageClassMissing": state.StorageClassMissing,
+		"capacityMismatch":    state.CapacityMismatch,
+		"healthy":             state.healthy(),
+	}
+	if state.Requested != nil {
+		vars["requested"] = *state.Requested
+	}
+	if state.Capacity != nil {
+		vars["capacity"] = *state.Capacity
+	}
+
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s PVC Status", state.Namespace, state.Name), "kubernetes_storage_class", "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12", compareWhen, state)
+}
+
+func getDefaultPVCResult(state pvcState) *AnalyzeResult {
+	if state.healthy() {
+		return nil
+	}
+
+	return &AnalyzeResult{
+		Title:   fmt.Sprintf("%s/%s PVC Status", state.Namespace, state.Name),
+		IconKey: "kubernetes_storage_class",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+		IsFail:  true,
+		Message: fmt.Sprintf("The persistent volume claim %s/%s %s", state.Namespace, state.Name, strings.Join(state.Problems, ", ")),
+	}
+}
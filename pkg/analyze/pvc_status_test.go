@@ -0,0 +1,335 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzePVCStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.PVCStatus
+		expectResult []*AnalyzeResult
+		files        map[string][]byte
+	}{
+		{
+			name:     "report all unhealthy pvcs",
+			analyzer: troubleshootv1beta2.PVCStatus{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "default/data-pending PVC Status",
+					Message: `The persistent volume claim default/data-pending is Pending, uses storage class "fast" which does not exist`,
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "default",
+						Name:       "data-pending",
+					},
+				},
+				{
+					IsFail:  true,
+					Title:   "default/data-small PVC Status",
+					Message: "The persistent volume claim default/data-small has a capacity of 5Gi but requested 10Gi",
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "default",
+						Name:       "data-small",
+					},
+				},
+				{
+					IsFail:  true,
+					Title:   "monitoring/prometheus-data PVC Status",
+					Message: "The persistent volume claim monitoring/prometheus-data is Lost",
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "monitoring",
+						Name:       "prometheus-data",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/pvcs/default.json":    []byte(defaultPVCs),
+				"cluster-resources/pvcs/monitoring.json": []byte(monitoringPVCs),
+				"cluster-resources/storage-classes.json": []byte(pvcStorageClasses),
+			},
+		},
+		{
+			name: "outcomes with legacy when",
+			analyzer: troubleshootv1beta2.PVCStatus{
+				Namespace: "default",
+				Name:      "data-bound",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    `phase != "Bound"`,
+							Message: "{{ .Name }} is {{ .Phase }}",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    "healthy",
+							Message: "{{ .Name }} is healthy",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					Title:   "default/data-bound PVC Status",
+					Message: "data-bound is healthy",
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "default",
+						Name:       "data-bound",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/pvcs/default.json":    []byte(defaultPVCs),
+				"cluster-resources/storage-classes.json": []byte(pvcStorageClasses),
+			},
+		},
+		{
+			name: "outcomes with expressions",
+			analyzer: troubleshootv1beta2.PVCStatus{
+				Namespace: "default",
+				Selector:  []string{"app=small"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    `phase == "Bound" && capacity < requested`,
+							Message: "{{ .Name }} only has {{ .Capacity }}",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "pass",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:  true,
+					Title:   "default/data-small PVC Status",
+					Message: "data-small only has 5Gi",
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "default",
+						Name:       "data-small",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/pvcs/default.json":    []byte(defaultPVCs),
+				"cluster-resources/storage-classes.json": []byte(pvcStorageClasses),
+			},
+		},
+		{
+			name: "missing storage class is not checked without storage classes",
+			analyzer: troubleshootv1beta2.PVCStatus{
+				Namespace: "default",
+				Name:      "data-pending",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "default/data-pending PVC Status",
+					Message: "The persistent volume claim default/data-pending is Pending",
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+					InvolvedObject: &corev1.ObjectReference{
+						APIVersion: "v1",
+						Kind:       "PersistentVolumeClaim",
+						Namespace:  "default",
+						Name:       "data-pending",
+					},
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/pvcs/default.json": []byte(defaultPVCs),
+			},
+		},
+		{
+			name: "named pvc not found",
+			analyzer: troubleshootv1beta2.PVCStatus{
+				Namespace: "default",
+				Name:      "does-not-exist",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "does-not-exist PVC Status",
+					Message: `The persistent volume claim "does-not-exist" was not found`,
+					IconKey: "kubernetes_storage_class",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/pvcs/default.json": []byte(defaultPVCs),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(n string) ([]byte, error) {
+				if file, ok := test.files[n]; ok {
+					return file, nil
+				}
+				return nil, errors.Errorf("file %s not found", n)
+			}
+
+			getFiles := func(n string) (map[string][]byte, error) {
+				if file, ok := test.files[n]; ok {
+					return map[string][]byte{n: file}, nil
+				}
+				files := map[string][]byte{}
+				for name, file := range test.files {
+					if matched, _ := filepath.Match(n, name); matched {
+						files[name] = file
+					}
+				}
+				return files, nil
+			}
+
+			actual, err := analyzePVCStatus(&test.analyzer, getFile, getFiles)
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var pvcStorageClasses = `{
+  "kind": "StorageClassList",
+  "apiVersion": "storage.k8s.io/v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "standard",
+        "annotations": {
+          "storageclass.kubernetes.io/is-default-class": "true"
+        }
+      },
+      "provisioner": "kubernetes.io/no-provisioner"
+    }
+  ]
+}`
+
+var defaultPVCs = `{
+  "kind": "PersistentVolumeClaimList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "data-bound",
+        "namespace": "default"
+      },
+      "spec": {
+        "storageClassName": "standard",
+        "resources": {
+          "requests": {
+            "storage": "10Gi"
+          }
+        }
+      },
+      "status": {
+        "phase": "Bound",
+        "capacity": {
+          "storage": "10Gi"
+        }
+      }
+    },
+    {
+      "metadata": {
+        "name": "data-pending",
+        "namespace": "default"
+      },
+      "spec": {
+        "storageClassName": "fast",
+        "resources": {
+          "requests": {
+            "storage": "10Gi"
+          }
+        }
+      },
+      "status": {
+        "phase": "Pending"
+      }
+    },
+    {
+      "metadata": {
+        "name": "data-small",
+        "namespace": "default",
+        "labels": {
+          "app": "small"
+        }
+      },
+      "spec": {
+        "resources": {
+          "requests": {
+            "storage": "10Gi"
+          }
+        }
+      },
+      "status": {
+        "phase": "Bound",
+        "capacity": {
+          "storage": "5Gi"
+        }
+      }
+    }
+  ]
+}`
+
+var monitoringPVCs = `{
+  "kind": "PersistentVolumeClaimList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "prometheus-data",
+        "namespace": "monitoring"
+      },
+      "spec": {
+        "storageClassName": "standard",
+        "resources": {
+          "requests": {
+            "storage": "50Gi"
+          }
+        }
+      },
+      "status": {
+        "phase": "Lost"
+      }
+    }
+  ]
+}`
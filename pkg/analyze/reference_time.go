@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// currentTime is what getReferenceTime falls back to for bundles without any timestamps
+var currentTime = time.Now
+
+// referenceTimeFiles are the collected cluster resources with timestamps that are updated while the
+// cluster is running, such as node heartbeats, events and container restarts.
+var referenceTimeFiles = []string{
+	filepath.Join("cluster-resources", "nodes.json"),
+	filepath.Join("cluster-resources", "events", "*.json"),
+	filepath.Join("cluster-resources", "pods", "*.json"),
+	filepath.Join("cluster-resources", "jobs", "*.json"),
+	filepath.Join("cluster-resources", "cronjobs", "*.json"),
+}
+
+// getReferenceTime returns the time that ages such as minutesSinceLastSeen are measured from.
+// Bundles don't record when they were collected, so this is the newest timestamp in the collected
+// cluster resources, which is close to the time of the collection, so that a bundle analyzed days
+// later has the same ages. Every analyzer of a bundle measures from the same time, which is only
+// computed once per bundle.
+func getReferenceTime(objects *collectedFileCache, findFiles getChildCollectedFileContents) (time.Time, error) {
+	reference, err := objects.getObject("cluster-resources", reflect.TypeOf(time.Time{}), func() (interface{}, error) {
+		var latest time.Time
+		for _, pattern := range referenceTimeFiles {
+			files, err := findFiles(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find collected files matching %s", pattern)
+			}
+			for _, contents := range files {
+				var document interface{}
+				if err := json.Unmarshal(contents, &document); err != nil {
+					// not every collected file is json, and those that aren't have no timestamps
+					continue
+				}
+				latest = latestTimestamp(document, latest)
+			}
+		}
+
+		if latest.IsZero() {
+			return currentTime(), nil
+		}
+		return latest, nil
+	})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get the time the bundle was collected")
+	}
+	return reference.(time.Time), nil
+}
+
+// latestTimestamp returns the newest of latest and the timestamps in a decoded json document, which
+// are the fields named like lastHeartbeatTime, lastTimestamp or startedAt. Deletion timestamps are
+// ignored, they may be in the future.
+func latestTimestamp(document interface{}, latest time.Time) time.Time {
+	switch d := document.(type) {
+	case map[string]interface{}:
+		for key, value := range d {
+			if s, ok := value.(string); ok && isTimestampField(key) {
+				if t, err := time.Parse(time.RFC3339, s); err == nil && t.After(latest) {
+					latest = t
+				}
+				continue
+			}
+			latest = latestTimestamp(value, latest)
+		}
+	case []interface{}:
+		for _, item := range d {
+			latest = latestTimestamp(item, latest)
+		}
+	}
+	return latest
+}
+
+func isTimestampField(key string) bool {
+	if key == "deletionTimestamp" {
+		return false
+	}
+	return strings.HasSuffix(key, "Time") || strings.HasSuffix(key, "Timestamp") || key == "startedAt" || key == "finishedAt"
+}
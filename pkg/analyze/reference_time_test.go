@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getReferenceTime(t *testing.T) {
+	collected := time.Date(2022, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		files map[string][]byte
+		want  time.Time
+	}{
+		{
+			name: "newest node heartbeat",
+			files: map[string][]byte{
+				"cluster-resources/nodes.json": []byte(`{"items": [
+					{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-03T11:00:00Z"}]}},
+					{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-03T12:00:00Z"}]}}
+				]}`),
+			},
+			want: collected,
+		},
+		{
+			name: "newest timestamp across resources",
+			files: map[string][]byte{
+				"cluster-resources/nodes.json":          []byte(`{"items": [{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-03T11:00:00Z"}]}}]}`),
+				"cluster-resources/events/default.json": []byte(`{"items": [{"lastTimestamp": "2022-03-03T12:00:00Z"}]}`),
+			},
+			want: collected,
+		},
+		{
+			name: "deletion timestamps are ignored",
+			files: map[string][]byte{
+				"cluster-resources/pods/default.json": []byte(`{"items": [{"metadata": {"creationTimestamp": "2022-03-03T12:00:00Z", "deletionTimestamp": "2022-03-04T12:00:00Z"}}]}`),
+			},
+			want: collected,
+		},
+		{
+			name: "files that aren't json are skipped",
+			files: map[string][]byte{
+				"cluster-resources/nodes.json":          []byte(`{"items": [{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-03T12:00:00Z"}]}}]}`),
+				"cluster-resources/events/default.json": []byte(`not json`),
+			},
+			want: collected,
+		},
+		{
+			name:  "bundle without timestamps",
+			files: map[string][]byte{},
+			want:  time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	defer func(now func() time.Time) { currentTime = now }(currentTime)
+	currentTime = func() time.Time { return time.Date(2022, 3, 5, 0, 0, 0, 0, time.UTC) }
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			findFiles := func(pattern string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, file := range test.files {
+					if matched, _ := filepath.Match(pattern, name); matched {
+						matching[name] = file
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := getReferenceTime(nil, findFiles)
+			req.NoError(err)
+			assert.True(t, test.want.Equal(actual), "got %s", actual)
+		})
+	}
+}
+
+func Test_getReferenceTimeIsSharedByAnalyzers(t *testing.T) {
+	req := require.New(t)
+
+	nodes := []byte(`{"items": [{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-03T12:00:00Z"}]}}]}`)
+	findFiles := func(pattern string) (map[string][]byte, error) {
+		if pattern == filepath.Join("cluster-resources", "nodes.json") {
+			return map[string][]byte{pattern: nodes}, nil
+		}
+		return map[string][]byte{}, nil
+	}
+	objects := newCollectedFileCache(nil, findFiles)
+
+	first, err := getReferenceTime(objects, findFiles)
+	req.NoError(err)
+
+	// later analyzers get the time computed by the first one
+	nodes = []byte(`{"items": [{"status": {"conditions": [{"lastHeartbeatTime": "2022-03-04T12:00:00Z"}]}}]}`)
+	second, err := getReferenceTime(objects, findFiles)
+	req.NoError(err)
+	assert.True(t, first.Equal(second), "got %s and %s", first, second)
+}
@@ -12,6 +12,26 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+type AnalyzeRegistryImages struct {
+	analyzer *troubleshootv1beta2.RegistryImagesAnalyze
+}
+
+func (a *AnalyzeRegistryImages) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, a.analyzer.CollectorName)
+}
+
+func (a *AnalyzeRegistryImages) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeRegistryImages) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeRegistry(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeRegistry(analyzer *troubleshootv1beta2.RegistryImagesAnalyze, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	collectorName := analyzer.CollectorName
 	if collectorName == "" {
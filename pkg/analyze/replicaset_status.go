@@ -13,6 +13,22 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+type AnalyzeReplicaSetStatus struct {
+	analyzer *troubleshootv1beta2.ReplicaSetStatus
+}
+
+func (a *AnalyzeReplicaSetStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "ReplicaSet Status")
+}
+
+func (a *AnalyzeReplicaSetStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeReplicaSetStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeReplicaSetStatus(a.analyzer, findFiles)
+}
+
 func analyzeReplicaSetStatus(analyzer *troubleshootv1beta2.ReplicaSetStatus, getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	if analyzer.Name == "" {
 		return analyzeAllReplicaSetStatuses(analyzer, getFileContents)
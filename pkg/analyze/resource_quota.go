@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type AnalyzeResourceQuota struct {
+	analyzer *troubleshootv1beta2.ResourceQuotaAnalyze
+}
+
+func (a *AnalyzeResourceQuota) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Resource Quota")
+}
+
+func (a *AnalyzeResourceQuota) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeResourceQuota) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeResourceQuota(a.analyzer, findFiles)
+}
+
+// resourceQuotaState is what outcomes of the resourceQuota analyzer are evaluated against, and what
+// their messages are templated with. Resource is the resource closest to its hard limit.
+type resourceQuotaState struct {
+	Namespace string
+	Name      string
+	Resource  string
+	Used      string
+	Hard      string
+	// Usage is the percentage of the hard limit that is used, per resource
+	Usage    map[string]float64
+	MaxUsage float64
+}
+
+func analyzeResourceQuota(analyzer *troubleshootv1beta2.ResourceQuotaAnalyze, getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	fileNames := make([]string, 0)
+	if analyzer.Namespace != "" {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "resourcequotas", fmt.Sprintf("%s.json", analyzer.Namespace)))
+	}
+	for _, ns := range analyzer.Namespaces {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "resourcequotas", fmt.Sprintf("%s.json", ns)))
+	}
+
+	// no namespace specified, so we need to analyze all quotas
+	if len(fileNames) == 0 {
+		fileNames = append(fileNames, filepath.Join("cluster-resources", "resourcequotas", "*.json"))
+	}
+
+	quotas := []corev1.ResourceQuota{}
+	for _, fileName := range fileNames {
+		files, err := getFileContents(fileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected resource quotas from file")
+		}
+
+		for _, collected := range files {
+			var list corev1.ResourceQuotaList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal resource quota list")
+			}
+			for _, quota := range list.Items {
+				if analyzer.Name != "" && quota.Name != analyzer.Name {
+					continue
+				}
+				quotas = append(quotas, quota)
+			}
+		}
+	}
+
+	// resources that one quota limits and another does not are 0% used in the other, so that the same
+	// outcomes can be evaluated against every quota
+	allResources := map[string]bool{}
+	for _, quota := range quotas {
+		for name := range quota.Status.Hard {
+			allResources[string(name)] = true
+		}
+	}
+
+	if analyzer.Name != "" && len(quotas) == 0 {
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s Resource Quota", analyzer.Name),
+				IconKey: "kubernetes_node_resources",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				IsFail:  true,
+				Message: fmt.Sprintf("The resource quota %q was not found", analyzer.Name),
+			},
+		}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, quota := range quotas {
+		state := getResourceQuotaState(&quota)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			var err error
+			result, err = resourceQuotaStatus(analyzer.Outcomes, state, &quota, allResources)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process resource quota")
+			}
+		} else {
+			result = getDefaultResourceQuotaResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "ResourceQuota",
+				Namespace:  quota.Namespace,
+				Name:       quota.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func getResourceQuotaState(quota *corev1.ResourceQuota) resourceQuotaState {
+	state := resourceQuotaState{
+		Namespace: quota.Namespace,
+		Name:      quota.Name,
+		Usage:     map[string]float64{},
+	}
+
+	names := []string{}
+	for name := range quota.Status.Hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		hard := quota.Status.Hard[corev1.ResourceName(name)]
+		used := quota.Status.Used[corev1.ResourceName(name)]
+
+		usage := quotaUsagePercent(used, hard)
+		state.Usage[name] = usage
+		if state.Resource == "" || usage > state.MaxUsage {
+			state.Resource = name
+			state.MaxUsage = usage
+			state.Used = used.String()
+			state.Hard = hard.String()
+		}
+	}
+
+	return state
+}
+
+// quotaUsagePercent returns how much of hard is used, in percent. A hard limit of 0 forbids the
+// resource, which is only a problem when something uses it anyway, e.g. because the quota was
+// lowered afterwards, so that is considered fully used and an unused one not used at all.
+func quotaUsagePercent(used resource.Quantity, hard resource.Quantity) float64 {
+	if hard.IsZero() {
+		if used.Sign() > 0 {
+			return 100
+		}
+		return 0
+	}
+	return used.AsApproximateFloat64() / hard.AsApproximateFloat64() * 100
+}
+
+// resourceQuotaWhenVariables exposes the usage of each resource in percent under the resource's
+// name, e.g. requests.cpu or count/deployments.apps, and the quantities under used.<name> and
+// hard.<name>.
+func resourceQuotaWhenVariables(state resourceQuotaState, quota *corev1.ResourceQuota, allResources map[string]bool) map[string]interface{} {
+	vars := map[string]interface{}{
+		"namespace": state.Namespace,
+		"name":      state.Name,
+		"maxUsage":  state.MaxUsage,
+		"used":      map[string]interface{}{},
+		"hard":      map[string]interface{}{},
+	}
+	for name := range allResources {
+		setNestedWhenVariable(vars, name, state.Usage[name])
+	}
+	for name, quantity := range quota.Status.Used {
+		setNestedWhenVariable(vars["used"].(map[string]interface{}), string(name), quantity)
+	}
+	for name, quantity := range quota.Status.Hard {
+		setNestedWhenVariable(vars["hard"].(map[string]interface{}), string(name), quantity)
+	}
+	return vars
+}
+
+// setNestedWhenVariable sets a variable with a dotted name so that expressions can resolve it.
+func setNestedWhenVariable(vars map[string]interface{}, name string, value interface{}) {
+	parts := strings.Split(name, ".")
+	current := vars
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			if _, exists := current[part]; exists {
+				return
+			}
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	if _, exists := current[parts[len(parts)-1]]; !exists {
+		current[parts[len(parts)-1]] = value
+	}
+}
+
+func resourceQuotaStatus(outcomes []*troubleshootv1beta2.Outcome, state resourceQuotaState, quota *corev1.ResourceQuota, allResources map[string]bool) (*AnalyzeResult, error) {
+	vars := resourceQuotaWhenVariables(state, quota, allResources)
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s Resource Quota", state.Namespace, state.Name), "kubernetes_node_resources", "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18", compareWhen, state)
+}
+
+func getDefaultResourceQuotaResult(state resourceQuotaState) *AnalyzeResult {
+	if state.MaxUsage < 90 {
+		return nil
+	}
+
+	result := &AnalyzeResult{
+		Title:   fmt.Sprintf("%s/%s Resource Quota", state.Namespace, state.Name),
+		IconKey: "kubernetes_node_resources",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+	}
+
+	if state.MaxUsage >= 100 {
+		result.IsFail = true
+		result.Message = fmt.Sprintf("The resource quota %s/%s is exhausted for %s, %s of %s is used", state.Namespace, state.Name, state.Resource, state.Used, state.Hard)
+	} else {
+		result.IsWarn = true
+		result.Message = fmt.Sprintf("The resource quota %s/%s is %.0f%% used for %s, %s of %s is used", state.Namespace, state.Name, state.MaxUsage, state.Resource, state.Used, state.Hard)
+	}
+
+	return result
+}
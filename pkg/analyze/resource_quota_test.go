@@ -0,0 +1,239 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_analyzeResourceQuota(t *testing.T) {
+	quotaRef := func(namespace string, name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "ResourceQuota", Namespace: namespace, Name: name}
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ResourceQuotaAnalyze
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "default results",
+			analyzer: troubleshootv1beta2.ResourceQuotaAnalyze{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "tenant-a/compute Resource Quota",
+					Message:        "The resource quota tenant-a/compute is exhausted for pods, 10 of 10 is used",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: quotaRef("tenant-a", "compute"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "tenant-b/compute Resource Quota",
+					Message:        "The resource quota tenant-b/compute is 95% used for requests.memory, 3900Mi of 4Gi is used",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: quotaRef("tenant-b", "compute"),
+				},
+			},
+		},
+		{
+			name: "percentage of a single resource",
+			analyzer: troubleshootv1beta2.ResourceQuotaAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "requests.cpu > 90%",
+							Message: "{{ .Namespace }} is out of cpu",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "count/deployments.apps >= 50%",
+							Message: "{{ .Namespace }} has many deployments",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Namespace }} has cpu left",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "tenant-a/compute Resource Quota",
+					Message:        "tenant-a has many deployments",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: quotaRef("tenant-a", "compute"),
+				},
+				{
+					IsFail:         true,
+					Title:          "tenant-b/compute Resource Quota",
+					Message:        "tenant-b is out of cpu",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: quotaRef("tenant-b", "compute"),
+				},
+			},
+		},
+		{
+			name: "highest usage and quantities",
+			analyzer: troubleshootv1beta2.ResourceQuotaAnalyze{
+				Namespace: "tenant-b",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "maxUsage >= 100%",
+							Message: "{{ .Resource }} is exhausted",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "used.requests.memory > 3Gi",
+							Message: "more than 3Gi of memory is requested",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "tenant-b/compute Resource Quota",
+					Message:        "more than 3Gi of memory is requested",
+					IconKey:        "kubernetes_node_resources",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+					InvolvedObject: quotaRef("tenant-b", "compute"),
+				},
+			},
+		},
+		{
+			name: "quota not found",
+			analyzer: troubleshootv1beta2.ResourceQuotaAnalyze{
+				Namespace: "tenant-a",
+				Name:      "storage",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:  true,
+					Title:   "storage Resource Quota",
+					Message: `The resource quota "storage" was not found`,
+					IconKey: "kubernetes_node_resources",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/node-resources.svg?w=16&h=18",
+				},
+			},
+		},
+	}
+
+	files := map[string][]byte{
+		"cluster-resources/resourcequotas/tenant-a.json": []byte(tenantAResourceQuotas),
+		"cluster-resources/resourcequotas/tenant-b.json": []byte(tenantBResourceQuotas),
+	}
+	getFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := analyzeResourceQuota(&test.analyzer, getFiles)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+func Test_quotaUsagePercent(t *testing.T) {
+	tests := []struct {
+		name string
+		used string
+		hard string
+		want float64
+	}{
+		{
+			name: "partly used",
+			used: "1500m",
+			hard: "2",
+			want: 75,
+		},
+		{
+			name: "fully used",
+			used: "10",
+			hard: "10",
+			want: 100,
+		},
+		{
+			name: "forbidden and unused",
+			used: "0",
+			hard: "0",
+			want: 0,
+		},
+		{
+			name: "forbidden but used",
+			used: "1",
+			hard: "0",
+			want: 100,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := quotaUsagePercent(resource.MustParse(test.used), resource.MustParse(test.hard))
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+var tenantAResourceQuotas = `{
+  "kind": "ResourceQuotaList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "compute", "namespace": "tenant-a"},
+      "spec": {"hard": {"pods": "10", "requests.cpu": "4", "count/deployments.apps": "4"}},
+      "status": {
+        "hard": {"pods": "10", "requests.cpu": "4", "count/deployments.apps": "4"},
+        "used": {"pods": "10", "requests.cpu": "1500m", "count/deployments.apps": "3"}
+      }
+    }
+  ]
+}`
+
+var tenantBResourceQuotas = `{
+  "kind": "ResourceQuotaList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "compute", "namespace": "tenant-b"},
+      "spec": {"hard": {"requests.cpu": "2", "requests.memory": "4Gi"}},
+      "status": {
+        "hard": {"requests.cpu": "2", "requests.memory": "4Gi"},
+        "used": {"requests.cpu": "1900m", "requests.memory": "3900Mi"}
+      }
+    }
+  ]
+}`
@@ -8,6 +8,26 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+type AnalyzeSecret struct {
+	analyzer *troubleshootv1beta2.AnalyzeSecret
+}
+
+func (a *AnalyzeSecret) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, fmt.Sprintf("Secret %s", a.analyzer.SecretName))
+}
+
+func (a *AnalyzeSecret) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeSecret) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeSecret(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeSecret(analyzer *troubleshootv1beta2.AnalyzeSecret, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	filename := collect.GetSecretFileName(
 		&troubleshootv1beta2.Secret{
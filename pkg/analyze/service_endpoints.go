@@ -0,0 +1,337 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AnalyzeServiceEndpoints struct {
+	analyzer *troubleshootv1beta2.ServiceEndpoints
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeServiceEndpoints) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Service Endpoints")
+}
+
+func (a *AnalyzeServiceEndpoints) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeServiceEndpoints) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeServiceEndpoints) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeServiceEndpoints(a.analyzer, findFiles, a.objects)
+}
+
+// serviceEndpointsState is what outcomes of the serviceEndpoints analyzer are evaluated against,
+// and what their messages are templated with.
+type serviceEndpointsState struct {
+	Namespace   string
+	Name        string
+	Type        string
+	HasSelector bool
+	// MatchingPods is the number of pods matched by the service's selector
+	MatchingPods int
+	Ready        int
+	NotReady     int
+}
+
+func (s serviceEndpointsState) vars() map[string]interface{} {
+	return map[string]interface{}{
+		"namespace":    s.Namespace,
+		"name":         s.Name,
+		"type":         s.Type,
+		"hasSelector":  s.HasSelector,
+		"matchingPods": s.MatchingPods,
+		"ready":        s.Ready,
+		"notReady":     s.NotReady,
+	}
+}
+
+func analyzeServiceEndpoints(analyzer *troubleshootv1beta2.ServiceEndpoints, getFileContents func(string) (map[string][]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	namespaces := []string{}
+	if analyzer.Namespace != "" {
+		namespaces = append(namespaces, analyzer.Namespace)
+	}
+	namespaces = append(namespaces, analyzer.Namespaces...)
+
+	fileNames := []string{}
+	for _, ns := range namespaces {
+		fileNames = append(fileNames, fmt.Sprintf("%s.json", ns))
+	}
+	if len(fileNames) == 0 {
+		fileNames = append(fileNames, "*.json")
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	services := []corev1.Service{}
+	endpointSlices := []discoveryv1.EndpointSlice{}
+	endpoints := []corev1.Endpoints{}
+	collectedEndpointSlices, collectedEndpoints := false, false
+	for _, fileName := range fileNames {
+		files, err := getFileContents(filepath.Join("cluster-resources", "services", fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected services from file")
+		}
+		for _, collected := range files {
+			var list corev1.ServiceList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal service list")
+			}
+			services = append(services, list.Items...)
+		}
+
+		files, err = getFileContents(filepath.Join("cluster-resources", "endpointslices", fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected endpoint slices from file")
+		}
+		for _, collected := range files {
+			collectedEndpointSlices = true
+			var list discoveryv1.EndpointSliceList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal endpoint slice list")
+			}
+			endpointSlices = append(endpointSlices, list.Items...)
+		}
+
+		files, err = getFileContents(filepath.Join("cluster-resources", "endpoints", fileName))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read collected endpoints from file")
+		}
+		for _, collected := range files {
+			collectedEndpoints = true
+			var list corev1.EndpointsList
+			if err := json.Unmarshal(collected, &list); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal endpoints list")
+			}
+			endpoints = append(endpoints, list.Items...)
+		}
+	}
+
+	// endpoint slices are preferred, endpoints are only used when the slices could not be collected
+	var serviceEndpoints map[string][]serviceEndpoint
+	switch {
+	case collectedEndpointSlices:
+		serviceEndpoints = getEndpointSliceEndpoints(endpointSlices)
+	case collectedEndpoints:
+		serviceEndpoints = getEndpointsEndpoints(endpoints)
+	case len(services) > 0:
+		// without either every service would look broken
+		return nil, errors.New("neither endpoint slices nor endpoints were collected")
+	}
+
+	pods, err := readCollectedPods(objects, namespaces, getFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*AnalyzeResult{}
+	found := false
+	for _, service := range services {
+		if analyzer.Name != "" && service.Name != analyzer.Name {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(service.Labels)) {
+			continue
+		}
+		if service.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		found = true
+
+		state := getServiceEndpointsState(&service, serviceEndpoints[service.Namespace+"/"+service.Name], pods)
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = serviceEndpointsStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process service endpoints")
+			}
+		} else {
+			result = getDefaultServiceEndpointsResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Namespace:  service.Namespace,
+				Name:       service.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	if analyzer.Name != "" && !found {
+		// there's not an error, but maybe the requested service is not even deployed
+		return []*AnalyzeResult{
+			{
+				Title:   fmt.Sprintf("%s Service Endpoints", analyzer.Name),
+				IconKey: "kubernetes_deployment_status",                                                  // TODO: need new icon
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", // TODO: need new icon
+				IsFail:  true,
+				Message: fmt.Sprintf("The service %q was not found", analyzer.Name),
+			},
+		}, nil
+	}
+
+	return results, nil
+}
+
+// serviceEndpoint is a single endpoint of a service, from either an endpoint slice or an endpoints object.
+type serviceEndpoint struct {
+	// addressType is IPv4, IPv6 or FQDN. Dual-stack services have an endpoint per address type for
+	// each pod.
+	addressType string
+	// key identifies what backs the endpoint, so that an endpoint that is in more than one endpoint
+	// slice is only counted once
+	key   string
+	ready bool
+}
+
+// getEndpointSliceEndpoints returns the endpoints of the endpoint slices by the namespace/name of
+// their service.
+func getEndpointSliceEndpoints(endpointSlices []discoveryv1.EndpointSlice) map[string][]serviceEndpoint {
+	endpoints := map[string][]serviceEndpoint{}
+	for _, endpointSlice := range endpointSlices {
+		serviceName, ok := endpointSlice.Labels[discoveryv1.LabelServiceName]
+		if !ok {
+			continue
+		}
+		service := endpointSlice.Namespace + "/" + serviceName
+		for _, endpoint := range endpointSlice.Endpoints {
+			endpoints[service] = append(endpoints[service], serviceEndpoint{
+				addressType: string(endpointSlice.AddressType),
+				key:         serviceEndpointKey(endpoint.TargetRef, endpoint.Addresses),
+				// a nil ready condition is to be interpreted as ready
+				ready: endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+			})
+		}
+	}
+	return endpoints
+}
+
+// getEndpointsEndpoints returns the addresses of the endpoints objects by the namespace/name of their
+// service, which is the same as that of the endpoints object.
+func getEndpointsEndpoints(endpointsList []corev1.Endpoints) map[string][]serviceEndpoint {
+	endpoints := map[string][]serviceEndpoint{}
+	for _, e := range endpointsList {
+		service := e.Namespace + "/" + e.Name
+		for _, subset := range e.Subsets {
+			for i, addresses := range [][]corev1.EndpointAddress{subset.Addresses, subset.NotReadyAddresses} {
+				for _, address := range addresses {
+					addressType := string(discoveryv1.AddressTypeIPv6)
+					if ip := net.ParseIP(address.IP); ip != nil && ip.To4() != nil {
+						addressType = string(discoveryv1.AddressTypeIPv4)
+					}
+					endpoints[service] = append(endpoints[service], serviceEndpoint{
+						addressType: addressType,
+						key:         serviceEndpointKey(address.TargetRef, []string{address.IP}),
+						ready:       i == 0,
+					})
+				}
+			}
+		}
+	}
+	return endpoints
+}
+
+func serviceEndpointKey(targetRef *corev1.ObjectReference, addresses []string) string {
+	if targetRef != nil {
+		return strings.Join([]string{targetRef.Kind, targetRef.Namespace, targetRef.Name}, "/")
+	}
+	sorted := append([]string{}, addresses...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func getServiceEndpointsState(service *corev1.Service, endpoints []serviceEndpoint, pods []corev1.Pod) serviceEndpointsState {
+	state := serviceEndpointsState{
+		Namespace:   service.Namespace,
+		Name:        service.Name,
+		Type:        string(service.Spec.Type),
+		HasSelector: len(service.Spec.Selector) > 0,
+	}
+
+	// endpoints are counted per address type and the address type with the most ready endpoints is
+	// reported, so that the pods of a dual-stack service are not counted twice
+	ready := map[string]map[string]bool{}
+	for _, endpoint := range endpoints {
+		if ready[endpoint.addressType] == nil {
+			ready[endpoint.addressType] = map[string]bool{}
+		}
+		// an endpoint that is ready in one slice but not yet in another counts as ready
+		ready[endpoint.addressType][endpoint.key] = ready[endpoint.addressType][endpoint.key] || endpoint.ready
+	}
+	for _, keys := range ready {
+		readyCount, notReadyCount := 0, 0
+		for _, isReady := range keys {
+			if isReady {
+				readyCount++
+			} else {
+				notReadyCount++
+			}
+		}
+		if readyCount > state.Ready || (readyCount == state.Ready && notReadyCount > state.NotReady) {
+			state.Ready, state.NotReady = readyCount, notReadyCount
+		}
+	}
+
+	if state.HasSelector {
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		for _, pod := range pods {
+			if pod.Namespace == service.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				state.MatchingPods++
+			}
+		}
+	}
+
+	return state
+}
+
+func serviceEndpointsStatus(outcomes []*troubleshootv1beta2.Outcome, state serviceEndpointsState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("%s/%s Service Endpoints", state.Namespace, state.Name), "kubernetes_deployment_status", "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", compareWhen, state)
+}
+
+func getDefaultServiceEndpointsResult(state serviceEndpointsState) *AnalyzeResult {
+	if state.Ready > 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("The service %s/%s has no ready endpoints", state.Namespace, state.Name)
+	if state.HasSelector && state.MatchingPods == 0 {
+		message = fmt.Sprintf("%s and its selector matches no pods", message)
+	} else if state.NotReady > 0 {
+		message = fmt.Sprintf("%s, %d endpoints are not ready", message, state.NotReady)
+	}
+
+	return &AnalyzeResult{
+		Title:   fmt.Sprintf("%s/%s Service Endpoints", state.Namespace, state.Name),
+		IconKey: "kubernetes_deployment_status",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+		IsFail:  true,
+		Message: message,
+	}
+}
@@ -0,0 +1,332 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeServiceEndpoints(t *testing.T) {
+	serviceRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: name}
+	}
+	readyCountOutcomes := []*troubleshootv1beta2.Outcome{
+		{
+			Pass: &troubleshootv1beta2.SingleOutcome{
+				Message: "{{ .Ready }} ready, {{ .NotReady }} not ready",
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		analyzer     troubleshootv1beta2.ServiceEndpoints
+		files        map[string][]byte
+		expectResult []*AnalyzeResult
+		wantErr      bool
+	}{
+		{
+			name:     "services without ready endpoints",
+			analyzer: troubleshootv1beta2.ServiceEndpoints{},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api Service Endpoints",
+					Message:        "The service default/api has no ready endpoints, 2 endpoints are not ready",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("api"),
+				},
+				{
+					IsFail:         true,
+					Title:          "default/web Service Endpoints",
+					Message:        "The service default/web has no ready endpoints and its selector matches no pods",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("web"),
+				},
+			},
+		},
+		{
+			name: "outcomes on ready and not ready counts",
+			analyzer: troubleshootv1beta2.ServiceEndpoints{
+				Namespace: "default",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "ready == 0",
+							Message: "{{ .Name }} has {{ .NotReady }} not ready endpoints",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "notReady > 0",
+							Message: "{{ .Name }} is degraded",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Name }} has {{ .Ready }} ready endpoints",
+						},
+					},
+				},
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "default/api Service Endpoints",
+					Message:        "api has 2 not ready endpoints",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("api"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "default/db Service Endpoints",
+					Message:        "db is degraded",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("db"),
+				},
+				{
+					IsFail:         true,
+					Title:          "default/web Service Endpoints",
+					Message:        "web has 0 not ready endpoints",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("web"),
+				},
+			},
+		},
+		{
+			name: "dual-stack endpoint slices",
+			analyzer: troubleshootv1beta2.ServiceEndpoints{
+				Namespace: "default",
+				Name:      "db",
+				Outcomes:  readyCountOutcomes,
+			},
+			files: map[string][]byte{
+				"cluster-resources/services/default.json":       []byte(defaultServices),
+				"cluster-resources/endpointslices/default.json": []byte(dualStackEndpointSlices),
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "default/db Service Endpoints",
+					Message:        "2 ready, 1 not ready",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("db"),
+				},
+			},
+		},
+		{
+			name: "endpoints when endpoint slices were not collected",
+			analyzer: troubleshootv1beta2.ServiceEndpoints{
+				Namespace: "default",
+				Name:      "db",
+				Outcomes:  readyCountOutcomes,
+			},
+			files: map[string][]byte{
+				"cluster-resources/services/default.json":  []byte(defaultServices),
+				"cluster-resources/endpoints/default.json": []byte(defaultEndpoints),
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "default/db Service Endpoints",
+					Message:        "1 ready, 1 not ready",
+					IconKey:        "kubernetes_deployment_status",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+					InvolvedObject: serviceRef("db"),
+				},
+			},
+		},
+		{
+			name: "neither endpoint slices nor endpoints collected",
+			analyzer: troubleshootv1beta2.ServiceEndpoints{
+				Namespace: "default",
+			},
+			files: map[string][]byte{
+				"cluster-resources/services/default.json": []byte(defaultServices),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			files := test.files
+			if files == nil {
+				files = map[string][]byte{
+					"cluster-resources/services/default.json":       []byte(defaultServices),
+					"cluster-resources/endpointslices/default.json": []byte(defaultEndpointSlices),
+					"cluster-resources/pods/default.json":           []byte(defaultServicePods),
+				}
+			}
+			getFiles := func(n string) (map[string][]byte, error) {
+				matching := map[string][]byte{}
+				for name, file := range files {
+					if matched, _ := filepath.Match(n, name); matched {
+						matching[name] = file
+					}
+				}
+				return matching, nil
+			}
+
+			actual, err := analyzeServiceEndpoints(&test.analyzer, getFiles, nil)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var defaultServices = `{
+  "kind": "ServiceList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "api", "namespace": "default"},
+      "spec": {"type": "ClusterIP", "selector": {"app": "api"}}
+    },
+    {
+      "metadata": {"name": "db", "namespace": "default"},
+      "spec": {"type": "ClusterIP", "selector": {"app": "db"}}
+    },
+    {
+      "metadata": {"name": "web", "namespace": "default"},
+      "spec": {"type": "ClusterIP", "selector": {"app": "web"}}
+    },
+    {
+      "metadata": {"name": "external", "namespace": "default"},
+      "spec": {"type": "ExternalName", "externalName": "example.com"}
+    }
+  ]
+}`
+
+var defaultEndpointSlices = `{
+  "kind": "EndpointSliceList",
+  "apiVersion": "discovery.k8s.io/v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "api-x7k2p",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "api"}
+      },
+      "addressType": "IPv4",
+      "endpoints": [
+        {"addresses": ["10.0.0.1"], "conditions": {"ready": false}},
+        {"addresses": ["10.0.0.2"], "conditions": {"ready": false}}
+      ]
+    },
+    {
+      "metadata": {
+        "name": "db-8f9qz",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "db"}
+      },
+      "addressType": "IPv4",
+      "endpoints": [
+        {"addresses": ["10.0.0.3"], "conditions": {"ready": true}},
+        {"addresses": ["10.0.0.4"], "conditions": {}},
+        {"addresses": ["10.0.0.5"], "conditions": {"ready": false}}
+      ]
+    },
+    {
+      "metadata": {
+        "name": "web-2mz8c",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "web"}
+      },
+      "addressType": "IPv4",
+      "endpoints": null
+    }
+  ]
+}`
+
+// every db pod is in an IPv4 and an IPv6 slice, and db-0 is also in a second IPv4 slice
+var dualStackEndpointSlices = `{
+  "kind": "EndpointSliceList",
+  "apiVersion": "discovery.k8s.io/v1",
+  "items": [
+    {
+      "metadata": {
+        "name": "db-ipv4-a",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "db"}
+      },
+      "addressType": "IPv4",
+      "endpoints": [
+        {"addresses": ["10.0.0.3"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-0"}},
+        {"addresses": ["10.0.0.4"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-1"}},
+        {"addresses": ["10.0.0.5"], "conditions": {"ready": false}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-2"}}
+      ]
+    },
+    {
+      "metadata": {
+        "name": "db-ipv4-b",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "db"}
+      },
+      "addressType": "IPv4",
+      "endpoints": [
+        {"addresses": ["10.0.0.3"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-0"}}
+      ]
+    },
+    {
+      "metadata": {
+        "name": "db-ipv6",
+        "namespace": "default",
+        "labels": {"kubernetes.io/service-name": "db"}
+      },
+      "addressType": "IPv6",
+      "endpoints": [
+        {"addresses": ["fd00::3"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-0"}},
+        {"addresses": ["fd00::4"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-1"}},
+        {"addresses": ["fd00::5"], "conditions": {"ready": false}, "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-2"}}
+      ]
+    }
+  ]
+}`
+
+var defaultEndpoints = `{
+  "kind": "EndpointsList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "db", "namespace": "default"},
+      "subsets": [
+        {
+          "addresses": [{"ip": "10.0.0.3", "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-0"}}],
+          "notReadyAddresses": [{"ip": "10.0.0.5", "targetRef": {"kind": "Pod", "namespace": "default", "name": "db-2"}}],
+          "ports": [{"port": 5432}]
+        }
+      ]
+    }
+  ]
+}`
+
+var defaultServicePods = `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {"metadata": {"name": "api-0", "namespace": "default", "labels": {"app": "api"}}},
+    {"metadata": {"name": "api-1", "namespace": "default", "labels": {"app": "api"}}},
+    {"metadata": {"name": "db-0", "namespace": "default", "labels": {"app": "db"}}}
+  ]
+}`
@@ -10,6 +10,22 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 )
 
+type AnalyzeStatefulsetStatus struct {
+	analyzer *troubleshootv1beta2.StatefulsetStatus
+}
+
+func (a *AnalyzeStatefulsetStatus) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Statefulset Status")
+}
+
+func (a *AnalyzeStatefulsetStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeStatefulsetStatus) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeStatefulsetStatus(a.analyzer, findFiles)
+}
+
 func analyzeStatefulsetStatus(analyzer *troubleshootv1beta2.StatefulsetStatus, getFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	if analyzer.Name == "" {
 		return analyzeAllStatefulsetStatuses(analyzer, getFileContents)
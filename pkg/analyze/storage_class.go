@@ -8,6 +8,32 @@ import (
 	storagev1beta1 "k8s.io/api/storage/v1beta1"
 )
 
+type AnalyzeStorageClass struct {
+	analyzer *troubleshootv1beta2.StorageClass
+}
+
+func (a *AnalyzeStorageClass) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	if a.analyzer.StorageClassName != "" {
+		return fmt.Sprintf("Storage class %s", a.analyzer.StorageClassName)
+	}
+	return "Default Storage Class"
+}
+
+func (a *AnalyzeStorageClass) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeStorageClass) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeStorageClass(a.analyzer, getFile)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 func analyzeStorageClass(analyzer *troubleshootv1beta2.StorageClass, getCollectedFileContents func(string) ([]byte, error)) (*AnalyzeResult, error) {
 	storageClassesData, err := getCollectedFileContents("cluster-resources/storage-classes.json")
 	if err != nil {
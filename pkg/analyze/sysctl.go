@@ -11,8 +11,32 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/expression"
 )
 
+type AnalyzeSysctl struct {
+	analyzer *troubleshootv1beta2.SysctlAnalyze
+}
+
+func (a *AnalyzeSysctl) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Sysctl")
+}
+
+func (a *AnalyzeSysctl) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeSysctl) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	result, err := analyzeSysctl(a.analyzer, findFiles)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
 // The when condition for outcomes in this analyzer is interpreted as "for some node".
 // For example, "when: net.ipv4.ip_forward = 0" is true if at least one node has IP forwarding
 // disabled.
@@ -126,31 +150,65 @@ func evalSysctlOutcome(nodeParams map[string]map[string]string, outcome *trouble
 var sysctlWhenRX = regexp.MustCompile(`([^\s]+)\s+(=+)\s+(.+)`)
 
 // Returns the list of node names the condition is true for. The condition is not considered true
-// if the parameter is missing for the node.
+// if a parameter it refers to is missing for the node.
 func evalSysctlWhen(nodeParams map[string]map[string]string, when string) ([]string, error) {
+	var nodes []string
+
+	for nodeName, params := range nodeParams {
+		params := params
+		isMatch, err := evaluateWhen(when, nil, sysctlWhenVariables(params), func(when string) (bool, error) {
+			return compareSysctlParam(params, when)
+		})
+		if expression.IsUndefinedVariable(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isMatch {
+			nodes = append(nodes, nodeName)
+		}
+	}
+
+	sort.Strings(nodes)
+
+	return nodes, nil
+}
+
+func compareSysctlParam(params map[string]string, when string) (bool, error) {
 	matches := sysctlWhenRX.FindStringSubmatch(when)
 	if len(matches) != 4 {
-		return nil, fmt.Errorf("Failed to parse when %q", when)
+		return false, fmt.Errorf("Failed to parse when %q", when)
 	}
 
 	switch matches[2] {
 	case "=", "==", "===":
-		var nodes []string
+		nodeValue, ok := params[matches[1]]
+		if !ok {
+			return false, nil
+		}
+		return nodeValue == strings.TrimSpace(matches[3]), nil
+	default:
+		return false, fmt.Errorf("Unknown operator %q", matches[2])
+	}
+}
 
-		for nodeName, params := range nodeParams {
-			nodeValue, ok := params[matches[1]]
+// sysctlWhenVariables nests the parameters of a node by the parts of their names, so that the
+// expression language resolves e.g. net.ipv4.ip_forward.
+func sysctlWhenVariables(params map[string]string) map[string]interface{} {
+	vars := map[string]interface{}{}
+	for name, value := range params {
+		current := vars
+		parts := strings.Split(name, ".")
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
 			if !ok {
-				continue
-			}
-			if nodeValue == strings.TrimSpace(matches[3]) {
-				nodes = append(nodes, nodeName)
+				next = map[string]interface{}{}
+				current[part] = next
 			}
+			current = next
 		}
-
-		sort.Strings(nodes)
-
-		return nodes, nil
-	default:
-		return nil, fmt.Errorf("Unknown operator %q", matches[2])
+		current[parts[len(parts)-1]] = value
 	}
+	return vars
 }
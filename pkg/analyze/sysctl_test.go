@@ -69,6 +69,25 @@ func TestEvalSysctlWhen(t *testing.T) {
 			expect:    []string{"node-b"},
 			expectErr: false,
 		},
+		{
+			name: "Expression over two parameters",
+			when: "net.ipv4.ip_forward == 0 || net.bridge.bridge-nf-call-iptables == 0",
+			nodeParams: map[string]map[string]string{
+				"node-a": {"net.ipv4.ip_forward": "1", "net.bridge.bridge-nf-call-iptables": "1"},
+				"node-b": {"net.ipv4.ip_forward": "1", "net.bridge.bridge-nf-call-iptables": "0"},
+				"node-c": {"net.ipv4.ip_forward": "1"},
+			},
+			expect:    []string{"node-b"},
+			expectErr: false,
+		},
+		{
+			name: "Unparseable when",
+			when: "net.ipv4.ip_forward >",
+			nodeParams: map[string]map[string]string{
+				"node-a": {"net.ipv4.ip_forward": "1"},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, test := range tests {
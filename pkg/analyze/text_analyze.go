@@ -13,6 +13,22 @@ import (
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
+type AnalyzeTextAnalyze struct {
+	analyzer *troubleshootv1beta2.TextAnalyze
+}
+
+func (a *AnalyzeTextAnalyze) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, a.analyzer.CollectorName)
+}
+
+func (a *AnalyzeTextAnalyze) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeTextAnalyze) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeTextAnalyze(a.analyzer, findFiles)
+}
+
 func analyzeTextAnalyze(analyzer *troubleshootv1beta2.TextAnalyze, getCollectedFileContents func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
 	fullPath := filepath.Join(analyzer.CollectorName, analyzer.FileName)
 	collected, err := getCollectedFileContents(fullPath)
@@ -161,10 +177,17 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 		}
 	}
 
+	vars := map[string]interface{}{}
+	for k, v := range foundMatches {
+		vars[k] = v
+	}
+
 	// allow fallthrough
 	for _, outcome := range outcomes {
 		if outcome.Fail != nil {
-			isMatch, err := compareRegex(outcome.Fail.When, foundMatches)
+			isMatch, err := evaluateWhen(outcome.Fail.When, nil, vars, func(when string) (bool, error) {
+				return compareRegex(when, foundMatches)
+			})
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex fail conditional")
 			}
@@ -181,7 +204,9 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 				return result, nil
 			}
 		} else if outcome.Warn != nil {
-			isMatch, err := compareRegex(outcome.Warn.When, foundMatches)
+			isMatch, err := evaluateWhen(outcome.Warn.When, nil, vars, func(when string) (bool, error) {
+				return compareRegex(when, foundMatches)
+			})
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex warn conditional")
 			}
@@ -198,7 +223,9 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 				return result, nil
 			}
 		} else if outcome.Pass != nil {
-			isMatch, err := compareRegex(outcome.Pass.When, foundMatches)
+			isMatch, err := evaluateWhen(outcome.Pass.When, nil, vars, func(when string) (bool, error) {
+				return compareRegex(when, foundMatches)
+			})
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex pass conditional")
 			}
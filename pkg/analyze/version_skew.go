@@ -0,0 +1,291 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AnalyzeVersionSkew struct {
+	analyzer *troubleshootv1beta2.VersionSkew
+	objects  *collectedFileCache
+}
+
+func (a *AnalyzeVersionSkew) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Version Skew")
+}
+
+func (a *AnalyzeVersionSkew) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeVersionSkew) setCollectedObjects(objects *collectedFileCache) {
+	a.objects = objects
+}
+
+func (a *AnalyzeVersionSkew) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeVersionSkew(a.analyzer, getFile, a.objects)
+}
+
+// versionSkewState is what outcomes of the versionSkew analyzer are evaluated against, and what their
+// messages are templated with, for every node. Skews are the number of minor versions a component
+// is older than the API server, and negative when it is newer. KubeletVersions and
+// ContainerRuntimeVersions are the distinct versions on all nodes.
+type versionSkewState struct {
+	Name                     string
+	APIServerVersion         string
+	KubeletVersion           string
+	KubeProxyVersion         string
+	ContainerRuntimeVersion  string
+	KubeletSkew              int
+	KubeProxySkew            int
+	MaxSkew                  int
+	OutOfPolicy              bool
+	KubeletVersions          []string
+	ContainerRuntimeVersions []string
+}
+
+func (s versionSkewState) vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"name":                         s.Name,
+		"apiServerVersion":             s.APIServerVersion,
+		"kubeletVersion":               s.KubeletVersion,
+		"containerRuntimeVersion":      s.ContainerRuntimeVersion,
+		"kubeletSkew":                  s.KubeletSkew,
+		"maxSkew":                      s.MaxSkew,
+		"outOfPolicy":                  s.OutOfPolicy,
+		"kubeletVersionCount":          len(s.KubeletVersions),
+		"containerRuntimeVersionCount": len(s.ContainerRuntimeVersions),
+		"mixedVersions":                len(s.KubeletVersions) > 1,
+		"mixedRuntimes":                len(s.ContainerRuntimeVersions) > 1,
+	}
+	if s.KubeProxyVersion != "" {
+		vars["kubeProxyVersion"] = s.KubeProxyVersion
+		vars["kubeProxySkew"] = s.KubeProxySkew
+	}
+	return vars
+}
+
+func analyzeVersionSkew(analyzer *troubleshootv1beta2.VersionSkew, getCollectedFileContents func(string) ([]byte, error), objects *collectedFileCache) ([]*AnalyzeResult, error) {
+	clusterInfo, err := getCollectedFileContents("cluster-info/cluster_version.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contents of cluster_version.json")
+	}
+
+	collectorClusterVersion := collect.ClusterVersion{}
+	if err := json.Unmarshal(clusterInfo, &collectorClusterVersion); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cluster_version.json")
+	}
+
+	apiServerVersion, err := semver.ParseTolerant(collectorClusterVersion.String)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse semver from cluster_version.json")
+	}
+
+	nodes, err := readCollectedNodes(objects, getCollectedFileContents)
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := labels.Parse(strings.Join(analyzer.Selector, ","))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse selector")
+	}
+
+	matchingNodes := []corev1.Node{}
+	for _, node := range nodes.Items {
+		if labelSelector.Matches(labels.Set(node.Labels)) {
+			matchingNodes = append(matchingNodes, node)
+		}
+	}
+	sort.Slice(matchingNodes, func(i, j int) bool {
+		return matchingNodes[i].Name < matchingNodes[j].Name
+	})
+
+	kubeletVersions := map[string][]string{}
+	runtimeVersions := map[string][]string{}
+	for _, node := range matchingNodes {
+		kubeletVersions[node.Status.NodeInfo.KubeletVersion] = append(kubeletVersions[node.Status.NodeInfo.KubeletVersion], node.Name)
+		runtimeVersions[node.Status.NodeInfo.ContainerRuntimeVersion] = append(runtimeVersions[node.Status.NodeInfo.ContainerRuntimeVersion], node.Name)
+	}
+
+	results := []*AnalyzeResult{}
+	for _, node := range matchingNodes {
+		state, err := getVersionSkewState(&node, apiServerVersion, collectorClusterVersion.String, kubeletVersions, runtimeVersions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get versions of node %s", node.Name)
+		}
+
+		var result *AnalyzeResult
+		if len(analyzer.Outcomes) > 0 {
+			result, err = versionSkewStatus(analyzer.Outcomes, state)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to process version skew")
+			}
+		} else {
+			result = getDefaultVersionSkewResult(state)
+		}
+
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	// versions that differ between nodes are reported once rather than for every node, unless there
+	// are outcomes to evaluate them with
+	if len(analyzer.Outcomes) == 0 {
+		if len(kubeletVersions) > 1 {
+			results = append(results, &AnalyzeResult{
+				Title:   "Kubelet Versions",
+				IconKey: "kubernetes_cluster_version",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				IsWarn:  true,
+				Message: fmt.Sprintf("Nodes run different kubelet versions: %s", describeNodeVersions(kubeletVersions)),
+			})
+		}
+		if len(runtimeVersions) > 1 {
+			results = append(results, &AnalyzeResult{
+				Title:   "Container Runtime Versions",
+				IconKey: "kubernetes_cluster_version",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				IsWarn:  true,
+				Message: fmt.Sprintf("Nodes run different container runtime versions: %s", describeNodeVersions(runtimeVersions)),
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// maxKubeletSkew returns how many minor versions older than the API server kubelets and kube-proxy
+// may be, which is three since Kubernetes 1.28 and two before.
+func maxKubeletSkew(apiServerVersion semver.Version) int {
+	if apiServerVersion.Major == 1 && apiServerVersion.Minor < 28 {
+		return 2
+	}
+	return 3
+}
+
+func getVersionSkewState(node *corev1.Node, apiServerVersion semver.Version, apiServerVersionString string, kubeletVersions map[string][]string, runtimeVersions map[string][]string) (versionSkewState, error) {
+	nodeInfo := node.Status.NodeInfo
+	state := versionSkewState{
+		Name:                    node.Name,
+		APIServerVersion:        apiServerVersionString,
+		KubeletVersion:          nodeInfo.KubeletVersion,
+		KubeProxyVersion:        nodeInfo.KubeProxyVersion,
+		ContainerRuntimeVersion: nodeInfo.ContainerRuntimeVersion,
+		MaxSkew:                 maxKubeletSkew(apiServerVersion),
+	}
+
+	for version := range kubeletVersions {
+		state.KubeletVersions = append(state.KubeletVersions, version)
+	}
+	sort.Strings(state.KubeletVersions)
+	for version := range runtimeVersions {
+		state.ContainerRuntimeVersions = append(state.ContainerRuntimeVersions, version)
+	}
+	sort.Strings(state.ContainerRuntimeVersions)
+
+	kubeletVersion, err := semver.ParseTolerant(nodeInfo.KubeletVersion)
+	if err != nil {
+		return state, errors.Wrapf(err, "failed to parse kubelet version %s", nodeInfo.KubeletVersion)
+	}
+	state.KubeletSkew = minorVersionSkew(apiServerVersion, kubeletVersion)
+	state.OutOfPolicy = state.KubeletSkew < 0 || state.KubeletSkew > state.MaxSkew
+
+	// kube-proxy is not reported by every node, e.g. when it is replaced by the network plugin
+	if nodeInfo.KubeProxyVersion != "" {
+		kubeProxyVersion, err := semver.ParseTolerant(nodeInfo.KubeProxyVersion)
+		if err != nil {
+			return state, errors.Wrapf(err, "failed to parse kube-proxy version %s", nodeInfo.KubeProxyVersion)
+		}
+		state.KubeProxySkew = minorVersionSkew(apiServerVersion, kubeProxyVersion)
+		state.OutOfPolicy = state.OutOfPolicy || state.KubeProxySkew < 0 || state.KubeProxySkew > state.MaxSkew
+	}
+
+	return state, nil
+}
+
+// minorVersionSkew returns how many minor versions older than the API server a component is. A
+// different major version is never within the policy, so it is counted as 100 minor versions.
+func minorVersionSkew(apiServerVersion semver.Version, version semver.Version) int {
+	if apiServerVersion.Major != version.Major {
+		return (int(apiServerVersion.Major) - int(version.Major)) * 100
+	}
+	return int(apiServerVersion.Minor) - int(version.Minor)
+}
+
+// describeNodeVersions lists versions with how many nodes run them, e.g. "v1.27.4 on 2 nodes, v1.28.1 on 1 node".
+func describeNodeVersions(versions map[string][]string) string {
+	sorted := []string{}
+	for version := range versions {
+		sorted = append(sorted, version)
+	}
+	sort.Strings(sorted)
+
+	descriptions := []string{}
+	for _, version := range sorted {
+		nodes := "nodes"
+		if len(versions[version]) == 1 {
+			nodes = "node"
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s on %d %s", version, len(versions[version]), nodes))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func versionSkewStatus(outcomes []*troubleshootv1beta2.Outcome, state versionSkewState) (*AnalyzeResult, error) {
+	vars := state.vars()
+	compareWhen := func(when string) (bool, error) {
+		return evaluateWhen(when, nil, vars, nil)
+	}
+
+	return evaluateOutcomes(outcomes, fmt.Sprintf("Node %s Version Skew", state.Name), "kubernetes_cluster_version", "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16", compareWhen, state)
+}
+
+func getDefaultVersionSkewResult(state versionSkewState) *AnalyzeResult {
+	if !state.OutOfPolicy {
+		return nil
+	}
+
+	messages := []string{}
+	if message := describeVersionSkew("kubelet", state.KubeletVersion, state.KubeletSkew, state); message != "" {
+		messages = append(messages, message)
+	}
+	if state.KubeProxyVersion != "" {
+		if message := describeVersionSkew("kube-proxy", state.KubeProxyVersion, state.KubeProxySkew, state); message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	return &AnalyzeResult{
+		Title:   fmt.Sprintf("Node %s Version Skew", state.Name),
+		IconKey: "kubernetes_cluster_version",
+		IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+		IsFail:  true,
+		Message: strings.Join(messages, ". "),
+	}
+}
+
+func describeVersionSkew(component string, version string, skew int, state versionSkewState) string {
+	if skew < 0 {
+		return fmt.Sprintf("The %s on node %s (%s) is newer than the API server (%s)", component, state.Name, version, state.APIServerVersion)
+	}
+	if skew > state.MaxSkew {
+		return fmt.Sprintf("The %s on node %s (%s) is %d minor versions older than the API server (%s), at most %d are supported", component, state.Name, version, skew, state.APIServerVersion, state.MaxSkew)
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_analyzeVersionSkew(t *testing.T) {
+	nodeRef := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: name}
+	}
+
+	tests := []struct {
+		name           string
+		analyzer       troubleshootv1beta2.VersionSkew
+		clusterVersion string
+		expectResult   []*AnalyzeResult
+		wantErr        bool
+	}{
+		{
+			name:           "default results",
+			analyzer:       troubleshootv1beta2.VersionSkew{},
+			clusterVersion: `{"info": {"major": "1", "minor": "28", "gitVersion": "v1.28.3"}, "string": "v1.28.3"}`,
+			expectResult: []*AnalyzeResult{
+				{
+					IsFail:         true,
+					Title:          "Node worker-1 Version Skew",
+					Message:        "The kubelet on node worker-1 (v1.24.17) is 4 minor versions older than the API server (v1.28.3), at most 3 are supported. The kube-proxy on node worker-1 (v1.24.17) is 4 minor versions older than the API server (v1.28.3), at most 3 are supported",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-1"),
+				},
+				{
+					IsFail:         true,
+					Title:          "Node worker-2 Version Skew",
+					Message:        "The kubelet on node worker-2 (v1.29.0) is newer than the API server (v1.28.3)",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-2"),
+				},
+				{
+					IsWarn:  true,
+					Title:   "Kubelet Versions",
+					Message: "Nodes run different kubelet versions: v1.24.17 on 1 node, v1.28.3 on 1 node, v1.29.0 on 1 node",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+				{
+					IsWarn:  true,
+					Title:   "Container Runtime Versions",
+					Message: "Nodes run different container runtime versions: containerd://1.6.8 on 1 node, containerd://1.7.2 on 2 nodes",
+					IconKey: "kubernetes_cluster_version",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+				},
+			},
+		},
+		{
+			name: "skew and mixed versions",
+			analyzer: troubleshootv1beta2.VersionSkew{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "kubeletSkew < 0",
+							Message: "{{ .Name }} runs {{ .KubeletVersion }}, which is newer than {{ .APIServerVersion }}",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "kubeletSkew >= 2",
+							Message: "{{ .Name }} is {{ .KubeletSkew }} minor versions behind",
+						},
+					},
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "mixedVersions",
+							Message: "{{ .Name }} is up to date, but nodes run {{ .KubeletVersions }}",
+						},
+					},
+				},
+			},
+			clusterVersion: `{"info": {"major": "1", "minor": "28", "gitVersion": "v1.28.3"}, "string": "v1.28.3"}`,
+			expectResult: []*AnalyzeResult{
+				{
+					IsWarn:         true,
+					Title:          "Node control-plane Version Skew",
+					Message:        "control-plane is up to date, but nodes run [v1.24.17 v1.28.3 v1.29.0]",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("control-plane"),
+				},
+				{
+					IsWarn:         true,
+					Title:          "Node worker-1 Version Skew",
+					Message:        "worker-1 is 4 minor versions behind",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-1"),
+				},
+				{
+					IsFail:         true,
+					Title:          "Node worker-2 Version Skew",
+					Message:        "worker-2 runs v1.29.0, which is newer than v1.28.3",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-2"),
+				},
+			},
+		},
+		{
+			name: "two minor versions before 1.28",
+			analyzer: troubleshootv1beta2.VersionSkew{
+				Selector: []string{"node-role.kubernetes.io/worker"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "outOfPolicy",
+							Message: "{{ .Name }} is out of policy, at most {{ .MaxSkew }} minor versions are supported",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "{{ .Name }} is within policy",
+						},
+					},
+				},
+			},
+			clusterVersion: `{"info": {"major": "1", "minor": "26+", "gitVersion": "v1.26.9-eks-f8587cb"}, "string": "v1.26.9-eks-f8587cb"}`,
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:         true,
+					Title:          "Node worker-1 Version Skew",
+					Message:        "worker-1 is within policy",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-1"),
+				},
+				{
+					IsFail:         true,
+					Title:          "Node worker-2 Version Skew",
+					Message:        "worker-2 is out of policy, at most 2 minor versions are supported",
+					IconKey:        "kubernetes_cluster_version",
+					IconURI:        "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+					InvolvedObject: nodeRef("worker-2"),
+				},
+			},
+		},
+		{
+			name:           "invalid cluster version",
+			analyzer:       troubleshootv1beta2.VersionSkew{},
+			clusterVersion: `{"string": "unknown"}`,
+			wantErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			files := map[string][]byte{
+				"cluster-info/cluster_version.json": []byte(test.clusterVersion),
+				"cluster-resources/nodes.json":      []byte(versionSkewNodes),
+			}
+			getFile := func(n string) ([]byte, error) {
+				return files[n], nil
+			}
+
+			actual, err := analyzeVersionSkew(&test.analyzer, getFile, nil)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			req.Equal(len(test.expectResult), len(actual))
+			for _, a := range actual {
+				assert.Contains(t, test.expectResult, a)
+			}
+		})
+	}
+}
+
+var versionSkewNodes = `{
+  "kind": "NodeList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "control-plane", "labels": {"node-role.kubernetes.io/control-plane": ""}},
+      "status": {
+        "nodeInfo": {"kubeletVersion": "v1.28.3", "kubeProxyVersion": "v1.28.3", "containerRuntimeVersion": "containerd://1.7.2"}
+      }
+    },
+    {
+      "metadata": {"name": "worker-1", "labels": {"node-role.kubernetes.io/worker": ""}},
+      "status": {
+        "nodeInfo": {"kubeletVersion": "v1.24.17", "kubeProxyVersion": "v1.24.17", "containerRuntimeVersion": "containerd://1.6.8"}
+      }
+    },
+    {
+      "metadata": {"name": "worker-2", "labels": {"node-role.kubernetes.io/worker": ""}},
+      "status": {
+        "nodeInfo": {"kubeletVersion": "v1.29.0", "containerRuntimeVersion": "containerd://1.7.2"}
+      }
+    }
+  ]
+}`
@@ -10,6 +10,22 @@ import (
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
+type AnalyzeWeaveReport struct {
+	analyzer *troubleshootv1beta2.WeaveReportAnalyze
+}
+
+func (a *AnalyzeWeaveReport) Title() string {
+	return analyzerTitleOrDefault(a.analyzer.AnalyzeMeta, "Weave Report")
+}
+
+func (a *AnalyzeWeaveReport) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeWeaveReport) Analyze(getFile func(string) ([]byte, error), findFiles func(string) (map[string][]byte, error)) ([]*AnalyzeResult, error) {
+	return analyzeWeaveReport(a.analyzer, findFiles)
+}
+
 // relevant fields from https://github.com/weaveworks/weave/blob/e3712152d2a0fe3bc998964c948e45bdf8ff6144/prog/weaver/http.go#L295
 type WeaveReport struct {
 	Router WeaveRouter
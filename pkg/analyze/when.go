@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/expression"
+)
+
+// whenVariableSources maps the top level variables available to every `when` expression to the
+// collected data they are loaded from, e.g. cpu.logical or memory.total.
+var whenVariableSources = map[string]func(getFile getCollectedFileContents) (interface{}, error){
+	"cpu": func(getFile getCollectedFileContents) (interface{}, error) {
+		cpuInfo := collect.CPUInfo{}
+		if err := unmarshalCollectedFile(getFile, collect.HostCPUPath, &cpuInfo); err != nil {
+			return nil, err
+		}
+		return hostCPUWhenVariables(cpuInfo), nil
+	},
+	"memory": func(getFile getCollectedFileContents) (interface{}, error) {
+		memoryInfo := collect.MemoryInfo{}
+		if err := unmarshalCollectedFile(getFile, collect.HostMemoryPath, &memoryInfo); err != nil {
+			return nil, err
+		}
+		return hostMemoryWhenVariables(memoryInfo), nil
+	},
+	"os": func(getFile getCollectedFileContents) (interface{}, error) {
+		osInfo := collect.HostOSInfo{}
+		if err := unmarshalCollectedFile(getFile, collect.HostOSInfoPath, &osInfo); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"name":            osInfo.Name,
+			"kernelVersion":   osInfo.KernelVersion,
+			"platform":        osInfo.Platform,
+			"platformVersion": osInfo.PlatformVersion,
+		}, nil
+	},
+}
+
+func unmarshalCollectedFile(getFile getCollectedFileContents, fileName string, v interface{}) error {
+	contents, err := getFile(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get collected file %s", fileName)
+	}
+	if err := json.Unmarshal(contents, v); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", fileName)
+	}
+	return nil
+}
+
+// evaluateWhen evaluates an outcome's when clause. Clauses that use the shared expression language
+// are evaluated against the analyzer's own variables and the collected data. Anything else goes to
+// the analyzer's legacy parser first, so existing specs keep their behavior, and only falls back to
+// the expression language when the legacy parser does not understand it. If neither understands it,
+// both errors are returned, so that e.g. an undefined variable in an expression is reported.
+// Analyzers that never had a legacy syntax pass a nil legacy parser and only understand expressions.
+// getFile may be nil for analyzers that have no access to other collected files.
+func evaluateWhen(when string, getFile getCollectedFileContents, vars map[string]interface{}, legacy func(string) (bool, error)) (bool, error) {
+	resolve := whenResolver(getFile, vars)
+	if legacy == nil || expression.IsExpression(when) {
+		return expression.Evaluate(when, resolve)
+	}
+
+	isMatch, err := legacy(when)
+	if err == nil {
+		return isMatch, nil
+	}
+
+	isMatch, exprErr := expression.Evaluate(when, resolve)
+	if exprErr != nil {
+		return false, errors.Wrap(exprErr, err.Error())
+	}
+	return isMatch, nil
+}
+
+// optionalVariablesDoNotMatch wraps compareWhen so that when clauses referring to one of the
+// optional variables, which are only defined for some objects, e.g. minutesSinceLastRestart for
+// containers that have restarted, do not match the objects they are not defined for.
+func optionalVariablesDoNotMatch(compareWhen func(string) (bool, error), optional ...string) func(string) (bool, error) {
+	return func(when string) (bool, error) {
+		isMatch, err := compareWhen(when)
+		if undefined, ok := errors.Cause(err).(expression.UndefinedVariableError); ok {
+			for _, name := range optional {
+				if undefined.Name == name {
+					return false, nil
+				}
+			}
+		}
+		return isMatch, err
+	}
+}
+
+func whenResolver(getFile getCollectedFileContents, vars map[string]interface{}) expression.Resolver {
+	resolveVar := expression.MapResolver(vars)
+	loaded := map[string]interface{}{}
+
+	return func(name string) (interface{}, bool, error) {
+		if value, ok, err := resolveVar(name); ok || err != nil {
+			return value, ok, err
+		}
+
+		if getFile == nil {
+			return nil, false, nil
+		}
+
+		source := strings.SplitN(name, ".", 2)[0]
+		if _, ok := loaded[source]; !ok {
+			load, ok := whenVariableSources[source]
+			if !ok {
+				return nil, false, nil
+			}
+			value, err := load(getFile)
+			if err != nil {
+				return nil, false, err
+			}
+			loaded[source] = value
+		}
+
+		return expression.MapResolver(loaded)(name)
+	}
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"path/filepath"
 	"testing"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -440,7 +441,7 @@ otherstuff:
 				return test.fileContents, nil
 			}
 
-			actual, err := analyzeYamlCompare(&test.analyzer, getCollectedFileContents)
+			actual, err := analyzeYamlCompare(&test.analyzer, getCollectedFileContents, nil)
 			if !test.isError {
 				req.NoError(err)
 				req.Equal(test.expectResult, *actual)
@@ -450,3 +451,57 @@ otherstuff:
 		})
 	}
 }
+
+func Test_yamlCompareJsonPath(t *testing.T) {
+	analyzer := troubleshootv1beta2.YamlCompare{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{
+				Fail: &troubleshootv1beta2.SingleOutcome{
+					When:    `any values in ["Pending", "Failed"]`,
+					Message: "fail",
+				},
+			},
+			{
+				Warn: &troubleshootv1beta2.SingleOutcome{
+					When:    "any values > 5",
+					Message: "warn",
+				},
+			},
+			{
+				Pass: &troubleshootv1beta2.SingleOutcome{
+					Message: "pass",
+				},
+			},
+		},
+		CollectorName: "yaml-compare-jsonpath",
+		FileName:      "*.yaml",
+		JsonPath:      "{.pods[*].restarts}",
+	}
+
+	files := map[string][]byte{
+		"yaml-compare-jsonpath/a.yaml": []byte("pods:\n- name: a\n  phase: Running\n  restarts: 0\n"),
+		"yaml-compare-jsonpath/b.yaml": []byte("pods:\n- name: b\n  phase: Pending\n  restarts: 7\n"),
+	}
+	findFiles := func(n string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for name, file := range files {
+			if matched, _ := filepath.Match(n, name); matched {
+				matching[name] = file
+			}
+		}
+		return matching, nil
+	}
+
+	req := require.New(t)
+
+	actual, err := analyzeYamlCompare(&analyzer, nil, findFiles)
+	req.NoError(err)
+	req.True(actual.IsWarn)
+	req.Equal("warn", actual.Message)
+
+	analyzer.JsonPath = "{.pods[*].phase}"
+	actual, err = analyzeYamlCompare(&analyzer, nil, findFiles)
+	req.NoError(err)
+	req.True(actual.IsFail)
+	req.Equal("fail", actual.Message)
+}
@@ -2,6 +2,7 @@ package v1beta2
 
 import (
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type ClusterVersion struct {
@@ -9,6 +10,13 @@ type ClusterVersion struct {
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+type DeprecatedApis struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	TargetVersion string     `json:"targetVersion,omitempty" yaml:"targetVersion,omitempty"`
+	Manifests     []string   `json:"manifests,omitempty" yaml:"manifests,omitempty"`
+}
+
 type StorageClass struct {
 	AnalyzeMeta      `json:",inline" yaml:",inline"`
 	Outcomes         []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -74,6 +82,27 @@ type JobStatus struct {
 	Name        string     `json:"name" yaml:"name"`
 }
 
+type CronJobStatus struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespace   string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Name        string     `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+type CustomResourceStatus struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Group         string     `json:"group" yaml:"group"`
+	Version       string     `json:"version,omitempty" yaml:"version,omitempty"`
+	Kind          string     `json:"kind" yaml:"kind"`
+	Namespace     string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces    []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Name          string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Selector      []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+	ConditionType string     `json:"conditionType,omitempty" yaml:"conditionType,omitempty"`
+}
+
 type ReplicaSetStatus struct {
 	AnalyzeMeta `json:",inline" yaml:",inline"`
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -83,12 +112,51 @@ type ReplicaSetStatus struct {
 	Selector    []string   `json:"selector" yaml:"selector"`
 }
 
+type PVCStatus struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespace   string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Name        string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Selector    []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type ServiceEndpoints struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespace   string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Name        string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Selector    []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type EventsAnalyze struct {
+	AnalyzeMeta  `json:",inline" yaml:",inline"`
+	Outcomes     []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespace    string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces   []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Kind         string     `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name         string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Type         string     `json:"type,omitempty" yaml:"type,omitempty"`
+	Reason       string     `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Reasons      []string   `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+	RegexPattern string     `json:"regex,omitempty" yaml:"regex,omitempty"`
+}
+
 type ClusterPodStatuses struct {
 	AnalyzeMeta `json:",inline" yaml:",inline"`
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
 	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
 }
 
+type ContainerStatuses struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespaces    []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Selector      []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+	ContainerName string     `json:"containerName,omitempty" yaml:"containerName,omitempty"`
+}
+
 type ContainerRuntime struct {
 	AnalyzeMeta `json:",inline" yaml:",inline"`
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -121,6 +189,41 @@ type NodeResourceSelectors struct {
 	MatchLabel map[string]string `json:"matchLabel,omitempty" yaml:"matchLabel,omitempty"`
 }
 
+type ResourceQuotaAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Namespace   string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Name        string     `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+type NodeConditions struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome  `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Selector    []string    `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Taints      []NodeTaint `json:"taints,omitempty" yaml:"taints,omitempty"`
+	Aggregate   bool        `json:"aggregate,omitempty" yaml:"aggregate,omitempty"`
+}
+
+type VersionSkew struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Selector    []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+type ClusterCapacity struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Selector    []string   `json:"selector,omitempty" yaml:"selector,omitempty"`
+	PerNode     bool       `json:"perNode,omitempty" yaml:"perNode,omitempty"`
+}
+
+type NodeTaint struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect string `json:"effect,omitempty" yaml:"effect,omitempty"`
+}
+
 type TextAnalyze struct {
 	AnalyzeMeta     `json:",inline" yaml:",inline"`
 	CollectorName   string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
@@ -136,6 +239,7 @@ type YamlCompare struct {
 	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
 	FileName      string     `json:"fileName,omitempty" yaml:"fileName,omitempty"`
 	Path          string     `json:"path,omitempty" yaml:"path,omitempty"`
+	JsonPath      string     `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
 	Value         string     `json:"value,omitempty" yaml:"value,omitempty"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
@@ -145,10 +249,27 @@ type JsonCompare struct {
 	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
 	FileName      string     `json:"fileName,omitempty" yaml:"fileName,omitempty"`
 	Path          string     `json:"path,omitempty" yaml:"path,omitempty"`
+	JsonPath      string     `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
 	Value         string     `json:"value,omitempty" yaml:"value,omitempty"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+type ClusterHTTPAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Name          string     `json:"name,omitempty" yaml:"name,omitempty"`
+	FileName      string     `json:"fileName,omitempty" yaml:"fileName,omitempty"`
+	JsonPath      string     `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type ExecResultAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Name          string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
 type DatabaseAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -162,6 +283,14 @@ type CollectdAnalyze struct {
 	CollectorName string     `json:"collectorName" yaml:"collectorName"`
 }
 
+type HelmReleaseAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Namespace     string     `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	ReleaseName   string     `json:"releaseName,omitempty" yaml:"releaseName,omitempty"`
+}
+
 type CephStatusAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -181,6 +310,21 @@ type WeaveReportAnalyze struct {
 	ReportFileGlob string `json:"reportFileGlob" yaml:"reportFileGlob,omitempty"`
 }
 
+type CalicoAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+}
+
+type CiliumAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+}
+
+type DNSAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+}
+
 type RegistryImagesAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -192,17 +336,40 @@ type SysctlAnalyze struct {
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+// CustomAnalyze runs an analyzer type that is not built into troubleshoot.
+// Type selects an analyzer registered with analyzer.RegisterAnalyzer and
+// Spec is passed through to it untouched.
+type CustomAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+	Type        string     `json:"type" yaml:"type"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec runtime.RawExtension `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
 type AnalyzeMeta struct {
 	CheckName   string                  `json:"checkName,omitempty" yaml:"checkName,omitempty"`
 	Exclude     *multitype.BoolOrString `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 	Strict      *multitype.BoolOrString `json:"strict,omitempty" yaml:"strict,omitempty"`
 	Annotations map[string]string       `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// DependsOn lists the checkNames of analyzers that have to run before this one. Host analyzers
+	// can only depend on other host analyzers.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	// RunIf is an expression evaluated against the result of each analyzer in DependsOn, using
+	// the variables pass, warn, fail, error and skipped. The analyzer is skipped unless it is
+	// true for all of them. Defaults to "pass".
+	// +optional
+	RunIf string `json:"runIf,omitempty" yaml:"runIf,omitempty"`
 }
 
 type Analyze struct {
 	ClusterVersion           *ClusterVersion           `json:"clusterVersion,omitempty" yaml:"clusterVersion,omitempty"`
+	DeprecatedApis           *DeprecatedApis           `json:"deprecatedApis,omitempty" yaml:"deprecatedApis,omitempty"`
+	VersionSkew              *VersionSkew              `json:"versionSkew,omitempty" yaml:"versionSkew,omitempty"`
 	StorageClass             *StorageClass             `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
 	CustomResourceDefinition *CustomResourceDefinition `json:"customResourceDefinition,omitempty" yaml:"customResourceDefinition,omitempty"`
+	CustomResourceStatus     *CustomResourceStatus     `json:"customResourceStatus,omitempty" yaml:"customResourceStatus,omitempty"`
 	Ingress                  *Ingress                  `json:"ingress,omitempty" yaml:"ingress,omitempty"`
 	Secret                   *AnalyzeSecret            `json:"secret,omitempty" yaml:"secret,omitempty"`
 	ConfigMap                *AnalyzeConfigMap         `json:"configMap,omitempty" yaml:"configMap,omitempty"`
@@ -210,20 +377,36 @@ type Analyze struct {
 	DeploymentStatus         *DeploymentStatus         `json:"deploymentStatus,omitempty" yaml:"deploymentStatus,omitempty"`
 	StatefulsetStatus        *StatefulsetStatus        `json:"statefulsetStatus,omitempty" yaml:"statefulsetStatus,omitempty"`
 	JobStatus                *JobStatus                `json:"jobStatus,omitempty" yaml:"jobStatus,omitempty"`
+	CronJobStatus            *CronJobStatus            `json:"cronJobStatus,omitempty" yaml:"cronJobStatus,omitempty"`
 	ReplicaSetStatus         *ReplicaSetStatus         `json:"replicasetStatus,omitempty" yaml:"replicasetStatus,omitempty"`
+	PVCStatus                *PVCStatus                `json:"pvcStatus,omitempty" yaml:"pvcStatus,omitempty"`
+	Events                   *EventsAnalyze            `json:"events,omitempty" yaml:"events,omitempty"`
+	ServiceEndpoints         *ServiceEndpoints         `json:"serviceEndpoints,omitempty" yaml:"serviceEndpoints,omitempty"`
 	ClusterPodStatuses       *ClusterPodStatuses       `json:"clusterPodStatuses,omitempty" yaml:"clusterPodStatuses,omitempty"`
+	ContainerStatuses        *ContainerStatuses        `json:"containerStatuses,omitempty" yaml:"containerStatuses,omitempty"`
 	ContainerRuntime         *ContainerRuntime         `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
 	Distribution             *Distribution             `json:"distribution,omitempty" yaml:"distribution,omitempty"`
 	NodeResources            *NodeResources            `json:"nodeResources,omitempty" yaml:"nodeResources,omitempty"`
+	NodeConditions           *NodeConditions           `json:"nodeConditions,omitempty" yaml:"nodeConditions,omitempty"`
+	ResourceQuota            *ResourceQuotaAnalyze     `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+	ClusterCapacity          *ClusterCapacity          `json:"clusterCapacity,omitempty" yaml:"clusterCapacity,omitempty"`
 	TextAnalyze              *TextAnalyze              `json:"textAnalyze,omitempty" yaml:"textAnalyze,omitempty"`
 	YamlCompare              *YamlCompare              `json:"yamlCompare,omitempty" yaml:"yamlCompare,omitempty"`
 	JsonCompare              *JsonCompare              `json:"jsonCompare,omitempty" yaml:"jsonCompare,omitempty"`
+	HTTP                     *ClusterHTTPAnalyze       `json:"http,omitempty" yaml:"http,omitempty"`
+	ExecResult               *ExecResultAnalyze        `json:"execResult,omitempty" yaml:"execResult,omitempty"`
 	Postgres                 *DatabaseAnalyze          `json:"postgres,omitempty" yaml:"postgres,omitempty"`
 	Mysql                    *DatabaseAnalyze          `json:"mysql,omitempty" yaml:"mysql,omitempty"`
 	Redis                    *DatabaseAnalyze          `json:"redis,omitempty" yaml:"redis,omitempty"`
 	CephStatus               *CephStatusAnalyze        `json:"cephStatus,omitempty" yaml:"cephStatus,omitempty"`
+	HelmRelease              *HelmReleaseAnalyze       `json:"helmRelease,omitempty" yaml:"helmRelease,omitempty"`
 	Longhorn                 *LonghornAnalyze          `json:"longhorn,omitempty" yaml:"longhorn,omitempty"`
+	Collectd                 *CollectdAnalyze          `json:"collectd,omitempty" yaml:"collectd,omitempty"`
 	RegistryImages           *RegistryImagesAnalyze    `json:"registryImages,omitempty" yaml:"registryImages,omitempty"`
 	WeaveReport              *WeaveReportAnalyze       `json:"weaveReport,omitempty" yaml:"weaveReport,omitempty"`
+	Calico                   *CalicoAnalyze            `json:"calico,omitempty" yaml:"calico,omitempty"`
+	Cilium                   *CiliumAnalyze            `json:"cilium,omitempty" yaml:"cilium,omitempty"`
+	DNS                      *DNSAnalyze               `json:"dns,omitempty" yaml:"dns,omitempty"`
 	Sysctl                   *SysctlAnalyze            `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	Custom                   *CustomAnalyze            `json:"custom,omitempty" yaml:"custom,omitempty"`
 }
@@ -5,9 +5,9 @@ import (
 	"strings"
 
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
-	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type CollectorMeta struct {
@@ -199,6 +199,46 @@ type RegistryImages struct {
 	ImagePullSecrets *ImagePullSecrets `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
 }
 
+type Helm struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Namespace     string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	ReleaseName   string `json:"releaseName,omitempty" yaml:"releaseName,omitempty"`
+	// IncludeValues also collects the values of each release. Values that look like credentials are
+	// masked.
+	IncludeValues bool `json:"includeValues,omitempty" yaml:"includeValues,omitempty"`
+}
+
+type Calico struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Namespace     string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+type Cilium struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Namespace     string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Timeout       string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+type DNS struct {
+	CollectorMeta   `json:",inline" yaml:",inline"`
+	Namespace       string            `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Image           string            `json:"image,omitempty" yaml:"image,omitempty"`
+	ImagePullSecret *ImagePullSecrets `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
+	Names           []string          `json:"names,omitempty" yaml:"names,omitempty"`
+	Timeout         string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// CustomCollector runs a collector type that is not built into troubleshoot.
+// Type selects a collector registered with collect.RegisterCollector and
+// Spec is passed through to it untouched.
+type CustomCollector struct {
+	CollectorMeta `json:",inline" yaml:",inline"`
+	Type          string `json:"type" yaml:"type"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec runtime.RawExtension `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
 type Collect struct {
 	ClusterInfo      *ClusterInfo      `json:"clusterInfo,omitempty" yaml:"clusterInfo,omitempty"`
 	ClusterResources *ClusterResources `json:"clusterResources,omitempty" yaml:"clusterResources,omitempty"`
@@ -220,215 +260,11 @@ type Collect struct {
 	Longhorn         *Longhorn         `json:"longhorn,omitempty" yaml:"longhorn,omitempty"`
 	RegistryImages   *RegistryImages   `json:"registryImages,omitempty" yaml:"registryImages,omitempty"`
 	Sysctl           *Sysctl           `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
-}
-
-func (c *Collect) AccessReviewSpecs(overrideNS string) []authorizationv1.SelfSubjectAccessReviewSpec {
-	result := make([]authorizationv1.SelfSubjectAccessReviewSpec, 0)
-
-	if c.ClusterInfo != nil {
-		// NOOP
-	} else if c.ClusterResources != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   "",
-				Verb:        "list",
-				Group:       "",
-				Version:     "",
-				Resource:    "namespaces",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   "",
-				Verb:        "list",
-				Group:       "",
-				Version:     "",
-				Resource:    "nodes",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   "",
-				Verb:        "list",
-				Group:       "apiextensions.k8s.io",
-				Version:     "",
-				Resource:    "customresourcedefinitions",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   "",
-				Verb:        "list",
-				Group:       "storage.k8s.io",
-				Version:     "",
-				Resource:    "storageclasses",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Secret != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Secret.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "secrets",
-				Subresource: "",
-				Name:        c.Secret.Name,
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.ConfigMap != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.ConfigMap.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "configmaps",
-				Subresource: "",
-				Name:        c.ConfigMap.Name,
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Logs != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Logs.Namespace, overrideNS),
-				Verb:        "list",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Logs.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "log",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Run != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Run.Namespace, overrideNS),
-				Verb:        "create",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.RunPod != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.RunPod.Namespace, overrideNS),
-				Verb:        "create",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Exec != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Exec.Namespace, overrideNS),
-				Verb:        "list",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Exec.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "exec",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Copy != nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Copy.Namespace, overrideNS),
-				Verb:        "list",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.Copy.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "pods",
-				Subresource: "exec",
-				Name:        "",
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.CopyFromHost != nil {
-		// TODO
-	} else if c.Collectd != nil {
-		// TODO
-	} else if c.HTTP != nil {
-		// NOOP
-	} else if c.RegistryImages != nil &&
-		c.RegistryImages.ImagePullSecrets != nil &&
-		c.RegistryImages.ImagePullSecrets.Data == nil {
-		result = append(result, authorizationv1.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationv1.ResourceAttributes{
-				Namespace:   pickNamespaceOrDefault(c.RegistryImages.Namespace, overrideNS),
-				Verb:        "get",
-				Group:       "",
-				Version:     "",
-				Resource:    "secrets",
-				Subresource: "",
-				Name:        c.RegistryImages.ImagePullSecrets.Name,
-			},
-			NonResourceAttributes: nil,
-		})
-	} else if c.Sysctl != nil {
-		// TODO
-	}
-
-	return result
+	Helm             *Helm             `json:"helm,omitempty" yaml:"helm,omitempty"`
+	Calico           *Calico           `json:"calico,omitempty" yaml:"calico,omitempty"`
+	Cilium           *Cilium           `json:"cilium,omitempty" yaml:"cilium,omitempty"`
+	DNS              *DNS              `json:"dns,omitempty" yaml:"dns,omitempty"`
+	Custom           *CustomCollector  `json:"custom,omitempty" yaml:"custom,omitempty"`
 }
 
 func (c *Collect) GetName() string {
@@ -516,6 +352,26 @@ func (c *Collect) GetName() string {
 		collector = "sysctl"
 		name = c.Sysctl.Name
 	}
+	if c.Helm != nil {
+		collector = "helm"
+		name = c.Helm.CollectorName
+	}
+	if c.Calico != nil {
+		collector = "calico"
+		name = c.Calico.CollectorName
+	}
+	if c.Cilium != nil {
+		collector = "cilium"
+		name = c.Cilium.CollectorName
+	}
+	if c.DNS != nil {
+		collector = "dns"
+		name = c.DNS.CollectorName
+	}
+	if c.Custom != nil {
+		collector = c.Custom.Type
+		name = c.Custom.CollectorName
+	}
 
 	if collector == "" {
 		return "<none>"
@@ -528,13 +384,3 @@ func (c *Collect) GetName() string {
 	}
 	return collector
 }
-
-func pickNamespaceOrDefault(collectorNS string, overrideNS string) string {
-	if overrideNS != "" {
-		return overrideNS
-	}
-	if collectorNS != "" {
-		return collectorNS
-	}
-	return "default"
-}
@@ -23,7 +23,7 @@ package v1beta2
 
 import (
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -59,6 +59,16 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(ClusterVersion)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeprecatedApis != nil {
+		in, out := &in.DeprecatedApis, &out.DeprecatedApis
+		*out = new(DeprecatedApis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VersionSkew != nil {
+		in, out := &in.VersionSkew, &out.VersionSkew
+		*out = new(VersionSkew)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageClass != nil {
 		in, out := &in.StorageClass, &out.StorageClass
 		*out = new(StorageClass)
@@ -69,6 +79,11 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(CustomResourceDefinition)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomResourceStatus != nil {
+		in, out := &in.CustomResourceStatus, &out.CustomResourceStatus
+		*out = new(CustomResourceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Ingress != nil {
 		in, out := &in.Ingress, &out.Ingress
 		*out = new(Ingress)
@@ -104,16 +119,41 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(JobStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CronJobStatus != nil {
+		in, out := &in.CronJobStatus, &out.CronJobStatus
+		*out = new(CronJobStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ReplicaSetStatus != nil {
 		in, out := &in.ReplicaSetStatus, &out.ReplicaSetStatus
 		*out = new(ReplicaSetStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PVCStatus != nil {
+		in, out := &in.PVCStatus, &out.PVCStatus
+		*out = new(PVCStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(EventsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceEndpoints != nil {
+		in, out := &in.ServiceEndpoints, &out.ServiceEndpoints
+		*out = new(ServiceEndpoints)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ClusterPodStatuses != nil {
 		in, out := &in.ClusterPodStatuses, &out.ClusterPodStatuses
 		*out = new(ClusterPodStatuses)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ContainerStatuses != nil {
+		in, out := &in.ContainerStatuses, &out.ContainerStatuses
+		*out = new(ContainerStatuses)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ContainerRuntime != nil {
 		in, out := &in.ContainerRuntime, &out.ContainerRuntime
 		*out = new(ContainerRuntime)
@@ -129,6 +169,21 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(NodeResources)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeConditions != nil {
+		in, out := &in.NodeConditions, &out.NodeConditions
+		*out = new(NodeConditions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = new(ResourceQuotaAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterCapacity != nil {
+		in, out := &in.ClusterCapacity, &out.ClusterCapacity
+		*out = new(ClusterCapacity)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TextAnalyze != nil {
 		in, out := &in.TextAnalyze, &out.TextAnalyze
 		*out = new(TextAnalyze)
@@ -144,6 +199,16 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(JsonCompare)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(ClusterHTTPAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecResult != nil {
+		in, out := &in.ExecResult, &out.ExecResult
+		*out = new(ExecResultAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Postgres != nil {
 		in, out := &in.Postgres, &out.Postgres
 		*out = new(DatabaseAnalyze)
@@ -164,11 +229,21 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(CephStatusAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HelmRelease != nil {
+		in, out := &in.HelmRelease, &out.HelmRelease
+		*out = new(HelmReleaseAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Longhorn != nil {
 		in, out := &in.Longhorn, &out.Longhorn
 		*out = new(LonghornAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Collectd != nil {
+		in, out := &in.Collectd, &out.Collectd
+		*out = new(CollectdAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RegistryImages != nil {
 		in, out := &in.RegistryImages, &out.RegistryImages
 		*out = new(RegistryImagesAnalyze)
@@ -179,11 +254,31 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(WeaveReportAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Calico != nil {
+		in, out := &in.Calico, &out.Calico
+		*out = new(CalicoAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cilium != nil {
+		in, out := &in.Cilium, &out.Cilium
+		*out = new(CiliumAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNSAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Sysctl != nil {
 		in, out := &in.Sysctl, &out.Sysctl
 		*out = new(SysctlAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Analyze.
@@ -243,6 +338,11 @@ func (in *AnalyzeMeta) DeepCopyInto(out *AnalyzeMeta) {
 			(*out)[key] = val
 		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzeMeta.
@@ -452,6 +552,38 @@ func (in *CPUAnalyze) DeepCopy() *CPUAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Calico) DeepCopyInto(out *Calico) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Calico.
+func (in *Calico) DeepCopy() *Calico {
+	if in == nil {
+		return nil
+	}
+	out := new(Calico)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CalicoAnalyze) DeepCopyInto(out *CalicoAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CalicoAnalyze.
+func (in *CalicoAnalyze) DeepCopy() *CalicoAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CalicoAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Ceph) DeepCopyInto(out *Ceph) {
 	*out = *in
@@ -538,6 +670,97 @@ func (in *CertificateAnalyze) DeepCopy() *CertificateAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cilium) DeepCopyInto(out *Cilium) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cilium.
+func (in *Cilium) DeepCopy() *Cilium {
+	if in == nil {
+		return nil
+	}
+	out := new(Cilium)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CiliumAnalyze) DeepCopyInto(out *CiliumAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CiliumAnalyze.
+func (in *CiliumAnalyze) DeepCopy() *CiliumAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CiliumAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacity) DeepCopyInto(out *ClusterCapacity) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacity.
+func (in *ClusterCapacity) DeepCopy() *ClusterCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHTTPAnalyze) DeepCopyInto(out *ClusterHTTPAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHTTPAnalyze.
+func (in *ClusterHTTPAnalyze) DeepCopy() *ClusterHTTPAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHTTPAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterInfo) DeepCopyInto(out *ClusterInfo) {
 	*out = *in
@@ -737,6 +960,31 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 		*out = new(Sysctl)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(Helm)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Calico != nil {
+		in, out := &in.Calico, &out.Calico
+		*out = new(Calico)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cilium != nil {
+		in, out := &in.Cilium, &out.Cilium
+		*out = new(Cilium)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(DNS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomCollector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Collect.
@@ -976,6 +1224,43 @@ func (in *ContainerRuntime) DeepCopy() *ContainerRuntime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerStatuses) DeepCopyInto(out *ContainerStatuses) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerStatuses.
+func (in *ContainerStatuses) DeepCopy() *ContainerStatuses {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerStatuses)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Copy) DeepCopyInto(out *Copy) {
 	*out = *in
@@ -1018,6 +1303,83 @@ func (in *CopyFromHost) DeepCopy() *CopyFromHost {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronJobStatus) DeepCopyInto(out *CronJobStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronJobStatus.
+func (in *CronJobStatus) DeepCopy() *CronJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CronJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomAnalyze) DeepCopyInto(out *CustomAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAnalyze.
+func (in *CustomAnalyze) DeepCopy() *CustomAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomCollector) DeepCopyInto(out *CustomCollector) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomCollector.
+func (in *CustomCollector) DeepCopy() *CustomCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomResourceDefinition) DeepCopyInto(out *CustomResourceDefinition) {
 	*out = *in
@@ -1045,6 +1407,85 @@ func (in *CustomResourceDefinition) DeepCopy() *CustomResourceDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomResourceStatus) DeepCopyInto(out *CustomResourceStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomResourceStatus.
+func (in *CustomResourceStatus) DeepCopy() *CustomResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNS) DeepCopyInto(out *DNS) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNS.
+func (in *DNS) DeepCopy() *DNS {
+	if in == nil {
+		return nil
+	}
+	out := new(DNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSAnalyze) DeepCopyInto(out *DNSAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSAnalyze.
+func (in *DNSAnalyze) DeepCopy() *DNSAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Data) DeepCopyInto(out *Data) {
 	*out = *in
@@ -1131,12 +1572,44 @@ func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentStatus.
-func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentStatus.
+func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeprecatedApis) DeepCopyInto(out *DeprecatedApis) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprecatedApis.
+func (in *DeprecatedApis) DeepCopy() *DeprecatedApis {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentStatus)
+	out := new(DeprecatedApis)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1211,6 +1684,43 @@ func (in *Distribution) DeepCopy() *Distribution {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventsAnalyze) DeepCopyInto(out *EventsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventsAnalyze.
+func (in *EventsAnalyze) DeepCopy() *EventsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(EventsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exec) DeepCopyInto(out *Exec) {
 	*out = *in
@@ -1242,6 +1752,33 @@ func (in *Exec) DeepCopy() *Exec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecResultAnalyze) DeepCopyInto(out *ExecResultAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecResultAnalyze.
+func (in *ExecResultAnalyze) DeepCopy() *ExecResultAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecResultAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FileSelector) DeepCopyInto(out *FileSelector) {
 	*out = *in
@@ -1428,6 +1965,49 @@ func (in *HTTPLoadBalancerAnalyze) DeepCopy() *HTTPLoadBalancerAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Helm) DeepCopyInto(out *Helm) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Helm.
+func (in *Helm) DeepCopy() *Helm {
+	if in == nil {
+		return nil
+	}
+	out := new(Helm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmReleaseAnalyze) DeepCopyInto(out *HelmReleaseAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmReleaseAnalyze.
+func (in *HelmReleaseAnalyze) DeepCopy() *HelmReleaseAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmReleaseAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostAnalyze) DeepCopyInto(out *HostAnalyze) {
 	*out = *in
@@ -2496,6 +3076,43 @@ func (in *MemoryAnalyze) DeepCopy() *MemoryAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConditions) DeepCopyInto(out *NodeConditions) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]NodeTaint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConditions.
+func (in *NodeConditions) DeepCopy() *NodeConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConditions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceFilters) DeepCopyInto(out *NodeResourceFilters) {
 	*out = *in
@@ -2570,6 +3187,21 @@ func (in *NodeResources) DeepCopy() *NodeResources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTaint) DeepCopyInto(out *NodeTaint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTaint.
+func (in *NodeTaint) DeepCopy() *NodeTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Outcome) DeepCopyInto(out *Outcome) {
 	*out = *in
@@ -2600,6 +3232,43 @@ func (in *Outcome) DeepCopy() *Outcome {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCStatus) DeepCopyInto(out *PVCStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCStatus.
+func (in *PVCStatus) DeepCopy() *PVCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Post) DeepCopyInto(out *Post) {
 	*out = *in
@@ -3523,6 +4192,38 @@ func (in *ReplicaSetStatus) DeepCopy() *ReplicaSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaAnalyze) DeepCopyInto(out *ResourceQuotaAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaAnalyze.
+func (in *ResourceQuotaAnalyze) DeepCopy() *ResourceQuotaAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResultRequest) DeepCopyInto(out *ResultRequest) {
 	*out = *in
@@ -3612,6 +4313,43 @@ func (in *Secret) DeepCopy() *Secret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpoints) DeepCopyInto(out *ServiceEndpoints) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpoints.
+func (in *ServiceEndpoints) DeepCopy() *ServiceEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SingleOutcome) DeepCopyInto(out *SingleOutcome) {
 	*out = *in
@@ -4108,6 +4846,38 @@ func (in *TimeAnalyze) DeepCopy() *TimeAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionSkew) DeepCopyInto(out *VersionSkew) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionSkew.
+func (in *VersionSkew) DeepCopy() *VersionSkew {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionSkew)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WeaveReportAnalyze) DeepCopyInto(out *WeaveReportAnalyze) {
 	*out = *in
@@ -0,0 +1,229 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// calicoResources are the Calico custom resources that are collected, each of which is saved in
+// <collector name>/calico/<resource>.json.
+var calicoResources = []schema.GroupVersionResource{
+	{Group: "crd.projectcalico.org", Version: "v1", Resource: "ippools"},
+	{Group: "crd.projectcalico.org", Version: "v1", Resource: "ipamblocks"},
+	{Group: "crd.projectcalico.org", Version: "v1", Resource: "bgppeers"},
+}
+
+type CollectCalico struct {
+	collector *troubleshootv1beta2.Calico
+	c         *Collector
+}
+
+func (c *CollectCalico) Title() string {
+	return clusterCollectorTitle("calico", c.collector.CollectorName, nil)
+}
+
+func (c *CollectCalico) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCalico) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(c.collector.Namespace, "list", "", "pods", "", ""),
+		resourceAccessReviewSpec(c.collector.Namespace, "create", "", "pods", "exec", ""),
+	}
+}
+
+func (c *CollectCalico) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	timeout, err := parseCNICollectorTimeout(c.collector.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(c.c.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(c.c.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return Calico(ctx, c.c, c.collector, client, dynamicClient, newPodExecFunc(ctx, c.c, clientset, timeout))
+}
+
+// CalicoNodeStatus is the state of the calico-node agent on a node, which is saved for every node
+// in <collector name>/calico/nodes/<node>.json. BGPPeers is empty when BIRD is not running, e.g.
+// because the cluster uses VXLAN without BGP.
+type CalicoNodeStatus struct {
+	Node       string          `json:"node"`
+	Namespace  string          `json:"namespace"`
+	Pod        string          `json:"pod"`
+	FelixReady bool            `json:"felixReady"`
+	FelixError string          `json:"felixError,omitempty"`
+	BIRDError  string          `json:"birdError,omitempty"`
+	BGPPeers   []CalicoBGPPeer `json:"bgpPeers"`
+}
+
+// CalicoBGPPeer is a BGP session of BIRD, as listed by "calicoctl node status".
+type CalicoBGPPeer struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	State   string `json:"state"`
+	Since   string `json:"since"`
+	Info    string `json:"info"`
+}
+
+// IsEstablished is true when the session with the peer is up.
+func (p CalicoBGPPeer) IsEstablished() bool {
+	return p.State == "up" && strings.HasPrefix(p.Info, "Established")
+}
+
+func Calico(ctx context.Context, c *Collector, calicoCollector *troubleshootv1beta2.Calico, client kubernetes.Interface, dynamicClient dynamic.Interface, execFn podExecFunc) (CollectorResult, error) {
+	output := NewResult()
+	pathPrefix := GetCalicoCollectorFilepath(calicoCollector.CollectorName)
+
+	errorList := []string{}
+
+	for _, gvr := range calicoResources {
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s: %v", gvr.Resource, err))
+			continue
+		}
+		b, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s: %v", gvr.Resource, err))
+			continue
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, fmt.Sprintf("%s.json", gvr.Resource)), bytes.NewBuffer(b))
+	}
+
+	pods, podsErrors := listPodsInSelectors(ctx, client, calicoCollector.Namespace, []string{"k8s-app=calico-node"})
+	errorList = append(errorList, podsErrors...)
+
+	for _, pod := range pods {
+		status := getCalicoNodeStatus(pod, execFn)
+		b, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s/%s: %v", pod.Namespace, pod.Name, err))
+			continue
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "nodes", fmt.Sprintf("%s.json", status.Node)), bytes.NewBuffer(b))
+	}
+
+	output.SaveResult(c.BundlePath, path.Join(pathPrefix, "errors.json"), marshalErrors(errorList))
+
+	return output, nil
+}
+
+func GetCalicoCollectorFilepath(name string) string {
+	if name != "" {
+		return path.Join(name, "calico")
+	}
+	return "calico"
+}
+
+// getCalicoNodeStatus asks calico-node whether felix is ready, and BIRD for its BGP sessions, which
+// is what "calicoctl node status" does without needing calicoctl in the container.
+func getCalicoNodeStatus(pod corev1.Pod, execFn podExecFunc) CalicoNodeStatus {
+	status := CalicoNodeStatus{
+		Node:      pod.Spec.NodeName,
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		BGPPeers:  []CalicoBGPPeer{},
+	}
+	if status.Node == "" {
+		status.Node = pod.Name
+	}
+
+	felix := execFn(pod, "calico-node", []string{"calico-node", "-felix-ready"})
+	if len(felix.errors) == 0 {
+		status.FelixReady = true
+	} else {
+		status.FelixError = getPodExecError(felix)
+	}
+
+	bird := execFn(pod, "calico-node", []string{"birdcl", "-s", "/var/run/calico/bird.ctl", "show", "protocols"})
+	if len(bird.errors) == 0 {
+		status.BGPPeers = parseBIRDProtocols(string(bird.stdout))
+	} else {
+		status.BIRDError = getPodExecError(bird)
+	}
+
+	return status
+}
+
+// parseBIRDProtocols returns the BGP sessions in the output of "birdcl show protocols", e.g.
+//
+//	name     proto    table    state  since    info
+//	Mesh_172_18_0_3 BGP      master   up     09:41:36 Established
+//	Node_10_0_0_1 BGP      master   start  09:41:36 Active        Socket: Connection refused
+//
+// Calico names the sessions after the type and address of the peer.
+func parseBIRDProtocols(output string) []CalicoBGPPeer {
+	peers := []CalicoBGPPeer{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[1] != "BGP" {
+			continue
+		}
+
+		peer := CalicoBGPPeer{
+			Address: fields[0],
+			Type:    "unknown",
+			State:   fields[3],
+			Since:   fields[4],
+			Info:    strings.Join(fields[5:], " "),
+		}
+
+		parts := strings.SplitN(fields[0], "_", 2)
+		if len(parts) == 2 {
+			switch parts[0] {
+			case "Mesh":
+				peer.Type = "node-to-node mesh"
+			case "Node":
+				peer.Type = "node specific"
+			case "Global":
+				peer.Type = "global"
+			}
+			if peer.Type != "unknown" {
+				peer.Address = parseBIRDPeerAddress(parts[1])
+			}
+		}
+
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+// parseBIRDPeerAddress reverses how Calico names sessions, which replaces the dots of IPv4
+// addresses and the colons of IPv6 addresses with underscores.
+func parseBIRDPeerAddress(name string) string {
+	if ip := net.ParseIP(strings.ReplaceAll(name, "_", ".")); ip != nil {
+		return ip.String()
+	}
+	return strings.ReplaceAll(name, "_", ":")
+}
+
+// parseCNICollectorTimeout parses the timeout of the commands the calico and cilium collectors run
+// in every agent pod, which defaults to 30 seconds.
+func parseCNICollectorTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 30 * time.Second, nil
+	}
+	return time.ParseDuration(timeout)
+}
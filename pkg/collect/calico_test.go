@@ -0,0 +1,115 @@
+package collect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseBIRDProtocols(t *testing.T) {
+	output := `BIRD v0.3.3+birdv1.6.8 ready.
+name     proto    table    state  since    info
+static1  Static   master   up     09:41:36
+kernel1  Kernel   master   up     09:41:36
+device1  Device   master   up     09:41:36
+direct1  Direct   master   up     09:41:36
+Mesh_172_18_0_3 BGP      master   up     09:41:38 Established
+Mesh_172_18_0_4 BGP      master   start  09:41:36 Active        Socket: Connection refused
+Node_fd00__1 BGP      master   start  09:41:36 Connect
+Global_10_0_0_1 BGP      master   up     09:45:02 Established
+`
+
+	assert.Equal(t, []CalicoBGPPeer{
+		{Address: "172.18.0.3", Type: "node-to-node mesh", State: "up", Since: "09:41:38", Info: "Established"},
+		{Address: "172.18.0.4", Type: "node-to-node mesh", State: "start", Since: "09:41:36", Info: "Active Socket: Connection refused"},
+		{Address: "fd00::1", Type: "node specific", State: "start", Since: "09:41:36", Info: "Connect"},
+		{Address: "10.0.0.1", Type: "global", State: "up", Since: "09:45:02", Info: "Established"},
+	}, parseBIRDProtocols(output))
+
+	assert.Equal(t, []CalicoBGPPeer{}, parseBIRDProtocols("BIRD v0.3.3+birdv1.6.8 ready.\n"))
+}
+
+func TestGetCalicoNodeStatus(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "calico-node-x7k2p", Namespace: "kube-system"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	}
+
+	tests := []struct {
+		name    string
+		outputs map[string]execOutput
+		want    CalicoNodeStatus
+	}{
+		{
+			name: "healthy",
+			outputs: map[string]execOutput{
+				"calico-node": {exitCode: 0},
+				"birdcl": {stdout: []byte(`name     proto    table    state  since    info
+Mesh_172_18_0_3 BGP      master   up     09:41:38 Established
+`)},
+			},
+			want: CalicoNodeStatus{
+				Node:       "node-1",
+				Namespace:  "kube-system",
+				Pod:        "calico-node-x7k2p",
+				FelixReady: true,
+				BGPPeers: []CalicoBGPPeer{
+					{Address: "172.18.0.3", Type: "node-to-node mesh", State: "up", Since: "09:41:38", Info: "Established"},
+				},
+			},
+		},
+		{
+			name: "felix not ready and bird not running",
+			outputs: map[string]execOutput{
+				"calico-node": {
+					stderr:   []byte("calico/node is not ready: felix is not ready: readiness probe reporting 503\n"),
+					exitCode: 1,
+					errors:   []string{"command terminated with exit code 1"},
+				},
+				"birdcl": {
+					stdout:   []byte("Unable to connect to server control socket (/var/run/calico/bird.ctl): No such file or directory\n"),
+					exitCode: 1,
+					errors:   []string{"command terminated with exit code 1"},
+				},
+			},
+			want: CalicoNodeStatus{
+				Node:       "node-1",
+				Namespace:  "kube-system",
+				Pod:        "calico-node-x7k2p",
+				FelixError: "calico/node is not ready: felix is not ready: readiness probe reporting 503",
+				BIRDError:  "Unable to connect to server control socket (/var/run/calico/bird.ctl): No such file or directory",
+				BGPPeers:   []CalicoBGPPeer{},
+			},
+		},
+		{
+			name: "exec failed",
+			outputs: map[string]execOutput{
+				"calico-node": {exitCode: -1, errors: []string{"timeout"}},
+				"birdcl":      {exitCode: -1, errors: []string{"timeout"}},
+			},
+			want: CalicoNodeStatus{
+				Node:       "node-1",
+				Namespace:  "kube-system",
+				Pod:        "calico-node-x7k2p",
+				FelixError: "timeout",
+				BIRDError:  "timeout",
+				BGPPeers:   []CalicoBGPPeer{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			execFn := func(pod corev1.Pod, container string, command []string) execOutput {
+				assert.Equal(t, "calico-node", container, strings.Join(command, " "))
+				return test.outputs[command[0]]
+			}
+
+			got := getCalicoNodeStatus(pod, execFn)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -9,10 +9,32 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectCeph struct {
+	collector *troubleshootv1beta2.Ceph
+	c         *Collector
+}
+
+func (c *CollectCeph) Title() string {
+	return clusterCollectorTitle("ceph", c.collector.CollectorName, nil)
+}
+
+func (c *CollectCeph) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCeph) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectCeph) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Ceph(ctx, c.c, c.collector)
+}
+
 const (
 	DefaultCephNamespace = "rook-ceph"
 )
@@ -105,9 +127,7 @@ var CephCommands = []CephCommand{
 	},
 }
 
-func Ceph(c *Collector, cephCollector *troubleshootv1beta2.Ceph) (CollectorResult, error) {
-	ctx := context.TODO()
-
+func Ceph(ctx context.Context, c *Collector, cephCollector *troubleshootv1beta2.Ceph) (CollectorResult, error) {
 	if cephCollector.Namespace == "" {
 		cephCollector.Namespace = DefaultCephNamespace
 	}
@@ -143,7 +163,7 @@ func cephCommandExec(ctx context.Context, c *Collector, cephCollector *troublesh
 		Args:      command.Args,
 		Timeout:   timeout,
 	}
-	results, err := Exec(c, execCollector)
+	results, err := Exec(ctx, c, execCollector)
 	if err != nil {
 		return errors.Wrap(err, "failed to exec command")
 	}
@@ -0,0 +1,114 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ciliumNodesResource has the pod CIDRs of every node, which are saved in
+// <collector name>/cilium/ciliumnodes.json.
+var ciliumNodesResource = schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumnodes"}
+
+// ciliumCommands are run in every cilium agent pod, and their output is saved in
+// <collector name>/cilium/<node>/<file>.
+var ciliumCommands = []struct {
+	file    string
+	command []string
+}{
+	{file: "status.json", command: []string{"cilium", "status", "--output", "json"}},
+	{file: "health.json", command: []string{"cilium-health", "status", "--output", "json"}},
+}
+
+type CollectCilium struct {
+	collector *troubleshootv1beta2.Cilium
+	c         *Collector
+}
+
+func (c *CollectCilium) Title() string {
+	return clusterCollectorTitle("cilium", c.collector.CollectorName, nil)
+}
+
+func (c *CollectCilium) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCilium) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(c.collector.Namespace, "list", "", "pods", "", ""),
+		resourceAccessReviewSpec(c.collector.Namespace, "create", "", "pods", "exec", ""),
+	}
+}
+
+func (c *CollectCilium) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	timeout, err := parseCNICollectorTimeout(c.collector.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(c.c.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(c.c.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return Cilium(ctx, c.c, c.collector, client, dynamicClient, newPodExecFunc(ctx, c.c, clientset, timeout))
+}
+
+func Cilium(ctx context.Context, c *Collector, ciliumCollector *troubleshootv1beta2.Cilium, client kubernetes.Interface, dynamicClient dynamic.Interface, execFn podExecFunc) (CollectorResult, error) {
+	output := NewResult()
+	pathPrefix := GetCiliumCollectorFilepath(ciliumCollector.CollectorName)
+
+	errorList := []string{}
+
+	ciliumNodes, err := dynamicClient.Resource(ciliumNodesResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errorList = append(errorList, fmt.Sprintf("%s: %v", ciliumNodesResource.Resource, err))
+	} else if b, err := json.MarshalIndent(ciliumNodes, "", "  "); err != nil {
+		errorList = append(errorList, fmt.Sprintf("%s: %v", ciliumNodesResource.Resource, err))
+	} else {
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "ciliumnodes.json"), bytes.NewBuffer(b))
+	}
+
+	pods, podsErrors := listPodsInSelectors(ctx, client, ciliumCollector.Namespace, []string{"k8s-app=cilium"})
+	errorList = append(errorList, podsErrors...)
+
+	for _, pod := range pods {
+		node := pod.Spec.NodeName
+		if node == "" {
+			node = pod.Name
+		}
+
+		for _, command := range ciliumCommands {
+			o := execFn(pod, "cilium-agent", command.command)
+			if len(o.errors) > 0 {
+				errorList = append(errorList, fmt.Sprintf("%s/%s: %s: %s", pod.Namespace, pod.Name, command.command[0], getPodExecError(o)))
+				continue
+			}
+			output.SaveResult(c.BundlePath, path.Join(pathPrefix, node, command.file), bytes.NewBuffer(o.stdout))
+		}
+	}
+
+	output.SaveResult(c.BundlePath, path.Join(pathPrefix, "errors.json"), marshalErrors(errorList))
+
+	return output, nil
+}
+
+func GetCiliumCollectorFilepath(name string) string {
+	if name != "" {
+		return path.Join(name, "cilium")
+	}
+	return "cilium"
+}
@@ -0,0 +1,157 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ClusterCollector interface {
+	Title() string
+	IsExcluded() (bool, error)
+	AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec
+	Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error)
+}
+
+// resourceAccessReviewSpec builds the access review that checks whether verb is allowed on a resource.
+func resourceAccessReviewSpec(namespace, verb, group, resource, subresource, name string) authorizationv1.SelfSubjectAccessReviewSpec {
+	return authorizationv1.SelfSubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace:   namespace,
+			Verb:        verb,
+			Group:       group,
+			Resource:    resource,
+			Subresource: subresource,
+			Name:        name,
+		},
+	}
+}
+
+// pickNamespaceOrDefault returns the namespace a collector runs in, the namespace of the run
+// overrides the one in the spec.
+func pickNamespaceOrDefault(collectorNS string, overrideNS string) string {
+	if overrideNS != "" {
+		return overrideNS
+	}
+	if collectorNS != "" {
+		return collectorNS
+	}
+	return "default"
+}
+
+// CollectorFactory builds a ClusterCollector from the spec of a custom collector.
+// The Collector carries the client config, namespace and bundle path of the current run.
+type CollectorFactory func(collector *troubleshootv1beta2.CustomCollector, c *Collector) ClusterCollector
+
+var (
+	customCollectors   = map[string]CollectorFactory{}
+	customCollectorsMu sync.RWMutex
+)
+
+// RegisterCollector makes a collector type available to specs through the "custom" collector.
+// It is meant to be called from an init function of a program that embeds troubleshoot.
+func RegisterCollector(collectorType string, factory CollectorFactory) error {
+	if collectorType == "" {
+		return errors.New("collector type is required")
+	}
+	if factory == nil {
+		return errors.Errorf("collector %q has no factory", collectorType)
+	}
+
+	customCollectorsMu.Lock()
+	defer customCollectorsMu.Unlock()
+
+	if _, ok := customCollectors[collectorType]; ok {
+		return errors.Errorf("collector %q is already registered", collectorType)
+	}
+	customCollectors[collectorType] = factory
+
+	return nil
+}
+
+func getCustomCollector(collector *troubleshootv1beta2.CustomCollector, c *Collector) (ClusterCollector, bool) {
+	customCollectorsMu.RLock()
+	factory, ok := customCollectors[collector.Type]
+	customCollectorsMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+	return factory(collector, c), true
+}
+
+func GetClusterCollector(c *Collector) (ClusterCollector, bool) {
+	collector := c.Collect
+
+	switch {
+	case collector.ClusterInfo != nil:
+		return &CollectClusterInfo{collector.ClusterInfo, c}, true
+	case collector.ClusterResources != nil:
+		return &CollectClusterResources{collector.ClusterResources, c}, true
+	case collector.Secret != nil:
+		return &CollectSecret{collector.Secret, c}, true
+	case collector.ConfigMap != nil:
+		return &CollectConfigMap{collector.ConfigMap, c}, true
+	case collector.Logs != nil:
+		return &CollectLogs{collector.Logs, c}, true
+	case collector.Run != nil:
+		return &CollectRun{collector.Run, c}, true
+	case collector.RunPod != nil:
+		return &CollectRunPod{collector.RunPod, c}, true
+	case collector.Exec != nil:
+		return &CollectExec{collector.Exec, c}, true
+	case collector.Data != nil:
+		return &CollectData{collector.Data, c}, true
+	case collector.Copy != nil:
+		return &CollectCopy{collector.Copy, c}, true
+	case collector.CopyFromHost != nil:
+		return &CollectCopyFromHost{collector.CopyFromHost, c}, true
+	case collector.HTTP != nil:
+		return &CollectHTTP{collector.HTTP, c}, true
+	case collector.Postgres != nil:
+		return &CollectPostgres{collector.Postgres, c}, true
+	case collector.Mysql != nil:
+		return &CollectMysql{collector.Mysql, c}, true
+	case collector.Redis != nil:
+		return &CollectRedis{collector.Redis, c}, true
+	case collector.Collectd != nil:
+		return &CollectCollectd{collector.Collectd, c}, true
+	case collector.Ceph != nil:
+		return &CollectCeph{collector.Ceph, c}, true
+	case collector.Longhorn != nil:
+		return &CollectLonghorn{collector.Longhorn, c}, true
+	case collector.RegistryImages != nil:
+		return &CollectRegistry{collector.RegistryImages, c}, true
+	case collector.Sysctl != nil:
+		return &CollectSysctl{collector.Sysctl, c}, true
+	case collector.Helm != nil:
+		return &CollectHelm{collector.Helm, c}, true
+	case collector.Calico != nil:
+		return &CollectCalico{collector.Calico, c}, true
+	case collector.Cilium != nil:
+		return &CollectCilium{collector.Cilium, c}, true
+	case collector.DNS != nil:
+		return &CollectDNS{collector.DNS, c}, true
+	case collector.Custom != nil:
+		return getCustomCollector(collector.Custom, c)
+	default:
+		return nil, false
+	}
+}
+
+// clusterCollectorTitle builds the display name of a collector, e.g. "logs/my-app" or "logs/app=api".
+func clusterCollectorTitle(collectorType string, name string, selector []string) string {
+	if name != "" {
+		return fmt.Sprintf("%s/%s", collectorType, name)
+	}
+	if s := strings.Join(selector, ","); s != "" {
+		return fmt.Sprintf("%s/%s", collectorType, s)
+	}
+	return collectorType
+}
@@ -2,14 +2,38 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"path/filepath"
 
 	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectClusterInfo struct {
+	collector *troubleshootv1beta2.ClusterInfo
+	c         *Collector
+}
+
+func (c *CollectClusterInfo) Title() string {
+	return clusterCollectorTitle("cluster-info", "", nil)
+}
+
+func (c *CollectClusterInfo) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectClusterInfo) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectClusterInfo) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return ClusterInfo(c.c)
+}
+
 type ClusterVersion struct {
 	Info   *version.Info `json:"info"`
 	String string        `json:"string"`
@@ -32,7 +32,33 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil/discovery"
 )
 
-func ClusterResources(c *Collector, clusterResourcesCollector *troubleshootv1beta2.ClusterResources) (CollectorResult, error) {
+type CollectClusterResources struct {
+	collector *troubleshootv1beta2.ClusterResources
+	c         *Collector
+}
+
+func (c *CollectClusterResources) Title() string {
+	return clusterCollectorTitle("cluster-resources", "", nil)
+}
+
+func (c *CollectClusterResources) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectClusterResources) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec("", "list", "", "namespaces", "", ""),
+		resourceAccessReviewSpec("", "list", "", "nodes", "", ""),
+		resourceAccessReviewSpec("", "list", "apiextensions.k8s.io", "customresourcedefinitions", "", ""),
+		resourceAccessReviewSpec("", "list", "storage.k8s.io", "storageclasses", "", ""),
+	}
+}
+
+func (c *CollectClusterResources) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return ClusterResources(ctx, c.c, c.collector)
+}
+
+func ClusterResources(ctx context.Context, c *Collector, clusterResourcesCollector *troubleshootv1beta2.ClusterResources) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, err
@@ -43,7 +69,6 @@ func ClusterResources(c *Collector, clusterResourcesCollector *troubleshootv1bet
 		return nil, err
 	}
 
-	ctx := context.Background()
 	output := NewResult()
 
 	// namespaces
@@ -104,6 +129,20 @@ func ClusterResources(c *Collector, clusterResourcesCollector *troubleshootv1bet
 	}
 	output.SaveResult(c.BundlePath, "cluster-resources/services-errors.json", marshalErrors(servicesErrors))
 
+	// endpoints
+	endpoints, endpointsErrors := endpoints(ctx, client, namespaceNames)
+	for k, v := range endpoints {
+		output.SaveResult(c.BundlePath, path.Join("cluster-resources/endpoints", k), bytes.NewBuffer(v))
+	}
+	output.SaveResult(c.BundlePath, "cluster-resources/endpoints-errors.json", marshalErrors(endpointsErrors))
+
+	// endpoint slices
+	endpointSlices, endpointSlicesErrors := endpointSlices(ctx, client, namespaceNames)
+	for k, v := range endpointSlices {
+		output.SaveResult(c.BundlePath, path.Join("cluster-resources/endpointslices", k), bytes.NewBuffer(v))
+	}
+	output.SaveResult(c.BundlePath, "cluster-resources/endpointslices-errors.json", marshalErrors(endpointSlicesErrors))
+
 	// deployments
 	deployments, deploymentsErrors := deployments(ctx, client, namespaceNames)
 	for k, v := range deployments {
@@ -187,6 +226,13 @@ func ClusterResources(c *Collector, clusterResourcesCollector *troubleshootv1bet
 	}
 	output.SaveResult(c.BundlePath, "cluster-resources/limitranges-errors.json", marshalErrors(limitRangesErrors))
 
+	// resource quotas
+	resourceQuotas, resourceQuotasErrors := resourceQuotas(ctx, client, namespaceNames)
+	for k, v := range resourceQuotas {
+		output.SaveResult(c.BundlePath, path.Join("cluster-resources/resourcequotas", k), bytes.NewBuffer(v))
+	}
+	output.SaveResult(c.BundlePath, "cluster-resources/resourcequotas-errors.json", marshalErrors(resourceQuotasErrors))
+
 	// auth cani
 	authCanI, authCanIErrors := authCanI(ctx, client, namespaceNames)
 	for k, v := range authCanI {
@@ -360,6 +406,123 @@ func services(ctx context.Context, client *kubernetes.Clientset, namespaces []st
 	return servicesByNamespace, errorsByNamespace
 }
 
+func endpoints(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+	endpointsByNamespace := make(map[string][]byte)
+	errorsByNamespace := make(map[string]string)
+
+	for _, namespace := range namespaces {
+		endpoints, err := client.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(endpoints, scheme.Scheme)
+		if err == nil {
+			endpoints.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		for i, o := range endpoints.Items {
+			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
+			if err == nil {
+				endpoints.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
+			}
+		}
+
+		b, err := json.MarshalIndent(endpoints, "", "  ")
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		endpointsByNamespace[namespace+".json"] = b
+	}
+
+	return endpointsByNamespace, errorsByNamespace
+}
+
+func endpointSlices(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+	ok, err := discovery.HasResource(client, "discovery.k8s.io/v1", "EndpointSlice")
+	if err != nil {
+		return nil, map[string]string{"": err.Error()}
+	}
+	if ok {
+		return endpointSlicesV1(ctx, client, namespaces)
+	}
+
+	return endpointSlicesV1beta1(ctx, client, namespaces)
+}
+
+func endpointSlicesV1(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+	endpointSlicesByNamespace := make(map[string][]byte)
+	errorsByNamespace := make(map[string]string)
+
+	for _, namespace := range namespaces {
+		endpointSlices, err := client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(endpointSlices, scheme.Scheme)
+		if err == nil {
+			endpointSlices.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		for i, o := range endpointSlices.Items {
+			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
+			if err == nil {
+				endpointSlices.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
+			}
+		}
+
+		b, err := json.MarshalIndent(endpointSlices, "", "  ")
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		endpointSlicesByNamespace[namespace+".json"] = b
+	}
+
+	return endpointSlicesByNamespace, errorsByNamespace
+}
+
+func endpointSlicesV1beta1(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+	endpointSlicesByNamespace := make(map[string][]byte)
+	errorsByNamespace := make(map[string]string)
+
+	for _, namespace := range namespaces {
+		endpointSlices, err := client.DiscoveryV1beta1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(endpointSlices, scheme.Scheme)
+		if err == nil {
+			endpointSlices.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		for i, o := range endpointSlices.Items {
+			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
+			if err == nil {
+				endpointSlices.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
+			}
+		}
+
+		b, err := json.MarshalIndent(endpointSlices, "", "  ")
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		endpointSlicesByNamespace[namespace+".json"] = b
+	}
+
+	return endpointSlicesByNamespace, errorsByNamespace
+}
+
 func deployments(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
 	deploymentsByNamespace := make(map[string][]byte)
 	errorsByNamespace := make(map[string]string)
@@ -1055,6 +1218,41 @@ func limitRanges(ctx context.Context, client *kubernetes.Clientset, namespaces [
 	return limitRangesByNamespace, errorsByNamespace
 }
 
+func resourceQuotas(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
+	resourceQuotasByNamespace := make(map[string][]byte)
+	errorsByNamespace := make(map[string]string)
+
+	for _, namespace := range namespaces {
+		resourceQuotas, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(resourceQuotas, scheme.Scheme)
+		if err == nil {
+			resourceQuotas.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+
+		for i, o := range resourceQuotas.Items {
+			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
+			if err == nil {
+				resourceQuotas.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
+			}
+		}
+
+		b, err := json.MarshalIndent(resourceQuotas, "", "  ")
+		if err != nil {
+			errorsByNamespace[namespace] = err.Error()
+			continue
+		}
+
+		resourceQuotasByNamespace[namespace+".json"] = b
+	}
+
+	return resourceQuotasByNamespace, errorsByNamespace
+}
+
 func nodes(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
 	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -4,12 +4,49 @@ import (
 	"context"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 )
 
+type CollectCollectd struct {
+	collector *troubleshootv1beta2.Collectd
+	c         *Collector
+}
+
+func (c *CollectCollectd) Title() string {
+	return clusterCollectorTitle("collectd", c.collector.CollectorName, nil)
+}
+
+func (c *CollectCollectd) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCollectd) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectCollectd) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	// TODO: see if redaction breaks these
+	namespace := c.collector.Namespace
+	if namespace == "" && c.c.Namespace == "" {
+		kubeconfig := k8sutil.GetKubeconfig()
+		namespace, _, _ = kubeconfig.Namespace()
+	} else if namespace == "" {
+		namespace = c.c.Namespace
+	}
+	return Collectd(ctx, c.c, c.collector, namespace, c.c.ClientConfig, client)
+}
+
 func Collectd(ctx context.Context, c *Collector, collector *troubleshootv1beta2.Collectd, namespace string, clientConfig *restclient.Config, client kubernetes.Interface) (CollectorResult, error) {
-	copyFromHost := &troubleshootv1beta2.CopyFromHost{
+	return CopyFromHost(ctx, c, collectdCopyFromHost(collector), namespace, clientConfig, client)
+}
+
+// collectdCopyFromHost extracts the rrd files of every node to collectd/rrd/<node name>, which is
+// where the collectd analyzer reads them from.
+func collectdCopyFromHost(collector *troubleshootv1beta2.Collectd) *troubleshootv1beta2.CopyFromHost {
+	return &troubleshootv1beta2.CopyFromHost{
 		CollectorMeta:   collector.CollectorMeta,
 		Name:            "collectd/rrd",
 		Namespace:       collector.Namespace,
@@ -18,6 +55,6 @@ func Collectd(ctx context.Context, c *Collector, collector *troubleshootv1beta2.
 		ImagePullSecret: collector.ImagePullSecret,
 		Timeout:         collector.Timeout,
 		HostPath:        collector.HostPath,
+		ExtractArchive:  true,
 	}
-	return CopyFromHost(ctx, c, copyFromHost, namespace, clientConfig, client)
 }
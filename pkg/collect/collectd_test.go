@@ -0,0 +1,31 @@
+package collect
+
+import (
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_collectdCopyFromHost(t *testing.T) {
+	collector := &troubleshootv1beta2.Collectd{
+		CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "collectd"},
+		Image:         "busybox:1",
+		Namespace:     "default",
+		HostPath:      "/var/lib/collectd/rrd",
+	}
+
+	copyFromHost := collectdCopyFromHost(collector)
+
+	assert.Equal(t, "collectd", copyFromHost.CollectorName)
+	assert.Equal(t, "busybox:1", copyFromHost.Image)
+	assert.Equal(t, "/var/lib/collectd/rrd", copyFromHost.HostPath)
+	// the archive has to be extracted for the analyzer to find the rrd files
+	assert.True(t, copyFromHost.ExtractArchive)
+
+	// the files of each node are extracted relative to the parent of the host path, and the
+	// collectd analyzer expects them at collectd/rrd/<node name>/rrd/<hostname>/<plugin>/<type>.rrd
+	extracted := filepath.Join(copyFromHost.Name, "node-1", filepath.Base(copyFromHost.HostPath), "node-1", "cpu-0", "cpu-idle.rrd")
+	assert.Equal(t, "collectd/rrd/node-1/rrd/node-1/cpu-0/cpu-idle.rrd", filepath.ToSlash(extracted))
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"runtime"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
-	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,6 +23,8 @@ type Collector struct {
 	ClientConfig *rest.Config
 	Namespace    string
 	BundlePath   string
+	// Timeout bounds how long the collector is allowed to run. Zero means no limit.
+	Timeout time.Duration
 }
 
 type Collectors []*Collector
@@ -49,162 +52,16 @@ func isExcluded(excludeVal *multitype.BoolOrString) (bool, error) {
 
 // checks if a given collector has a spec with 'exclude' that evaluates to true.
 func (c *Collector) IsExcluded() bool {
-	if c.Collect.ClusterInfo != nil {
-		isExcludedResult, err := isExcluded(c.Collect.ClusterInfo.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.ClusterResources != nil {
-		isExcludedResult, err := isExcluded(c.Collect.ClusterResources.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Secret != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Secret.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.ConfigMap != nil {
-		isExcludedResult, err := isExcluded(c.Collect.ConfigMap.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Logs != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Logs.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Run != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Run.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.RunPod != nil {
-		isExcludedResult, err := isExcluded(c.Collect.RunPod.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Exec != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Exec.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Data != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Data.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Copy != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Copy.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.CopyFromHost != nil {
-		isExcludedResult, err := isExcluded(c.Collect.CopyFromHost.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.HTTP != nil {
-		isExcludedResult, err := isExcluded(c.Collect.HTTP.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Postgres != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Postgres.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Mysql != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Mysql.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Redis != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Redis.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Collectd != nil {
-		// TODO: see if redaction breaks these
-		isExcludedResult, err := isExcluded(c.Collect.Collectd.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Ceph != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Ceph.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Longhorn != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Longhorn.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
-	} else if c.Collect.Sysctl != nil {
-		isExcludedResult, err := isExcluded(c.Collect.Sysctl.Exclude)
-		if err != nil {
-			return true
-		}
-		if isExcludedResult {
-			return true
-		}
+	collector, ok := GetClusterCollector(c)
+	if !ok {
+		return false
 	}
 
-	return false
+	isExcludedResult, err := collector.IsExcluded()
+	if err != nil {
+		return true
+	}
+	return isExcludedResult
 }
 
 func (c *Collector) RunCollectorSync(clientConfig *rest.Config, client kubernetes.Interface, globalRedactors []*troubleshootv1beta2.Redact) (result CollectorResult, err error) {
@@ -219,75 +76,13 @@ func (c *Collector) RunCollectorSync(clientConfig *rest.Config, client kubernete
 		return
 	}
 
-	ctx := context.TODO()
-
-	if c.Collect.ClusterInfo != nil {
-		result, err = ClusterInfo(c)
-	} else if c.Collect.ClusterResources != nil {
-		result, err = ClusterResources(c, c.Collect.ClusterResources)
-	} else if c.Collect.Secret != nil {
-		result, err = Secret(ctx, c, c.Collect.Secret, client)
-	} else if c.Collect.ConfigMap != nil {
-		result, err = ConfigMap(ctx, c, c.Collect.ConfigMap, client)
-	} else if c.Collect.Logs != nil {
-		result, err = Logs(c, c.Collect.Logs)
-	} else if c.Collect.Run != nil {
-		result, err = Run(c, c.Collect.Run)
-	} else if c.Collect.RunPod != nil {
-		result, err = RunPod(c, c.Collect.RunPod)
-	} else if c.Collect.Exec != nil {
-		result, err = Exec(c, c.Collect.Exec)
-	} else if c.Collect.Data != nil {
-		result, err = Data(c, c.Collect.Data)
-	} else if c.Collect.Copy != nil {
-		result, err = Copy(c, c.Collect.Copy)
-	} else if c.Collect.CopyFromHost != nil {
-		namespace := c.Collect.CopyFromHost.Namespace
-		if namespace == "" && c.Namespace == "" {
-			kubeconfig := k8sutil.GetKubeconfig()
-			namespace, _, _ = kubeconfig.Namespace()
-		} else if namespace == "" {
-			namespace = c.Namespace
-		}
-		result, err = CopyFromHost(ctx, c, c.Collect.CopyFromHost, namespace, clientConfig, client)
-	} else if c.Collect.HTTP != nil {
-		result, err = HTTP(c, c.Collect.HTTP)
-	} else if c.Collect.Postgres != nil {
-		result, err = Postgres(c, c.Collect.Postgres)
-	} else if c.Collect.Mysql != nil {
-		result, err = Mysql(c, c.Collect.Mysql)
-	} else if c.Collect.Redis != nil {
-		result, err = Redis(c, c.Collect.Redis)
-	} else if c.Collect.Collectd != nil {
-		// TODO: see if redaction breaks these
-		namespace := c.Collect.Collectd.Namespace
-		if namespace == "" && c.Namespace == "" {
-			kubeconfig := k8sutil.GetKubeconfig()
-			namespace, _, _ = kubeconfig.Namespace()
-		} else if namespace == "" {
-			namespace = c.Namespace
-		}
-		result, err = Collectd(ctx, c, c.Collect.Collectd, namespace, clientConfig, client)
-	} else if c.Collect.Ceph != nil {
-		result, err = Ceph(c, c.Collect.Ceph)
-	} else if c.Collect.Longhorn != nil {
-		result, err = Longhorn(c, c.Collect.Longhorn)
-	} else if c.Collect.RegistryImages != nil {
-		result, err = Registry(c, c.Collect.RegistryImages)
-	} else if c.Collect.Sysctl != nil {
-		if c.Collect.Sysctl.Namespace == "" {
-			c.Collect.Sysctl.Namespace = c.Namespace
-		}
-		if c.Collect.Sysctl.Namespace == "" {
-			kubeconfig := k8sutil.GetKubeconfig()
-			namespace, _, _ := kubeconfig.Namespace()
-			c.Collect.Sysctl.Namespace = namespace
-		}
-		result, err = Sysctl(ctx, c, client, c.Collect.Sysctl)
-	} else {
+	collector, ok := GetClusterCollector(c)
+	if !ok {
 		err = errors.New("no spec found to run")
 		return
 	}
+
+	result, err = c.collectWithTimeout(collector, client)
 	if err != nil {
 		return
 	}
@@ -300,7 +95,51 @@ func (c *Collector) RunCollectorSync(clientConfig *rest.Config, client kubernete
 	return
 }
 
+// collectWithTimeout runs the collector, giving up once c.Timeout has elapsed. The context passed
+// to the collector is cancelled on timeout, which stops the api calls, execs and requests the
+// built in collectors make. The collector is waited for after that, so that it does not write to the
+// bundle after this returns, and the files it did write are removed since they are not redacted.
+func (c *Collector) collectWithTimeout(collector ClusterCollector, client kubernetes.Interface) (CollectorResult, error) {
+	if c.Timeout <= 0 {
+		return collector.Collect(context.TODO(), client)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	type collectResponse struct {
+		result CollectorResult
+		err    error
+	}
+
+	responseCh := make(chan collectResponse, 1)
+	go func() {
+		response := collectResponse{}
+		defer func() {
+			if r := recover(); r != nil {
+				response.err = errors.Errorf("recovered from panic: %v", r)
+			}
+			responseCh <- response
+		}()
+		response.result, response.err = collector.Collect(ctx, client)
+	}()
+
+	select {
+	case response := <-responseCh:
+		return response.result, response.err
+	case <-ctx.Done():
+		response := <-responseCh
+		if err := removeResult(c.BundlePath, response.result); err != nil {
+			return nil, errors.Wrapf(err, "timed out after %s", c.Timeout)
+		}
+		return nil, errors.Errorf("timed out after %s", c.Timeout)
+	}
+}
+
 func (c *Collector) GetDisplayName() string {
+	if collector, ok := GetClusterCollector(c); ok {
+		return collector.Title()
+	}
 	return c.Collect.GetName()
 }
 
@@ -314,9 +153,14 @@ func (c *Collector) CheckRBAC(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create client from config")
 	}
 
+	collector, ok := GetClusterCollector(c)
+	if !ok {
+		return nil
+	}
+
 	forbidden := make([]error, 0)
 
-	specs := c.Collect.AccessReviewSpecs(c.Namespace)
+	specs := collector.AccessReviewSpecs(c.Namespace)
 	for _, spec := range specs {
 		sar := &authorizationv1.SelfSubjectAccessReview{
 			Spec: spec,
@@ -328,12 +172,15 @@ func (c *Collector) CheckRBAC(ctx context.Context) error {
 		}
 
 		if !resp.Status.Allowed { // all other fields of Status are empty...
-			forbidden = append(forbidden, RBACError{
+			rbacErr := RBACError{
 				DisplayName: c.GetDisplayName(),
-				Namespace:   spec.ResourceAttributes.Namespace,
-				Resource:    spec.ResourceAttributes.Resource,
-				Verb:        spec.ResourceAttributes.Verb,
-			})
+			}
+			if spec.ResourceAttributes != nil {
+				rbacErr.Namespace = spec.ResourceAttributes.Namespace
+				rbacErr.Resource = spec.ResourceAttributes.Resource
+				rbacErr.Verb = spec.ResourceAttributes.Verb
+			}
+			forbidden = append(forbidden, rbacErr)
 		}
 	}
 	c.RBACErrors = forbidden
@@ -341,6 +188,29 @@ func (c *Collector) CheckRBAC(ctx context.Context) error {
 	return nil
 }
 
+// RunConcurrently calls fn for every collector, running at most concurrency of them at the same time.
+// A concurrency of less than 1 runs the collectors one after another, in order.
+func (cs Collectors) RunConcurrently(concurrency int, fn func(c *Collector)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, c := range cs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(c *Collector) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(c)
+		}(c)
+	}
+	wg.Wait()
+}
+
 func (cs Collectors) CheckRBAC(ctx context.Context) error {
 	for _, c := range cs {
 		if err := c.CheckRBAC(ctx); err != nil {
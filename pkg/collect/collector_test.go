@@ -1,11 +1,21 @@
 package collect
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 )
 
 func TestCollector_RunCollectorSyncNoRedact(t *testing.T) {
@@ -348,3 +358,219 @@ pwd=somethinggoeshere;`,
 		})
 	}
 }
+
+type testCustomCollector struct {
+	collector *troubleshootv1beta2.CustomCollector
+}
+
+func (c *testCustomCollector) Title() string {
+	return clusterCollectorTitle(c.collector.Type, c.collector.CollectorName, nil)
+}
+
+func (c *testCustomCollector) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *testCustomCollector) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *testCustomCollector) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return CollectorResult{
+		"custom/data.txt": append([]byte("pwd=somethinggoeshere;\n"), c.collector.Spec.Raw...),
+	}, nil
+}
+
+func TestCollector_RegisterCollector(t *testing.T) {
+	req := require.New(t)
+
+	err := RegisterCollector("test-custom", func(collector *troubleshootv1beta2.CustomCollector, c *Collector) ClusterCollector {
+		return &testCustomCollector{collector}
+	})
+	req.NoError(err)
+
+	err = RegisterCollector("test-custom", func(collector *troubleshootv1beta2.CustomCollector, c *Collector) ClusterCollector {
+		return &testCustomCollector{collector}
+	})
+	req.Error(err)
+
+	c := &Collector{
+		Collect: &troubleshootv1beta2.Collect{
+			Custom: &troubleshootv1beta2.CustomCollector{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{
+					CollectorName: "mine",
+				},
+				Type: "test-custom",
+				Spec: runtime.RawExtension{Raw: []byte(`{"key":"value"}`)},
+			},
+		},
+		Redact: true,
+	}
+	req.Equal("test-custom/mine", c.GetDisplayName())
+
+	got, err := c.RunCollectorSync(nil, nil, nil)
+	req.NoError(err)
+	req.Equal("pwd=***HIDDEN***;\n{\"key\":\"value\"}\n", string(got["custom/data.txt"]))
+
+	c.Collect.Custom.Exclude = multitype.FromBool(true)
+	got, err = c.RunCollectorSync(nil, nil, nil)
+	req.NoError(err)
+	req.Empty(got)
+
+	c.Collect.Custom.Type = "not-registered"
+	c.Collect.Custom.Exclude = nil
+	_, err = c.RunCollectorSync(nil, nil, nil)
+	req.Error(err)
+}
+
+type testSlowCollector struct {
+	collector *troubleshootv1beta2.CustomCollector
+}
+
+func (c *testSlowCollector) Title() string {
+	return clusterCollectorTitle(c.collector.Type, c.collector.CollectorName, nil)
+}
+
+func (c *testSlowCollector) IsExcluded() (bool, error) {
+	return false, nil
+}
+
+func (c *testSlowCollector) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *testSlowCollector) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	select {
+	case <-time.After(time.Second):
+		return CollectorResult{"slow/" + c.collector.CollectorName: []byte("done")}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestCollector_RunCollectorSyncTimeout(t *testing.T) {
+	req := require.New(t)
+
+	err := RegisterCollector("test-slow", func(collector *troubleshootv1beta2.CustomCollector, c *Collector) ClusterCollector {
+		return &testSlowCollector{collector}
+	})
+	req.NoError(err)
+
+	c := &Collector{
+		Collect: &troubleshootv1beta2.Collect{
+			Custom: &troubleshootv1beta2.CustomCollector{
+				Type: "test-slow",
+			},
+		},
+		Timeout: 10 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err = c.RunCollectorSync(nil, nil, nil)
+	req.Error(err)
+	req.Contains(err.Error(), "timed out")
+	req.Less(int64(time.Since(start)), int64(time.Second))
+}
+
+// testLateCollector keeps writing to the bundle after it is cancelled
+type testLateCollector struct {
+	collector *troubleshootv1beta2.CustomCollector
+	c         *Collector
+}
+
+func (c *testLateCollector) Title() string {
+	return clusterCollectorTitle(c.collector.Type, c.collector.CollectorName, nil)
+}
+
+func (c *testLateCollector) IsExcluded() (bool, error) {
+	return false, nil
+}
+
+func (c *testLateCollector) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *testLateCollector) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	output := NewResult()
+	if err := output.SaveResult(c.c.BundlePath, "late/early.txt", bytes.NewBufferString("pwd=secret;")); err != nil {
+		return nil, err
+	}
+
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := output.SaveResult(c.c.BundlePath, "late/late.txt", bytes.NewBufferString("pwd=secret;")); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func TestCollector_RunCollectorSyncTimeoutRemovesUnredactedFiles(t *testing.T) {
+	req := require.New(t)
+
+	err := RegisterCollector("test-late", func(collector *troubleshootv1beta2.CustomCollector, c *Collector) ClusterCollector {
+		return &testLateCollector{collector, c}
+	})
+	req.NoError(err)
+
+	bundlePath := t.TempDir()
+	c := &Collector{
+		Collect: &troubleshootv1beta2.Collect{
+			Custom: &troubleshootv1beta2.CustomCollector{
+				Type: "test-late",
+			},
+		},
+		Redact:     true,
+		BundlePath: bundlePath,
+		Timeout:    10 * time.Millisecond,
+	}
+
+	_, err = c.RunCollectorSync(nil, nil, nil)
+	req.Error(err)
+	req.Contains(err.Error(), "timed out")
+
+	// the collector has returned, and nothing it wrote is left in the bundle
+	for _, name := range []string{"late/early.txt", "late/late.txt"} {
+		_, err := os.Stat(filepath.Join(bundlePath, name))
+		req.True(os.IsNotExist(err), name)
+	}
+}
+
+func TestCollectors_RunConcurrently(t *testing.T) {
+	req := require.New(t)
+
+	var collectors Collectors
+	for i := 0; i < 10; i++ {
+		collectors = append(collectors, &Collector{
+			Namespace: strconv.Itoa(i),
+		})
+	}
+
+	var mut sync.Mutex
+	running, maxRunning := 0, 0
+	seen := []string{}
+	collectors.RunConcurrently(3, func(c *Collector) {
+		mut.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		seen = append(seen, c.Namespace)
+		mut.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mut.Lock()
+		running--
+		mut.Unlock()
+	})
+	req.Len(seen, 10)
+	req.LessOrEqual(maxRunning, 3)
+
+	// a concurrency of less than 1 runs collectors sequentially, in order
+	seen = []string{}
+	collectors.RunConcurrently(0, func(c *Collector) {
+		seen = append(seen, c.Namespace)
+	})
+	req.Equal([]string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, seen)
+}
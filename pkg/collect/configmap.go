@@ -10,12 +10,36 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectConfigMap struct {
+	collector *troubleshootv1beta2.ConfigMap
+	c         *Collector
+}
+
+func (c *CollectConfigMap) Title() string {
+	return clusterCollectorTitle("configmap", c.collector.CollectorName, c.collector.Selector)
+}
+
+func (c *CollectConfigMap) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectConfigMap) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "get", "", "configmaps", "", c.collector.Name),
+	}
+}
+
+func (c *CollectConfigMap) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return ConfigMap(ctx, c.c, c.collector, client)
+}
+
 type ConfigMapOutput struct {
 	Namespace       string            `json:"namespace"`
 	Name            string            `json:"name"`
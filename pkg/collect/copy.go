@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -19,8 +20,33 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 )
 
-//Copy function gets a file or folder from a container specified in the specs.
-func Copy(c *Collector, copyCollector *troubleshootv1beta2.Copy) (CollectorResult, error) {
+type CollectCopy struct {
+	collector *troubleshootv1beta2.Copy
+	c         *Collector
+}
+
+func (c *CollectCopy) Title() string {
+	return clusterCollectorTitle("copy", c.collector.CollectorName, c.collector.Selector)
+}
+
+func (c *CollectCopy) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCopy) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	ns := pickNamespaceOrDefault(c.collector.Namespace, namespace)
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(ns, "list", "", "pods", "", ""),
+		resourceAccessReviewSpec(ns, "get", "", "pods", "exec", ""),
+	}
+}
+
+func (c *CollectCopy) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Copy(ctx, c.c, c.collector)
+}
+
+// Copy function gets a file or folder from a container specified in the specs.
+func Copy(ctx context.Context, c *Collector, copyCollector *troubleshootv1beta2.Copy) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, err
@@ -28,8 +54,6 @@ func Copy(c *Collector, copyCollector *troubleshootv1beta2.Copy) (CollectorResul
 
 	output := NewResult()
 
-	ctx := context.Background()
-
 	pods, podsErrors := listPodsInSelectors(ctx, client, copyCollector.Namespace, copyCollector.Selector)
 	if len(podsErrors) > 0 {
 		output.SaveResult(c.BundlePath, getCopyErrosFileName(copyCollector), marshalErrors(podsErrors))
@@ -11,9 +11,11 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
 	"github.com/segmentio/ksuid"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,6 +26,34 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 )
 
+type CollectCopyFromHost struct {
+	collector *troubleshootv1beta2.CopyFromHost
+	c         *Collector
+}
+
+func (c *CollectCopyFromHost) Title() string {
+	return clusterCollectorTitle("copy-from-host", c.collector.CollectorName, nil)
+}
+
+func (c *CollectCopyFromHost) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectCopyFromHost) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectCopyFromHost) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	namespace := c.collector.Namespace
+	if namespace == "" && c.c.Namespace == "" {
+		kubeconfig := k8sutil.GetKubeconfig()
+		namespace, _, _ = kubeconfig.Namespace()
+	} else if namespace == "" {
+		namespace = c.c.Namespace
+	}
+	return CopyFromHost(ctx, c.c, c.collector, namespace, c.c.ClientConfig, client)
+}
+
 // CopyFromHost is a function that copies a file or directory from a host or hosts to include in the bundle.
 func CopyFromHost(ctx context.Context, c *Collector, collector *troubleshootv1beta2.CopyFromHost, namespace string, clientConfig *restclient.Config, client kubernetes.Interface) (CollectorResult, error) {
 	labels := map[string]string{
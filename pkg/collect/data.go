@@ -2,11 +2,35 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"path/filepath"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
+type CollectData struct {
+	collector *troubleshootv1beta2.Data
+	c         *Collector
+}
+
+func (c *CollectData) Title() string {
+	return clusterCollectorTitle("data", c.collector.CollectorName, nil)
+}
+
+func (c *CollectData) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectData) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectData) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Data(c.c, c.collector)
+}
+
 func Data(c *Collector, dataCollector *troubleshootv1beta2.Data) (CollectorResult, error) {
 	bundlePath := filepath.Join(dataCollector.Name, dataCollector.CollectorName)
 
@@ -0,0 +1,389 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// dnsDefaultImage is the image kubernetes documents for debugging DNS resolution, which has dig
+	dnsDefaultImage = "registry.k8s.io/e2e-test-images/jessie-dnsutils:1.3"
+
+	// dnsScript prints the resolv.conf of the pod, and then resolves every name it is passed with
+	// the search path applied the way the libc resolver does, showing every query that is made
+	dnsScript = `echo "=== resolv.conf"
+cat /etc/resolv.conf
+for name in "$@"; do
+  echo "=== query $name"
+  dig +showsearch +time=2 +tries=1 "$name" 2>&1
+done`
+)
+
+// dnsDefaultNames are resolved when the collector does not list any names. External names are not
+// resolved by default, as they cannot be in airgapped clusters.
+var dnsDefaultNames = []string{"kubernetes.default", "kubernetes.default.svc"}
+
+type CollectDNS struct {
+	collector *troubleshootv1beta2.DNS
+	c         *Collector
+}
+
+func (c *CollectDNS) Title() string {
+	return clusterCollectorTitle("dns", c.collector.CollectorName, nil)
+}
+
+func (c *CollectDNS) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectDNS) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "create", "", "pods", "", ""),
+	}
+}
+
+func (c *CollectDNS) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return DNS(ctx, c.c, c.collector, client)
+}
+
+// DNSResult is what a pod saw when resolving the names of the dns collector, which is saved in
+// <collector name>/dns/result.json.
+type DNSResult struct {
+	Nameservers []string         `json:"nameservers"`
+	Search      []string         `json:"search"`
+	Ndots       int              `json:"ndots"`
+	Queries     []DNSQueryResult `json:"queries"`
+}
+
+// DNSQueryResult is the result of resolving a name. Status is the response code of the last
+// query, or TIMEOUT when no server answered it. Queries counts every query that was made, including
+// those for the name with the search domains appended.
+type DNSQueryResult struct {
+	Name                string   `json:"name"`
+	Status              string   `json:"status"`
+	AnsweredName        string   `json:"answeredName,omitempty"`
+	Answers             []string `json:"answers"`
+	Queries             int      `json:"queries"`
+	LatencyMilliseconds int      `json:"latencyMilliseconds"`
+	Error               string   `json:"error,omitempty"`
+}
+
+// DNSServerPod is the state of a CoreDNS pod, which are saved in <collector name>/dns/coredns-pods.json.
+type DNSServerPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node"`
+	Phase     string `json:"phase"`
+	Ready     bool   `json:"ready"`
+	Restarts  int32  `json:"restarts"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func DNS(ctx context.Context, c *Collector, dnsCollector *troubleshootv1beta2.DNS, client kubernetes.Interface) (CollectorResult, error) {
+	output := NewResult()
+	pathPrefix := GetDNSCollectorFilepath(dnsCollector.CollectorName)
+
+	errorList := []string{}
+
+	timeout := time.Minute
+	if dnsCollector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(dnsCollector.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse timeout")
+		}
+	}
+
+	logs, err := runDNSPod(ctx, client, dnsCollector, timeout)
+	if err != nil {
+		errorList = append(errorList, err.Error())
+	} else {
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "output.log"), bytes.NewBuffer(logs))
+
+		b, err := json.MarshalIndent(parseDNSOutput(string(logs)), "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal dns result")
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "result.json"), bytes.NewBuffer(b))
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, "coredns", metav1.GetOptions{})
+	if err != nil {
+		errorList = append(errorList, fmt.Sprintf("failed to get coredns configmap: %v", err))
+	} else if corefile, ok := configMap.Data["Corefile"]; ok {
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "Corefile"), bytes.NewBufferString(corefile))
+	}
+
+	pods, podsErrors := listPodsInSelectors(ctx, client, "kube-system", []string{"k8s-app=kube-dns"})
+	errorList = append(errorList, podsErrors...)
+	if len(podsErrors) == 0 {
+		serverPods := []DNSServerPod{}
+		for _, pod := range pods {
+			serverPods = append(serverPods, getDNSServerPod(pod))
+		}
+		b, err := json.MarshalIndent(serverPods, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal coredns pods")
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "coredns-pods.json"), bytes.NewBuffer(b))
+	}
+
+	output.SaveResult(c.BundlePath, path.Join(pathPrefix, "errors.json"), marshalErrors(errorList))
+
+	return output, nil
+}
+
+func GetDNSCollectorFilepath(name string) string {
+	if name != "" {
+		return path.Join(name, "dns")
+	}
+	return "dns"
+}
+
+// runDNSPod runs the dns script in a pod, and returns its output once the pod has completed
+func runDNSPod(ctx context.Context, client kubernetes.Interface, dnsCollector *troubleshootv1beta2.DNS, timeout time.Duration) ([]byte, error) {
+	image := dnsCollector.Image
+	if image == "" {
+		image = dnsDefaultImage
+	}
+	names := dnsCollector.Names
+	if len(names) == 0 {
+		names = dnsDefaultNames
+	}
+
+	runPodCollector := &troubleshootv1beta2.RunPod{
+		CollectorMeta: troubleshootv1beta2.CollectorMeta{
+			CollectorName: dnsCollector.CollectorName,
+		},
+		Name:            "dns",
+		Namespace:       dnsCollector.Namespace,
+		ImagePullSecret: dnsCollector.ImagePullSecret,
+		PodSpec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Name:            "dns",
+					Command:         []string{"/bin/sh", "-c", dnsScript, "dns"},
+					Args:            names,
+				},
+			},
+		},
+	}
+
+	pod, err := runPodWithSpec(ctx, client, runPodCollector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run pod")
+	}
+	defer func() {
+		// the parent context may have timed out, and the pod has to be deleted regardless
+		if err := client.CoreV1().Pods(pod.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			logger.Printf("Failed to delete pod %s: %v", pod.Name, err)
+		}
+	}()
+	if dnsCollector.ImagePullSecret != nil && dnsCollector.ImagePullSecret.Data != nil {
+		defer func() {
+			for _, k := range pod.Spec.ImagePullSecrets {
+				if err := client.CoreV1().Secrets(pod.Namespace).Delete(context.Background(), k.Name, metav1.DeleteOptions{}); err != nil {
+					logger.Printf("Failed to delete secret %s: %v", k.Name, err)
+				}
+			}
+		}()
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		status, err := client.CoreV1().Pods(pod.Namespace).Get(timeoutCtx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get pod")
+		}
+		if _, ok := getRunPodResult(status); ok {
+			break
+		}
+		for _, v := range status.Status.ContainerStatuses {
+			if v.State.Waiting != nil && v.State.Waiting.Reason == "ImagePullBackOff" {
+				return nil, errors.Errorf("dns pod aborted after getting pod status 'ImagePullBackOff'")
+			}
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return nil, errors.Errorf("dns pod did not complete in %s", timeout)
+		case <-time.After(time.Second):
+		}
+	}
+
+	logs, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(timeoutCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pod logs")
+	}
+	return logs, nil
+}
+
+func getDNSServerPod(pod corev1.Pod) DNSServerPod {
+	serverPod := DNSServerPod{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+		Reason:    pod.Status.Reason,
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			serverPod.Ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		serverPod.Restarts += status.RestartCount
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			serverPod.Reason = status.State.Waiting.Reason
+		}
+	}
+	return serverPod
+}
+
+// parseDNSOutput parses the output of the dns script, which is resolv.conf followed by the output
+// of dig for every name, e.g.
+//
+//	=== query kubernetes.default
+//	;; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 29472
+//	;; QUESTION SECTION:
+//	;kubernetes.default.default.svc.cluster.local. IN A
+//	...
+//	;; ANSWER SECTION:
+//	kubernetes.default.svc.cluster.local. 30 IN A 10.96.0.1
+//	;; Query time: 1 msec
+//
+// with one response for every query made while applying the search path.
+func parseDNSOutput(output string) DNSResult {
+	result := DNSResult{
+		Nameservers: []string{},
+		Search:      []string{},
+		Ndots:       1,
+		Queries:     []DNSQueryResult{},
+	}
+
+	var query *DNSQueryResult
+	section := ""
+	question := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "=== resolv.conf" {
+			section = "resolv.conf"
+			continue
+		}
+		if strings.HasPrefix(line, "=== query ") {
+			if query != nil {
+				result.Queries = append(result.Queries, *query)
+			}
+			query = &DNSQueryResult{
+				Name:    strings.TrimPrefix(line, "=== query "),
+				Answers: []string{},
+			}
+			section = "query"
+			continue
+		}
+
+		if section == "resolv.conf" {
+			parseResolvConfLine(line, &result)
+			continue
+		}
+		if query == nil || line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, ";; ->>HEADER<<-"):
+			query.Queries++
+			query.Status = ""
+			query.AnsweredName = ""
+			query.Answers = []string{}
+			for _, field := range strings.Split(line, ",") {
+				if parts := strings.SplitN(strings.TrimSpace(field), "status: ", 2); len(parts) == 2 {
+					query.Status = parts[1]
+				}
+			}
+			section = "query"
+		case strings.Contains(line, "no servers could be reached"):
+			query.Queries++
+			query.Status = "TIMEOUT"
+			query.AnsweredName = ""
+			query.Answers = []string{}
+		case strings.HasPrefix(line, ";; QUESTION SECTION:"):
+			section = "question"
+		case strings.HasPrefix(line, ";; ANSWER SECTION:"):
+			section = "answer"
+		case strings.HasPrefix(line, ";; Query time:"):
+			fields := strings.Fields(strings.TrimPrefix(line, ";; Query time:"))
+			if len(fields) > 0 {
+				if ms, err := strconv.Atoi(fields[0]); err == nil {
+					query.LatencyMilliseconds += ms
+				}
+			}
+		case strings.HasPrefix(line, ";;"):
+			section = "query"
+		case section == "question":
+			fields := strings.Fields(strings.TrimPrefix(line, ";"))
+			if len(fields) > 0 {
+				question = strings.TrimSuffix(fields[0], ".")
+			}
+		case section == "answer":
+			// owner, ttl, class, type and the data of the record
+			fields := strings.Fields(line)
+			if len(fields) >= 5 {
+				query.AnsweredName = question
+				query.Answers = append(query.Answers, strings.Join(fields[3:], " "))
+			}
+		case strings.HasPrefix(line, ";"):
+			// comments of dig, e.g. its version and the options of the query
+		case query.Queries == 0 && query.Error == "":
+			// dig could not be run, or could not make a query
+			query.Error = line
+		}
+	}
+	if query != nil {
+		result.Queries = append(result.Queries, *query)
+	}
+
+	return result
+}
+
+func parseResolvConfLine(line string, result *DNSResult) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return
+	}
+
+	switch fields[0] {
+	case "nameserver":
+		result.Nameservers = append(result.Nameservers, fields[1])
+	case "search":
+		result.Search = fields[1:]
+	case "options":
+		for _, option := range fields[1:] {
+			if strings.HasPrefix(option, "ndots:") {
+				if ndots, err := strconv.Atoi(strings.TrimPrefix(option, "ndots:")); err == nil {
+					result.Ndots = ndots
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDNSOutput(t *testing.T) {
+	output := `=== resolv.conf
+search default.svc.cluster.local svc.cluster.local cluster.local
+nameserver 10.96.0.10
+options ndots:5
+=== query kubernetes.default
+; <<>> DiG 9.9.5-9+deb8u19-Debian <<>> +showsearch +time=2 +tries=1 kubernetes.default
+;; global options: +cmd
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NXDOMAIN, id: 22117
+;; flags: qr aa rd; QUERY: 1, ANSWER: 0, AUTHORITY: 1, ADDITIONAL: 0
+
+;; QUESTION SECTION:
+;kubernetes.default.default.svc.cluster.local. IN A
+
+;; AUTHORITY SECTION:
+cluster.local.		30	IN	SOA	ns.dns.cluster.local. hostmaster.cluster.local. 1654084800 7200 1800 86400 30
+
+;; Query time: 1 msec
+;; SERVER: 10.96.0.10#53(10.96.0.10)
+;; WHEN: Wed Jun 01 12:00:00 UTC 2022
+;; MSG SIZE  rcvd: 155
+
+; <<>> DiG 9.9.5-9+deb8u19-Debian <<>> +showsearch +time=2 +tries=1 kubernetes.default
+;; global options: +cmd
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NOERROR, id: 4062
+;; flags: qr aa rd; QUERY: 1, ANSWER: 1, AUTHORITY: 0, ADDITIONAL: 0
+
+;; QUESTION SECTION:
+;kubernetes.default.svc.cluster.local. IN	A
+
+;; ANSWER SECTION:
+kubernetes.default.svc.cluster.local. 30 IN A	10.96.0.1
+
+;; Query time: 2 msec
+;; SERVER: 10.96.0.10#53(10.96.0.10)
+;; WHEN: Wed Jun 01 12:00:00 UTC 2022
+;; MSG SIZE  rcvd: 106
+
+=== query api.example.com
+; <<>> DiG 9.9.5-9+deb8u19-Debian <<>> +showsearch +time=2 +tries=1 api.example.com
+;; global options: +cmd
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NXDOMAIN, id: 1021
+;; QUESTION SECTION:
+;api.example.com.default.svc.cluster.local. IN A
+;; Query time: 1 msec
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NXDOMAIN, id: 1022
+;; QUESTION SECTION:
+;api.example.com.svc.cluster.local. IN A
+;; Query time: 1 msec
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NXDOMAIN, id: 1023
+;; QUESTION SECTION:
+;api.example.com.cluster.local. IN A
+;; Query time: 1 msec
+;; Got answer:
+;; ->>HEADER<<- opcode: QUERY, status: NXDOMAIN, id: 1024
+;; QUESTION SECTION:
+;api.example.com.		IN	A
+;; Query time: 12 msec
+=== query db.internal
+; <<>> DiG 9.9.5-9+deb8u19-Debian <<>> +showsearch +time=2 +tries=1 db.internal
+;; global options: +cmd
+;; connection timed out; no servers could be reached
+=== query missing
+/bin/sh: 5: dig: not found
+`
+
+	assert.Equal(t, DNSResult{
+		Nameservers: []string{"10.96.0.10"},
+		Search:      []string{"default.svc.cluster.local", "svc.cluster.local", "cluster.local"},
+		Ndots:       5,
+		Queries: []DNSQueryResult{
+			{
+				Name:                "kubernetes.default",
+				Status:              "NOERROR",
+				AnsweredName:        "kubernetes.default.svc.cluster.local",
+				Answers:             []string{"A 10.96.0.1"},
+				Queries:             2,
+				LatencyMilliseconds: 3,
+			},
+			{
+				Name:                "api.example.com",
+				Status:              "NXDOMAIN",
+				Answers:             []string{},
+				Queries:             4,
+				LatencyMilliseconds: 15,
+			},
+			{
+				Name:    "db.internal",
+				Status:  "TIMEOUT",
+				Answers: []string{},
+				Queries: 1,
+			},
+			{
+				Name:    "missing",
+				Answers: []string{},
+				Error:   "/bin/sh: 5: dig: not found",
+			},
+		},
+	}, parseDNSOutput(output))
+}
@@ -3,51 +3,91 @@ package collect
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
-func Exec(c *Collector, execCollector *troubleshootv1beta2.Exec) (CollectorResult, error) {
-	if execCollector.Timeout == "" {
-		return execWithoutTimeout(c, execCollector)
-	}
+type CollectExec struct {
+	collector *troubleshootv1beta2.Exec
+	c         *Collector
+}
 
-	timeout, err := time.ParseDuration(execCollector.Timeout)
-	if err != nil {
-		return nil, err
+func (c *CollectExec) Title() string {
+	return clusterCollectorTitle("exec", c.collector.CollectorName, c.collector.Selector)
+}
+
+func (c *CollectExec) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectExec) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	ns := pickNamespaceOrDefault(c.collector.Namespace, namespace)
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(ns, "list", "", "pods", "", ""),
+		resourceAccessReviewSpec(ns, "get", "", "pods", "exec", ""),
 	}
+}
 
-	errCh := make(chan error, 1)
-	resultCh := make(chan CollectorResult, 1)
+func (c *CollectExec) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Exec(ctx, c.c, c.collector)
+}
 
-	go func() {
-		b, err := execWithoutTimeout(c, execCollector)
+// ExecResult is the machine readable result of a command run by the exec or run collectors in a
+// container. ExitCode is -1 when the command did not run to completion, in which case Error says why.
+// Signal is the signal that terminated the command, if any. StdoutFile is the path of the command's
+// output in the bundle, if there was any.
+type ExecResult struct {
+	Namespace       string  `json:"namespace"`
+	Pod             string  `json:"pod"`
+	Container       string  `json:"container"`
+	ExitCode        int     `json:"exitCode"`
+	Signal          int     `json:"signal,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	TimedOut        bool    `json:"timedOut"`
+	Error           string  `json:"error,omitempty"`
+	StdoutFile      string  `json:"stdoutFile,omitempty"`
+}
+
+func Exec(ctx context.Context, c *Collector, execCollector *troubleshootv1beta2.Exec) (CollectorResult, error) {
+	var timeout time.Duration
+	if execCollector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(execCollector.Timeout)
 		if err != nil {
-			errCh <- err
-		} else {
-			resultCh <- b
+			return nil, err
 		}
-	}()
-
-	select {
-	case <-time.After(timeout):
-		return nil, errors.New("timeout")
-	case result := <-resultCh:
-		return result, nil
-	case err := <-errCh:
-		return nil, err
 	}
+
+	return execInPods(ctx, c, execCollector, timeout)
 }
 
-func execWithoutTimeout(c *Collector, execCollector *troubleshootv1beta2.Exec) (CollectorResult, error) {
+type execOutput struct {
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	errors   []string
+}
+
+// execInPods runs the command in every selected pod, one after another. The timeout applies to all
+// of them, and pods that the command has not completed in when it elapses are recorded as timed out.
+func execInPods(ctx context.Context, c *Collector, execCollector *troubleshootv1beta2.Exec, timeout time.Duration) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, err
@@ -55,7 +95,13 @@ func execWithoutTimeout(c *Collector, execCollector *troubleshootv1beta2.Exec) (
 
 	output := NewResult()
 
-	ctx := context.Background()
+	execCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	timedOut := false
 
 	pods, podsErrors := listPodsInSelectors(ctx, client, execCollector.Namespace, execCollector.Selector)
 	if len(podsErrors) > 0 {
@@ -64,18 +110,51 @@ func execWithoutTimeout(c *Collector, execCollector *troubleshootv1beta2.Exec) (
 
 	if len(pods) > 0 {
 		for _, pod := range pods {
-			stdout, stderr, execErrors := getExecOutputs(c, client, pod, execCollector)
-
 			bundlePath := filepath.Join(execCollector.Name, pod.Namespace, pod.Name)
-			if len(stdout) > 0 {
-				output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-stdout.txt"), bytes.NewBuffer(stdout))
+			result := ExecResult{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: getExecContainerName(pod, execCollector),
+			}
+
+			start := time.Now()
+			o := execOutput{exitCode: -1, errors: []string{"timeout"}}
+			if !timedOut {
+				// the stream is closed when the timeout elapses or the collector is cancelled
+				stdout, stderr, exitCode, execErrors := getExecOutputs(execCtx, c, client, pod, execCollector)
+				if execCtx.Err() != nil {
+					timedOut = true
+				} else {
+					o = execOutput{stdout: stdout, stderr: stderr, exitCode: exitCode, errors: execErrors}
+				}
+			}
+			result.TimedOut = timedOut
+			result.DurationSeconds = time.Since(start).Seconds()
+			result.ExitCode = o.exitCode
+
+			if len(o.stdout) > 0 {
+				result.StdoutFile = filepath.Join(bundlePath, execCollector.CollectorName+"-stdout.txt")
+				output.SaveResult(c.BundlePath, result.StdoutFile, bytes.NewBuffer(o.stdout))
 			}
-			if len(stderr) > 0 {
-				output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-stderr.txt"), bytes.NewBuffer(stderr))
+			if len(o.stderr) > 0 {
+				output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-stderr.txt"), bytes.NewBuffer(o.stderr))
+			}
+			if result.ExitCode > 128 {
+				// shells report commands that were killed by a signal as 128 + the signal number
+				result.Signal = result.ExitCode - 128
+			}
+			if result.ExitCode < 0 && len(o.errors) > 0 {
+				result.Error = o.errors[0]
 			}
 
-			if len(execErrors) > 0 {
-				output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-errors.json"), marshalErrors(execErrors))
+			b, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-result.json"), bytes.NewBuffer(b))
+
+			if len(o.errors) > 0 {
+				output.SaveResult(c.BundlePath, filepath.Join(bundlePath, execCollector.CollectorName+"-errors.json"), marshalErrors(o.errors))
 				continue
 			}
 		}
@@ -84,16 +163,92 @@ func execWithoutTimeout(c *Collector, execCollector *troubleshootv1beta2.Exec) (
 	return output, nil
 }
 
-func getExecOutputs(c *Collector, client *kubernetes.Clientset, pod corev1.Pod, execCollector *troubleshootv1beta2.Exec) ([]byte, []byte, []string) {
-	container := pod.Spec.Containers[0].Name
+// podExecFunc runs a command in a container of a pod. Collectors that run fixed commands in pods
+// take one, so that they can be tested without a cluster.
+type podExecFunc func(pod corev1.Pod, container string, command []string) execOutput
+
+// newPodExecFunc returns a podExecFunc that gives up on commands that have not completed when the
+// timeout elapses, if it is set, or when ctx is cancelled.
+func newPodExecFunc(ctx context.Context, c *Collector, client *kubernetes.Clientset, timeout time.Duration) podExecFunc {
+	return func(pod corev1.Pod, container string, command []string) execOutput {
+		execCollector := &troubleshootv1beta2.Exec{
+			ContainerName: container,
+			Command:       command,
+		}
+
+		execCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			execCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		stdout, stderr, exitCode, execErrors := getExecOutputs(execCtx, c, client, pod, execCollector)
+		if execCtx.Err() != nil {
+			return execOutput{exitCode: -1, errors: []string{"timeout"}}
+		}
+		return execOutput{stdout: stdout, stderr: stderr, exitCode: exitCode, errors: execErrors}
+	}
+}
+
+// getPodExecError returns why a command run by a podExecFunc failed, preferring what the command
+// printed to the error of the exec itself, which only has the exit code.
+func getPodExecError(o execOutput) string {
+	if message := strings.TrimSpace(string(o.stderr)); message != "" {
+		return message
+	}
+	if message := strings.TrimSpace(string(o.stdout)); message != "" {
+		return message
+	}
+	return o.errors[0]
+}
+
+func getExecContainerName(pod corev1.Pod, execCollector *troubleshootv1beta2.Exec) string {
 	if execCollector.ContainerName != "" {
-		container = execCollector.ContainerName
+		return execCollector.ContainerName
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// getExecOutputs runs the command in the pod, and returns its output and exit code. The exit code
+// is -1 if the command could not be run or did not complete. The stream is closed when ctx is done.
+func getExecOutputs(ctx context.Context, c *Collector, client *kubernetes.Clientset, pod corev1.Pod, execCollector *troubleshootv1beta2.Exec) ([]byte, []byte, int, []string) {
+	req, err := getExecRequest(client, pod, execCollector)
+	if err != nil {
+		return nil, nil, -1, []string{err.Error()}
+	}
+
+	exec, err := newSPDYExecutorWithContext(ctx, c.ClientConfig, "POST", req.URL())
+	if err != nil {
+		return nil, nil, -1, []string{err.Error()}
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  nil,
+		Stdout: stdout,
+		Stderr: stderr,
+		Tty:    false,
+	})
+
+	if err != nil {
+		return stdout.Bytes(), stderr.Bytes(), getExecExitCode(err), []string{err.Error()}
 	}
 
+	return stdout.Bytes(), stderr.Bytes(), 0, nil
+}
+
+// getExecRequest returns the request that runs the command in the pod and streams back both its
+// stdout and stderr.
+func getExecRequest(client kubernetes.Interface, pod corev1.Pod, execCollector *troubleshootv1beta2.Exec) (*restclient.Request, error) {
+	container := getExecContainerName(pod, execCollector)
+
 	req := client.CoreV1().RESTClient().Post().Resource("pods").Name(pod.Name).Namespace(pod.Namespace).SubResource("exec")
 	scheme := runtime.NewScheme()
 	if err := corev1.AddToScheme(scheme); err != nil {
-		return nil, nil, []string{err.Error()}
+		return nil, err
 	}
 
 	parameterCodec := runtime.NewParameterCodec(scheme)
@@ -101,31 +256,55 @@ func getExecOutputs(c *Collector, client *kubernetes.Clientset, pod corev1.Pod,
 		Command:   append(execCollector.Command, execCollector.Args...),
 		Container: container,
 		Stdin:     true,
-		Stdout:    false,
+		Stdout:    true,
 		Stderr:    true,
 		TTY:       false,
 	}, parameterCodec)
 
-	exec, err := remotecommand.NewSPDYExecutor(c.ClientConfig, "POST", req.URL())
+	return req, nil
+}
+
+// newSPDYExecutorWithContext returns an executor whose stream is closed when ctx is done, which
+// remotecommand does not support itself.
+func newSPDYExecutorWithContext(ctx context.Context, config *restclient.Config, method string, url *url.URL) (remotecommand.Executor, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(config)
 	if err != nil {
-		return nil, nil, []string{err.Error()}
+		return nil, err
 	}
+	return remotecommand.NewSPDYExecutorForTransports(transport, &contextUpgrader{Upgrader: upgrader, ctx: ctx}, method, url)
+}
 
-	stdout := new(bytes.Buffer)
-	stderr := new(bytes.Buffer)
-
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdin:  nil,
-		Stdout: stdout,
-		Stderr: stderr,
-		Tty:    false,
-	})
+// contextUpgrader closes the connections it upgrades when its context is done
+type contextUpgrader struct {
+	spdy.Upgrader
+	ctx context.Context
+}
 
+func (u *contextUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
 	if err != nil {
-		return stdout.Bytes(), stderr.Bytes(), []string{err.Error()}
+		return nil, err
 	}
 
-	return stdout.Bytes(), stderr.Bytes(), nil
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+
+	return conn, nil
+}
+
+// getExecExitCode returns the exit code of a command that exited with an error, or -1 if the error
+// is not about the command's exit code, e.g. because the container could not be reached.
+func getExecExitCode(err error) int {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+	return -1
 }
 
 func getExecErrosFileName(execCollector *troubleshootv1beta2.Exec) string {
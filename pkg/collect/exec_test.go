@@ -0,0 +1,100 @@
+package collect
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+)
+
+type testConnection struct {
+	httpstream.Connection
+
+	closeOnce sync.Once
+	closed    chan bool
+}
+
+func (c *testConnection) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *testConnection) CloseChan() <-chan bool {
+	return c.closed
+}
+
+type testUpgrader struct {
+	conn *testConnection
+}
+
+func (u *testUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	return u.conn, nil
+}
+
+func Test_contextUpgrader(t *testing.T) {
+	req := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &testConnection{closed: make(chan bool)}
+	upgrader := &contextUpgrader{Upgrader: &testUpgrader{conn}, ctx: ctx}
+
+	_, err := upgrader.NewConnection(&http.Response{})
+	req.NoError(err)
+
+	select {
+	case <-conn.closed:
+		t.Fatal("connection closed before the context was done")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case <-conn.closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection not closed when the context was done")
+	}
+}
+
+func Test_getExecRequest(t *testing.T) {
+	req := require.New(t)
+
+	client, err := kubernetes.NewForConfig(&restclient.Config{Host: "https://kubernetes.default"})
+	req.NoError(err)
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "calico-node-abcde", Namespace: "kube-system"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "calico-node"}},
+		},
+	}
+	execCollector := &troubleshootv1beta2.Exec{
+		Command: []string{"calicoctl"},
+		Args:    []string{"node", "status"},
+	}
+
+	request, err := getExecRequest(client, pod, execCollector)
+	req.NoError(err)
+
+	url := request.URL()
+	assert.Equal(t, "/api/v1/namespaces/kube-system/pods/calico-node-abcde/exec", url.Path)
+
+	query := url.Query()
+	assert.Equal(t, []string{"calicoctl", "node", "status"}, query["command"])
+	assert.Equal(t, "calico-node", query.Get("container"))
+	assert.Equal(t, "true", query.Get("stdout"))
+	assert.Equal(t, "true", query.Get("stderr"))
+	assert.Equal(t, "", query.Get("tty"))
+}
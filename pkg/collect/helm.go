@@ -0,0 +1,284 @@
+package collect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"gopkg.in/yaml.v2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// helmReleaseGzipMagic is the header of gzipped release payloads. Helm only compresses releases
+// since 3.0, so payloads without it are plain JSON.
+var helmReleaseGzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// helmSensitiveValueKey matches the keys of Helm values that are masked.
+var helmSensitiveValueKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private_?key|access_?key|api_?key)`)
+
+type CollectHelm struct {
+	collector *troubleshootv1beta2.Helm
+	c         *Collector
+}
+
+func (c *CollectHelm) Title() string {
+	return clusterCollectorTitle("helm", c.collector.CollectorName, nil)
+}
+
+func (c *CollectHelm) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectHelm) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(c.collector.Namespace, "list", "", "secrets", "", ""),
+	}
+}
+
+func (c *CollectHelm) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Helm(ctx, c.c, c.collector, client)
+}
+
+// HelmReleaseInfo is the collected state of a Helm release, which is saved for every release in
+// <collector name>/helm/<namespace>/<release name>.json.
+type HelmReleaseInfo struct {
+	Name         string                `json:"name"`
+	Namespace    string                `json:"namespace"`
+	Chart        string                `json:"chart"`
+	ChartVersion string                `json:"chartVersion"`
+	AppVersion   string                `json:"appVersion,omitempty"`
+	Status       string                `json:"status"`
+	Revision     int                   `json:"revision"`
+	Updated      time.Time             `json:"updated"`
+	Description  string                `json:"description,omitempty"`
+	History      []HelmReleaseRevision `json:"history"`
+}
+
+// HelmReleaseRevision is a single revision of a Helm release, the most recent first.
+type HelmReleaseRevision struct {
+	Revision     int       `json:"revision"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Updated      time.Time `json:"updated"`
+	Description  string    `json:"description,omitempty"`
+}
+
+// helmRelease is the part of a release stored by Helm 3 that is collected.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		LastDeployed time.Time `json:"last_deployed"`
+		Description  string    `json:"description"`
+		Status       string    `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]interface{} `json:"config"`
+}
+
+func Helm(ctx context.Context, c *Collector, helmCollector *troubleshootv1beta2.Helm, client kubernetes.Interface) (CollectorResult, error) {
+	output := NewResult()
+	pathPrefix := GetHelmCollectorFilepath(helmCollector.CollectorName)
+
+	selector := []string{"owner=helm"}
+	if helmCollector.ReleaseName != "" {
+		selector = append(selector, fmt.Sprintf("name=%s", helmCollector.ReleaseName))
+	}
+
+	secrets, err := client.CoreV1().Secrets(helmCollector.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: strings.Join(selector, ","),
+	})
+	if err != nil {
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, "errors.json"), marshalErrors([]string{err.Error()}))
+		return output, nil
+	}
+
+	errorList := []string{}
+	releases := map[string][]*helmRelease{}
+	for _, secret := range secrets.Items {
+		if secret.Type != "helm.sh/release.v1" {
+			continue
+		}
+
+		release, err := decodeHelmRelease(&secret)
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s/%s: %v", secret.Namespace, secret.Name, err))
+			continue
+		}
+
+		key := path.Join(release.Namespace, release.Name)
+		releases[key] = append(releases[key], release)
+	}
+
+	for key, revisions := range releases {
+		sort.Slice(revisions, func(i, j int) bool {
+			return revisions[i].Version > revisions[j].Version
+		})
+
+		info := helmReleaseInfo(revisions)
+		b, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, fmt.Sprintf("%s.json", key)), bytes.NewBuffer(b))
+
+		if !helmCollector.IncludeValues {
+			continue
+		}
+
+		// values are saved on their own so that redactors can match them like any other yaml file
+		values, err := yaml.Marshal(maskHelmValues(revisions[0].Config))
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		output.SaveResult(c.BundlePath, path.Join(pathPrefix, fmt.Sprintf("%s-values.yaml", key)), bytes.NewBuffer(values))
+	}
+
+	output.SaveResult(c.BundlePath, path.Join(pathPrefix, "errors.json"), marshalErrors(errorList))
+
+	return output, nil
+}
+
+// maskHelmValues masks everything under keys that look like they hold credentials, e.g. password
+// or apiToken, as values often include them and the default redactors only know some formats.
+func maskHelmValues(values interface{}) interface{} {
+	switch v := values.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if helmSensitiveValueKey.MatchString(key) {
+				masked[key] = maskAllHelmValues(value)
+			} else {
+				masked[key] = maskHelmValues(value)
+			}
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, value := range v {
+			masked[i] = maskHelmValues(value)
+		}
+		return masked
+	}
+	return values
+}
+
+func maskAllHelmValues(values interface{}) interface{} {
+	switch v := values.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			masked[key] = maskAllHelmValues(value)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, value := range v {
+			masked[i] = maskAllHelmValues(value)
+		}
+		return masked
+	}
+	return redact.MASK_TEXT
+}
+
+func GetHelmCollectorFilepath(name string) string {
+	if name != "" {
+		return path.Join(name, "helm")
+	}
+	return "helm"
+}
+
+// decodeHelmRelease decodes the release stored in a Helm secret, which is base64 encoded and
+// usually gzipped on top of the encoding of the secret data itself.
+func decodeHelmRelease(secret *corev1.Secret) (*helmRelease, error) {
+	data, ok := secret.Data["release"]
+	if !ok {
+		return nil, errors.New("secret has no release")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode release")
+	}
+
+	if bytes.HasPrefix(b, helmReleaseGzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create gzip reader")
+		}
+		defer r.Close()
+		b, err = ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress release")
+		}
+	}
+
+	release := &helmRelease{}
+	if err := json.Unmarshal(b, release); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal release")
+	}
+	if release.Namespace == "" {
+		release.Namespace = secret.Namespace
+	}
+
+	return release, nil
+}
+
+// helmReleaseInfo summarizes the revisions of a release, which are sorted from the most recent.
+func helmReleaseInfo(revisions []*helmRelease) HelmReleaseInfo {
+	current := revisions[0]
+	info := HelmReleaseInfo{
+		Name:         current.Name,
+		Namespace:    current.Namespace,
+		Chart:        current.Chart.Metadata.Name,
+		ChartVersion: current.Chart.Metadata.Version,
+		AppVersion:   current.Chart.Metadata.AppVersion,
+		Status:       current.Info.Status,
+		Revision:     current.Version,
+		Updated:      current.Info.LastDeployed,
+		Description:  current.Info.Description,
+		History:      []HelmReleaseRevision{},
+	}
+
+	for _, revision := range revisions {
+		info.History = append(info.History, HelmReleaseRevision{
+			Revision:     revision.Version,
+			Status:       revision.Info.Status,
+			Chart:        revision.Chart.Metadata.Name,
+			ChartVersion: revision.Chart.Metadata.Version,
+			AppVersion:   revision.Chart.Metadata.AppVersion,
+			Updated:      revision.Info.LastDeployed,
+			Description:  revision.Info.Description,
+		})
+	}
+
+	return info
+}
@@ -0,0 +1,203 @@
+package collect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHelm(t *testing.T) {
+	firstDeployed := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	upgraded := time.Date(2022, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		helmCollector *troubleshootv1beta2.Helm
+		want          CollectorResult
+	}{
+		{
+			name:          "all releases with values",
+			helmCollector: &troubleshootv1beta2.Helm{IncludeValues: true},
+			want: CollectorResult{
+				"helm/app/api.json": mustJSONMarshalIndent(t, HelmReleaseInfo{
+					Name:         "api",
+					Namespace:    "app",
+					Chart:        "api",
+					ChartVersion: "1.1.0",
+					AppVersion:   "2.0.0",
+					Status:       "failed",
+					Revision:     2,
+					Updated:      upgraded,
+					Description:  "Upgrade \"api\" failed: timed out waiting for the condition",
+					History: []HelmReleaseRevision{
+						{
+							Revision:     2,
+							Status:       "failed",
+							Chart:        "api",
+							ChartVersion: "1.1.0",
+							AppVersion:   "2.0.0",
+							Updated:      upgraded,
+							Description:  "Upgrade \"api\" failed: timed out waiting for the condition",
+						},
+						{
+							Revision:     1,
+							Status:       "superseded",
+							Chart:        "api",
+							ChartVersion: "1.0.0",
+							AppVersion:   "1.9.0",
+							Updated:      firstDeployed,
+							Description:  "Install complete",
+						},
+					},
+				}),
+				"helm/app/api-values.yaml": []byte("database:\n  password: '***HIDDEN***'\nreplicas: 3\n"),
+				"helm/monitoring/metrics.json": mustJSONMarshalIndent(t, HelmReleaseInfo{
+					Name:         "metrics",
+					Namespace:    "monitoring",
+					Chart:        "metrics",
+					ChartVersion: "0.3.1",
+					Status:       "deployed",
+					Revision:     1,
+					Updated:      firstDeployed,
+					Description:  "Install complete",
+					History: []HelmReleaseRevision{
+						{
+							Revision:     1,
+							Status:       "deployed",
+							Chart:        "metrics",
+							ChartVersion: "0.3.1",
+							Updated:      firstDeployed,
+							Description:  "Install complete",
+						},
+					},
+				}),
+				"helm/monitoring/metrics-values.yaml": []byte("{}\n"),
+				"helm/errors.json": mustJSONMarshalIndent(t, []string{
+					"app/sh.helm.release.v1.broken.v1: failed to decode release: illegal base64 data at input byte 3",
+				}),
+			},
+		},
+		{
+			name: "single release",
+			helmCollector: &troubleshootv1beta2.Helm{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "releases"},
+				Namespace:     "monitoring",
+				ReleaseName:   "metrics",
+			},
+			want: CollectorResult{
+				"releases/helm/monitoring/metrics.json": mustJSONMarshalIndent(t, HelmReleaseInfo{
+					Name:         "metrics",
+					Namespace:    "monitoring",
+					Chart:        "metrics",
+					ChartVersion: "0.3.1",
+					Status:       "deployed",
+					Revision:     1,
+					Updated:      firstDeployed,
+					Description:  "Install complete",
+					History: []HelmReleaseRevision{
+						{
+							Revision:     1,
+							Status:       "deployed",
+							Chart:        "metrics",
+							ChartVersion: "0.3.1",
+							Updated:      firstDeployed,
+							Description:  "Install complete",
+						},
+					},
+				}),
+			},
+		},
+	}
+
+	secrets := []corev1.Secret{
+		helmReleaseSecret(t, "app", "api", 1, true, fmt.Sprintf(`{
+			"name": "api", "namespace": "app", "version": 1,
+			"info": {"status": "superseded", "last_deployed": %q, "description": "Install complete"},
+			"chart": {"metadata": {"name": "api", "version": "1.0.0", "appVersion": "1.9.0"}},
+			"config": {"replicas": 2}
+		}`, firstDeployed.Format(time.RFC3339))),
+		helmReleaseSecret(t, "app", "api", 2, true, fmt.Sprintf(`{
+			"name": "api", "namespace": "app", "version": 2,
+			"info": {"status": "failed", "last_deployed": %q, "description": "Upgrade \"api\" failed: timed out waiting for the condition"},
+			"chart": {"metadata": {"name": "api", "version": "1.1.0", "appVersion": "2.0.0"}},
+			"config": {"replicas": 3, "database": {"password": "hunter2"}}
+		}`, upgraded.Format(time.RFC3339))),
+		helmReleaseSecret(t, "monitoring", "metrics", 1, false, fmt.Sprintf(`{
+			"name": "metrics", "namespace": "monitoring", "version": 1,
+			"info": {"status": "deployed", "last_deployed": %q, "description": "Install complete"},
+			"chart": {"metadata": {"name": "metrics", "version": "0.3.1"}}
+		}`, firstDeployed.Format(time.RFC3339))),
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.broken.v1",
+				Namespace: "app",
+				Labels:    map[string]string{"owner": "helm", "name": "broken"},
+			},
+			Type: "helm.sh/release.v1",
+			Data: map[string][]byte{"release": []byte("not base64")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-credentials",
+				Namespace: "app",
+				Labels:    map[string]string{"owner": "helm", "name": "api"},
+			},
+			Type: corev1.SecretTypeOpaque,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := testclient.NewSimpleClientset()
+			for _, secret := range secrets {
+				_, err := client.CoreV1().Secrets(secret.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			got, err := Helm(ctx, &Collector{}, tt.helmCollector, client)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func helmReleaseSecret(t *testing.T, namespace string, name string, revision int, gzipped bool, release string) corev1.Secret {
+	data := []byte(release)
+	if gzipped {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		data = buf.Bytes()
+	}
+
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"owner":   "helm",
+				"name":    name,
+				"version": fmt.Sprintf("%d", revision),
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			"release": []byte(base64.StdEncoding.EncodeToString(data)),
+		},
+	}
+}
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"path/filepath"
 
@@ -29,11 +30,11 @@ func (c *CollectHostHTTP) Collect(progressChan chan<- interface{}) (map[string][
 	var err error
 
 	if httpCollector.Get != nil {
-		response, err = doGet(httpCollector.Get)
+		response, err = doGet(context.Background(), httpCollector.Get)
 	} else if httpCollector.Post != nil {
-		response, err = doPost(httpCollector.Post)
+		response, err = doPost(context.Background(), httpCollector.Post)
 	} else if httpCollector.Put != nil {
-		response, err = doPut(httpCollector.Put)
+		response, err = doPut(context.Background(), httpCollector.Put)
 	} else {
 		return nil, errors.New("no supported http request type")
 	}
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -11,8 +12,31 @@ import (
 	"strings"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
+type CollectHTTP struct {
+	collector *troubleshootv1beta2.HTTP
+	c         *Collector
+}
+
+func (c *CollectHTTP) Title() string {
+	return clusterCollectorTitle("http", c.collector.CollectorName, nil)
+}
+
+func (c *CollectHTTP) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectHTTP) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectHTTP) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return HTTP(ctx, c.c, c.collector)
+}
+
 type HTTPResponse struct {
 	Status  int               `json:"status"`
 	Body    string            `json:"body"`
@@ -33,16 +57,16 @@ var (
 	}
 )
 
-func HTTP(c *Collector, httpCollector *troubleshootv1beta2.HTTP) (CollectorResult, error) {
+func HTTP(ctx context.Context, c *Collector, httpCollector *troubleshootv1beta2.HTTP) (CollectorResult, error) {
 	var response *http.Response
 	var err error
 
 	if httpCollector.Get != nil {
-		response, err = doGet(httpCollector.Get)
+		response, err = doGet(ctx, httpCollector.Get)
 	} else if httpCollector.Post != nil {
-		response, err = doPost(httpCollector.Post)
+		response, err = doPost(ctx, httpCollector.Post)
 	} else if httpCollector.Put != nil {
-		response, err = doPut(httpCollector.Put)
+		response, err = doPut(ctx, httpCollector.Put)
 	} else {
 		return nil, errors.New("no supported http request type")
 	}
@@ -63,13 +87,13 @@ func HTTP(c *Collector, httpCollector *troubleshootv1beta2.HTTP) (CollectorResul
 	return output, nil
 }
 
-func doGet(get *troubleshootv1beta2.Get) (*http.Response, error) {
+func doGet(ctx context.Context, get *troubleshootv1beta2.Get) (*http.Response, error) {
 	httpClient := http.DefaultClient
 	if get.InsecureSkipVerify {
 		httpClient = httpInsecureClient
 	}
 
-	req, err := http.NewRequest("GET", get.URL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", get.URL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +105,13 @@ func doGet(get *troubleshootv1beta2.Get) (*http.Response, error) {
 	return httpClient.Do(req)
 }
 
-func doPost(post *troubleshootv1beta2.Post) (*http.Response, error) {
+func doPost(ctx context.Context, post *troubleshootv1beta2.Post) (*http.Response, error) {
 	httpClient := http.DefaultClient
 	if post.InsecureSkipVerify {
 		httpClient = httpInsecureClient
 	}
 
-	req, err := http.NewRequest("POST", post.URL, strings.NewReader(post.Body))
+	req, err := http.NewRequestWithContext(ctx, "POST", post.URL, strings.NewReader(post.Body))
 	if err != nil {
 		return nil, err
 	}
@@ -99,13 +123,13 @@ func doPost(post *troubleshootv1beta2.Post) (*http.Response, error) {
 	return httpClient.Do(req)
 }
 
-func doPut(put *troubleshootv1beta2.Put) (*http.Response, error) {
+func doPut(ctx context.Context, put *troubleshootv1beta2.Put) (*http.Response, error) {
 	httpClient := http.DefaultClient
 	if put.InsecureSkipVerify {
 		httpClient = httpInsecureClient
 	}
 
-	req, err := http.NewRequest("PUT", put.URL, strings.NewReader(put.Body))
+	req, err := http.NewRequestWithContext(ctx, "PUT", put.URL, strings.NewReader(put.Body))
 	if err != nil {
 		return nil, err
 	}
@@ -10,12 +10,38 @@ import (
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-func Logs(c *Collector, logsCollector *troubleshootv1beta2.Logs) (CollectorResult, error) {
+type CollectLogs struct {
+	collector *troubleshootv1beta2.Logs
+	c         *Collector
+}
+
+func (c *CollectLogs) Title() string {
+	return clusterCollectorTitle("logs", c.collector.CollectorName, c.collector.Selector)
+}
+
+func (c *CollectLogs) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectLogs) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	ns := pickNamespaceOrDefault(c.collector.Namespace, namespace)
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(ns, "list", "", "pods", "", ""),
+		resourceAccessReviewSpec(ns, "get", "", "pods", "log", ""),
+	}
+}
+
+func (c *CollectLogs) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Logs(ctx, c.c, c.collector)
+}
+
+func Logs(ctx context.Context, c *Collector, logsCollector *troubleshootv1beta2.Logs) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, err
@@ -23,8 +49,6 @@ func Logs(c *Collector, logsCollector *troubleshootv1beta2.Logs) (CollectorResul
 
 	output := NewResult()
 
-	ctx := context.Background()
-
 	pods, podsErrors := listPodsInSelectors(ctx, client, logsCollector.Namespace, logsCollector.Selector)
 	if len(podsErrors) > 0 {
 		output.SaveResult(c.BundlePath, getLogsErrorsFileName(logsCollector), marshalErrors(podsErrors))
@@ -84,7 +108,7 @@ func Logs(c *Collector, logsCollector *troubleshootv1beta2.Logs) (CollectorResul
 	return output, nil
 }
 
-func listPodsInSelectors(ctx context.Context, client *kubernetes.Clientset, namespace string, selector []string) ([]corev1.Pod, []string) {
+func listPodsInSelectors(ctx context.Context, client kubernetes.Interface, namespace string, selector []string) ([]corev1.Pod, []string) {
 	serializedLabelSelector := strings.Join(selector, ",")
 
 	listOptions := metav1.ListOptions{
@@ -17,20 +17,41 @@ import (
 	longhornv1beta1 "github.com/replicatedhq/troubleshoot/pkg/longhorn/client/clientset/versioned/typed/longhorn/v1beta1"
 	longhorntypes "github.com/replicatedhq/troubleshoot/pkg/longhorn/types"
 	"gopkg.in/yaml.v2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
 )
 
+type CollectLonghorn struct {
+	collector *troubleshootv1beta2.Longhorn
+	c         *Collector
+}
+
+func (c *CollectLonghorn) Title() string {
+	return clusterCollectorTitle("longhorn", c.collector.CollectorName, nil)
+}
+
+func (c *CollectLonghorn) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectLonghorn) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectLonghorn) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Longhorn(ctx, c.c, c.collector)
+}
+
 const (
 	DefaultLonghornNamespace = "longhorn-system"
 )
 
 var checksumRX = regexp.MustCompile(`(\S+)\s+(\S+)`)
 
-func Longhorn(c *Collector, longhornCollector *troubleshootv1beta2.Longhorn) (CollectorResult, error) {
-	ctx := context.TODO()
+func Longhorn(ctx context.Context, c *Collector, longhornCollector *troubleshootv1beta2.Longhorn) (CollectorResult, error) {
 
 	ns := DefaultLonghornNamespace
 	if longhornCollector.Namespace != "" {
@@ -201,7 +222,7 @@ func Longhorn(c *Collector, longhornCollector *troubleshootv1beta2.Longhorn) (Co
 		Selector:  []string{""},
 		Namespace: ns,
 	}
-	logs, err := Logs(c, logsCollector)
+	logs, err := Logs(ctx, c, logsCollector)
 	if err != nil {
 		return nil, errors.Wrap(err, "collect longhorn logs")
 	}
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -9,9 +10,32 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
-func Mysql(c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
+type CollectMysql struct {
+	collector *troubleshootv1beta2.Database
+	c         *Collector
+}
+
+func (c *CollectMysql) Title() string {
+	return clusterCollectorTitle("mysql", c.collector.CollectorName, nil)
+}
+
+func (c *CollectMysql) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectMysql) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectMysql) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Mysql(ctx, c.c, c.collector)
+}
+
+func Mysql(ctx context.Context, c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
 	databaseConnection := DatabaseConnection{}
 
 	db, err := sql.Open("mysql", databaseCollector.URI)
@@ -20,7 +44,7 @@ func Mysql(c *Collector, databaseCollector *troubleshootv1beta2.Database) (Colle
 	} else {
 		defer db.Close()
 		query := `select version()`
-		row := db.QueryRow(query)
+		row := db.QueryRowContext(ctx, query)
 
 		version := ""
 		if err := row.Scan(&version); err != nil {
@@ -32,7 +56,7 @@ func Mysql(c *Collector, databaseCollector *troubleshootv1beta2.Database) (Colle
 
 		requestedParameters := databaseCollector.Parameters
 		if len(requestedParameters) > 0 {
-			rows, err := db.Query("SHOW VARIABLES")
+			rows, err := db.QueryContext(ctx, "SHOW VARIABLES")
 
 			if err != nil {
 				databaseConnection.Error = err.Error()
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,9 +11,32 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
-func Postgres(c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
+type CollectPostgres struct {
+	collector *troubleshootv1beta2.Database
+	c         *Collector
+}
+
+func (c *CollectPostgres) Title() string {
+	return clusterCollectorTitle("postgres", c.collector.CollectorName, nil)
+}
+
+func (c *CollectPostgres) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectPostgres) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectPostgres) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Postgres(ctx, c.c, c.collector)
+}
+
+func Postgres(ctx context.Context, c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
 	databaseConnection := DatabaseConnection{}
 
 	db, err := sql.Open("postgres", databaseCollector.URI)
@@ -20,7 +44,7 @@ func Postgres(c *Collector, databaseCollector *troubleshootv1beta2.Database) (Co
 		databaseConnection.Error = err.Error()
 	} else {
 		query := `select version()`
-		row := db.QueryRow(query)
+		row := db.QueryRowContext(ctx, query)
 		version := ""
 		if err := row.Scan(&version); err != nil {
 			databaseConnection.Error = err.Error()
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -9,16 +10,39 @@ import (
 	"github.com/go-redis/redis/v7"
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
-func Redis(c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
+type CollectRedis struct {
+	collector *troubleshootv1beta2.Database
+	c         *Collector
+}
+
+func (c *CollectRedis) Title() string {
+	return clusterCollectorTitle("redis", c.collector.CollectorName, nil)
+}
+
+func (c *CollectRedis) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectRedis) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectRedis) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Redis(ctx, c.c, c.collector)
+}
+
+func Redis(ctx context.Context, c *Collector, databaseCollector *troubleshootv1beta2.Database) (CollectorResult, error) {
 	databaseConnection := DatabaseConnection{}
 
 	opt, err := redis.ParseURL(databaseCollector.URI)
 	if err != nil {
 		databaseConnection.Error = err.Error()
 	} else {
-		client := redis.NewClient(opt)
+		client := redis.NewClient(opt).WithContext(ctx)
 		stringResult := client.Info("server")
 
 		if stringResult.Err() != nil {
@@ -18,10 +18,37 @@ import (
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectRegistry struct {
+	collector *troubleshootv1beta2.RegistryImages
+	c         *Collector
+}
+
+func (c *CollectRegistry) Title() string {
+	return clusterCollectorTitle("registry-images", c.collector.CollectorName, nil)
+}
+
+func (c *CollectRegistry) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectRegistry) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	if c.collector.ImagePullSecrets == nil || c.collector.ImagePullSecrets.Data != nil {
+		return nil
+	}
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "get", "", "secrets", "", c.collector.ImagePullSecrets.Name),
+	}
+}
+
+func (c *CollectRegistry) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Registry(ctx, c.c, c.collector)
+}
+
 type RegistryImage struct {
 	Exists bool   `json:"exists"`
 	Error  string `json:"error,omitempty"`
@@ -36,13 +63,13 @@ type registryAuthConfig struct {
 	password string
 }
 
-func Registry(c *Collector, registryCollector *troubleshootv1beta2.RegistryImages) (CollectorResult, error) {
+func Registry(ctx context.Context, c *Collector, registryCollector *troubleshootv1beta2.RegistryImages) (CollectorResult, error) {
 	registryInfo := RegistryInfo{
 		Images: map[string]RegistryImage{},
 	}
 
 	for _, image := range registryCollector.Images {
-		exists, err := imageExists(c, registryCollector, image)
+		exists, err := imageExists(ctx, c, registryCollector, image)
 		if err != nil {
 			registryInfo.Images[image] = RegistryImage{
 				Error: err.Error(),
@@ -70,13 +97,13 @@ func Registry(c *Collector, registryCollector *troubleshootv1beta2.RegistryImage
 	return output, nil
 }
 
-func imageExists(c *Collector, registryCollector *troubleshootv1beta2.RegistryImages, image string) (bool, error) {
+func imageExists(ctx context.Context, c *Collector, registryCollector *troubleshootv1beta2.RegistryImages, image string) (bool, error) {
 	imageRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
 	if err != nil {
 		return false, errors.Wrapf(err, "failed to parse image name %s", image)
 	}
 
-	authConfig, err := getImageAuthConfig(c, registryCollector, imageRef)
+	authConfig, err := getImageAuthConfig(ctx, c, registryCollector, imageRef)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to get auth config")
 	}
@@ -94,7 +121,7 @@ func imageExists(c *Collector, registryCollector *troubleshootv1beta2.RegistryIm
 			}
 		}
 
-		remoteImage, err := imageRef.NewImage(context.Background(), &sysCtx)
+		remoteImage, err := imageRef.NewImage(ctx, &sysCtx)
 		if err == nil {
 			remoteImage.Close()
 			return true, nil
@@ -123,7 +150,7 @@ func imageExists(c *Collector, registryCollector *troubleshootv1beta2.RegistryIm
 	return false, errors.Wrap(lastErr, "failed to retry")
 }
 
-func getImageAuthConfig(c *Collector, registryCollector *troubleshootv1beta2.RegistryImages, imageRef types.ImageReference) (*registryAuthConfig, error) {
+func getImageAuthConfig(ctx context.Context, c *Collector, registryCollector *troubleshootv1beta2.RegistryImages, imageRef types.ImageReference) (*registryAuthConfig, error) {
 	if registryCollector.ImagePullSecrets == nil {
 		return nil, nil
 	}
@@ -144,7 +171,7 @@ func getImageAuthConfig(c *Collector, registryCollector *troubleshootv1beta2.Reg
 		if namespace == "" {
 			namespace = "default"
 		}
-		config, err := getImageAuthConfigFromSecret(c, imageRef, registryCollector.ImagePullSecrets, namespace)
+		config, err := getImageAuthConfigFromSecret(ctx, c, imageRef, registryCollector.ImagePullSecrets, namespace)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get auth from secret")
 		}
@@ -197,9 +224,7 @@ func getImageAuthConfigFromData(c *Collector, imageRef types.ImageReference, pul
 	return &authConfig, nil
 }
 
-func getImageAuthConfigFromSecret(c *Collector, imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets, namespace string) (*registryAuthConfig, error) {
-	ctx := context.Background()
-
+func getImageAuthConfigFromSecret(ctx context.Context, c *Collector, imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets, namespace string) (*registryAuthConfig, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create client from config")
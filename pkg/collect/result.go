@@ -144,6 +144,21 @@ func (r CollectorResult) CloseWriter(bundlePath string, relativePath string, wri
 	return errors.Errorf("cannot close writer of type %T", writer)
 }
 
+// removeResult deletes the files of result from the bundle directory.
+func removeResult(bundlePath string, result CollectorResult) error {
+	if bundlePath == "" {
+		return nil
+	}
+
+	for relativePath := range result {
+		err := os.Remove(filepath.Join(bundlePath, relativePath))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove file")
+		}
+	}
+	return nil
+}
+
 func TarSupportBundleDir(bundlePath string, input CollectorResult, outputFilename string) error {
 	fileWriter, err := os.Create(outputFilename)
 	if err != nil {
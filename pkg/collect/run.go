@@ -5,17 +5,66 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-func Run(c *Collector, runCollector *troubleshootv1beta2.Run) (CollectorResult, error) {
+type CollectRun struct {
+	collector *troubleshootv1beta2.Run
+	c         *Collector
+}
+
+func (c *CollectRun) Title() string {
+	return clusterCollectorTitle("run", c.collector.CollectorName, nil)
+}
+
+func (c *CollectRun) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectRun) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "create", "", "pods", "", ""),
+	}
+}
+
+func (c *CollectRun) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Run(ctx, c.c, c.collector)
+}
+
+type CollectRunPod struct {
+	collector *troubleshootv1beta2.RunPod
+	c         *Collector
+}
+
+func (c *CollectRunPod) Title() string {
+	return clusterCollectorTitle("run-pod", c.collector.CollectorName, nil)
+}
+
+func (c *CollectRunPod) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectRunPod) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "create", "", "pods", "", ""),
+	}
+}
+
+func (c *CollectRunPod) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return RunPod(ctx, c.c, c.collector)
+}
+
+func Run(ctx context.Context, c *Collector, runCollector *troubleshootv1beta2.Run) (CollectorResult, error) {
 	pullPolicy := corev1.PullIfNotPresent
 	if runCollector.ImagePullPolicy != "" {
 		pullPolicy = corev1.PullPolicy(runCollector.ImagePullPolicy)
@@ -54,12 +103,10 @@ func Run(c *Collector, runCollector *troubleshootv1beta2.Run) (CollectorResult,
 		},
 	}
 
-	return RunPod(c, runPodCollector)
+	return RunPod(ctx, c, runPodCollector)
 }
 
-func RunPod(c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (CollectorResult, error) {
-	ctx := context.Background()
-
+func RunPod(ctx context.Context, c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create client from config")
@@ -93,6 +140,31 @@ func RunPod(c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (Collecto
 		return nil, errors.Wrap(err, "failed to parse timeout")
 	}
 
+	return runWithTimeout(ctx, timeout, func(ctx context.Context) (CollectorResult, error) {
+		return runWithoutTimeout(ctx, c, pod, runPodCollector)
+	}, func() (CollectorResult, error) {
+		// the pod's output so far is lost, but the result records that it timed out
+		output := NewResult()
+		result := ExecResult{
+			Namespace:       pod.Namespace,
+			Pod:             pod.Name,
+			Container:       pod.Spec.Containers[0].Name,
+			ExitCode:        -1,
+			DurationSeconds: timeout.Seconds(),
+			TimedOut:        true,
+			Error:           "timeout",
+		}
+		if err := saveRunPodResult(c, output, runPodCollector.Name, result); err != nil {
+			return nil, err
+		}
+		return output, nil
+	})
+}
+
+// runWithTimeout returns the result of run, or that of timedOut when run does not finish within
+// timeout. run is canceled and waited for before timedOut is called, so that it cannot save its
+// result over the one that records the timeout.
+func runWithTimeout(ctx context.Context, timeout time.Duration, run func(ctx context.Context) (CollectorResult, error), timedOut func() (CollectorResult, error)) (CollectorResult, error) {
 	errCh := make(chan error, 1)
 	resultCh := make(chan CollectorResult, 1)
 
@@ -100,7 +172,7 @@ func RunPod(c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (Collecto
 	defer cancel()
 
 	go func() {
-		b, err := runWithoutTimeout(timeoutCtx, c, pod, runPodCollector)
+		b, err := run(timeoutCtx)
 		if err != nil {
 			errCh <- err
 		} else {
@@ -110,7 +182,12 @@ func RunPod(c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (Collecto
 
 	select {
 	case <-time.After(timeout):
-		return nil, errors.New("timeout")
+		cancel()
+		select {
+		case <-resultCh:
+		case <-errCh:
+		}
+		return timedOut()
 	case result := <-resultCh:
 		return result, nil
 	case err := <-errCh:
@@ -118,7 +195,7 @@ func RunPod(c *Collector, runPodCollector *troubleshootv1beta2.RunPod) (Collecto
 	}
 }
 
-func runPodWithSpec(ctx context.Context, client *kubernetes.Clientset, runPodCollector *troubleshootv1beta2.RunPod) (*corev1.Pod, error) {
+func runPodWithSpec(ctx context.Context, client kubernetes.Interface, runPodCollector *troubleshootv1beta2.RunPod) (*corev1.Pod, error) {
 	podLabels := make(map[string]string)
 	podLabels["troubleshoot-role"] = "run-collector"
 
@@ -205,9 +282,88 @@ func runWithoutTimeout(ctx context.Context, c *Collector, pod *corev1.Pod, runPo
 		output[k] = v
 	}
 
+	// the log stream ends when the container exits, but its status may not have been updated yet
+	result := waitForRunPodResult(ctx, client, pod)
+	for k := range podLogs {
+		if strings.HasSuffix(k, ".log") {
+			result.StdoutFile = k
+		}
+	}
+	if err := saveRunPodResult(c, output, collectorName, result); err != nil {
+		return nil, err
+	}
+
 	return output, nil
 }
 
+func waitForRunPodResult(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) ExecResult {
+	failed := func(message string) ExecResult {
+		return ExecResult{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: pod.Spec.Containers[0].Name,
+			ExitCode:  -1,
+			Error:     message,
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		status, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return failed(errors.Wrap(err, "failed to get pod").Error())
+		}
+		if result, ok := getRunPodResult(status); ok {
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			return failed(ctx.Err().Error())
+		case <-time.After(time.Second):
+		}
+	}
+
+	return failed("container did not terminate")
+}
+
+// getRunPodResult returns the result of the pod's first container, which is the one whose logs are
+// collected, once it has terminated.
+func getRunPodResult(pod *corev1.Pod) (ExecResult, bool) {
+	container := pod.Spec.Containers[0].Name
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != container || status.State.Terminated == nil {
+			continue
+		}
+
+		terminated := status.State.Terminated
+		result := ExecResult{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: container,
+			ExitCode:  int(terminated.ExitCode),
+			Signal:    int(terminated.Signal),
+		}
+		if !terminated.StartedAt.IsZero() && !terminated.FinishedAt.IsZero() {
+			result.DurationSeconds = terminated.FinishedAt.Sub(terminated.StartedAt.Time).Seconds()
+		}
+		if result.Signal == 0 && result.ExitCode > 128 {
+			// container runtimes report processes that were killed by a signal as 128 + the signal number
+			result.Signal = result.ExitCode - 128
+		}
+		return result, true
+	}
+
+	return ExecResult{}, false
+}
+
+func saveRunPodResult(c *Collector, output CollectorResult, collectorName string, result ExecResult) error {
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal result")
+	}
+	return output.SaveResult(c.BundlePath, filepath.Join(collectorName, result.Pod+"-result.json"), bytes.NewBuffer(b))
+}
+
 func createSecret(ctx context.Context, client kubernetes.Interface, namespace string, imagePullSecret *troubleshootv1beta2.ImagePullSecrets) (string, error) {
 	if imagePullSecret.Data == nil {
 		return "", nil
@@ -0,0 +1,130 @@
+package collect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func Test_getExecExitCode(t *testing.T) {
+	assert.Equal(t, 3, getExecExitCode(utilexec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3}))
+	assert.Equal(t, -1, getExecExitCode(errors.New("unable to upgrade connection: container not found")))
+}
+
+func Test_waitForRunPodResult(t *testing.T) {
+	started := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   ExecResult
+	}{
+		{
+			name: "exit code",
+			status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "collector",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode:   2,
+								StartedAt:  metav1.NewTime(started),
+								FinishedAt: metav1.NewTime(started.Add(1500 * time.Millisecond)),
+							},
+						},
+					},
+				},
+			},
+			want: ExecResult{
+				Namespace:       "default",
+				Pod:             "diagnostics",
+				Container:       "collector",
+				ExitCode:        2,
+				DurationSeconds: 1.5,
+			},
+		},
+		{
+			name: "killed by a signal",
+			status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "collector",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode:   137,
+								StartedAt:  metav1.NewTime(started),
+								FinishedAt: metav1.NewTime(started.Add(time.Minute)),
+							},
+						},
+					},
+				},
+			},
+			want: ExecResult{
+				Namespace:       "default",
+				Pod:             "diagnostics",
+				Container:       "collector",
+				ExitCode:        137,
+				Signal:          9,
+				DurationSeconds: 60,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "diagnostics", Namespace: "default"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "collector"}},
+				},
+				Status: test.status,
+			}
+			client := testclient.NewSimpleClientset(pod)
+
+			got := waitForRunPodResult(context.Background(), client, pod)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_runWithTimeout(t *testing.T) {
+	saveResult := func(output CollectorResult, result string) CollectorResult {
+		output["run/result.json"] = []byte(result)
+		return output
+	}
+
+	t.Run("finished", func(t *testing.T) {
+		got, err := runWithTimeout(context.Background(), time.Minute, func(ctx context.Context) (CollectorResult, error) {
+			return saveResult(NewResult(), "done"), nil
+		}, func() (CollectorResult, error) {
+			return saveResult(NewResult(), "timeout"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "done", string(got["run/result.json"]))
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		// the run saves its result to the same output after it is canceled, as the pod collector
+		// does on disk, and that must not replace the result that records the timeout
+		output := NewResult()
+		got, err := runWithTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) (CollectorResult, error) {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return saveResult(output, "canceled"), nil
+		}, func() (CollectorResult, error) {
+			return saveResult(output, "timeout"), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "timeout", string(got["run/result.json"]))
+		assert.Equal(t, "timeout", string(output["run/result.json"]))
+	})
+}
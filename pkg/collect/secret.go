@@ -10,12 +10,36 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectSecret struct {
+	collector *troubleshootv1beta2.Secret
+	c         *Collector
+}
+
+func (c *CollectSecret) Title() string {
+	return clusterCollectorTitle("secret", c.collector.CollectorName, c.collector.Selector)
+}
+
+func (c *CollectSecret) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectSecret) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return []authorizationv1.SelfSubjectAccessReviewSpec{
+		resourceAccessReviewSpec(pickNamespaceOrDefault(c.collector.Namespace, namespace), "get", "", "secrets", "", c.collector.Name),
+	}
+}
+
+func (c *CollectSecret) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	return Secret(ctx, c.c, c.collector, client)
+}
+
 type SecretOutput struct {
 	Namespace    string `json:"namespace"`
 	Name         string `json:"name"`
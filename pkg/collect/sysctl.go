@@ -8,12 +8,43 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+type CollectSysctl struct {
+	collector *troubleshootv1beta2.Sysctl
+	c         *Collector
+}
+
+func (c *CollectSysctl) Title() string {
+	return clusterCollectorTitle("sysctl", c.collector.Name, nil)
+}
+
+func (c *CollectSysctl) IsExcluded() (bool, error) {
+	return isExcluded(c.collector.Exclude)
+}
+
+func (c *CollectSysctl) AccessReviewSpecs(namespace string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	return nil
+}
+
+func (c *CollectSysctl) Collect(ctx context.Context, client kubernetes.Interface) (CollectorResult, error) {
+	if c.collector.Namespace == "" {
+		c.collector.Namespace = c.c.Namespace
+	}
+	if c.collector.Namespace == "" {
+		kubeconfig := k8sutil.GetKubeconfig()
+		namespace, _, _ := kubeconfig.Namespace()
+		c.collector.Namespace = namespace
+	}
+	return Sysctl(ctx, c.c, client, c.collector)
+}
+
 func Sysctl(ctx context.Context, c *Collector, client kubernetes.Interface, collector *troubleshootv1beta2.Sysctl) (CollectorResult, error) {
 
 	if collector.Timeout != "" {
@@ -20,6 +20,10 @@ const (
 	SeverityWarn  Severity = "warn"
 	SeverityInfo  Severity = "info"
 	SeverityDebug Severity = "debug"
+
+	// SeverityAnalyzerError is the severity of analyzers that could not run, which is not the
+	// outcome of a check
+	SeverityAnalyzerError Severity = "analyzerError"
 )
 
 type Severity string
@@ -105,6 +109,14 @@ func FromAnalyzerResult(input []*analyze.AnalyzeResult) []*Result {
 		} else if i.IsWarn {
 			r.Severity = SeverityWarn
 			r.Insight.Severity = SeverityWarn
+		} else if i.IsError {
+			// the analyzer could not run, which should not be mistaken for a failed check
+			r.Severity = SeverityAnalyzerError
+			r.Insight.Severity = SeverityAnalyzerError
+			r.Error = i.Error
+		} else if i.IsSkipped {
+			r.Severity = SeverityInfo
+			r.Insight.Severity = SeverityInfo
 		} else if i.IsPass {
 			r.Severity = SeverityDebug
 			r.Insight.Severity = SeverityDebug
@@ -0,0 +1,56 @@
+package convert
+
+import (
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromAnalyzerResultSeverity(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    *analyze.AnalyzeResult
+		want      Severity
+		wantError string
+	}{
+		{
+			name:      "fail",
+			result:    &analyze.AnalyzeResult{Title: "Node Count", IsFail: true, Message: "At least 3 nodes are required"},
+			want:      SeverityError,
+			wantError: "At least 3 nodes are required",
+		},
+		{
+			name:   "warn",
+			result: &analyze.AnalyzeResult{Title: "Node Count", IsWarn: true, Message: "At least 5 nodes are recommended"},
+			want:   SeverityWarn,
+		},
+		{
+			name:      "analyzer error",
+			result:    &analyze.AnalyzeResult{Title: "Node Count", IsError: true, Error: "failed to read nodes.json"},
+			want:      SeverityAnalyzerError,
+			wantError: "failed to read nodes.json",
+		},
+		{
+			name:   "skipped",
+			result: &analyze.AnalyzeResult{Title: "Node Count", IsSkipped: true},
+			want:   SeverityInfo,
+		},
+		{
+			name:   "pass",
+			result: &analyze.AnalyzeResult{Title: "Node Count", IsPass: true},
+			want:   SeverityDebug,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := FromAnalyzerResult([]*analyze.AnalyzeResult{tt.result})
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want, results[0].Severity)
+			assert.Equal(t, tt.want, results[0].Insight.Severity)
+			assert.Equal(t, tt.wantError, results[0].Error)
+		})
+	}
+}
@@ -0,0 +1,721 @@
+// Package expression implements the expression language shared by analyzer outcome `when` clauses.
+//
+// An expression compares variables and literals and combines the comparisons with boolean logic:
+//
+//	cpu.logical >= 4 && memory.total >= 16Gi
+//	!(os.platform == "ubuntu") || os.platformVersion == "20.04"
+//
+// Supported operators are ==, = and === (equal), !=, <, <=, >, >=, && (and), || (or) and ! (not),
+// with parentheses for grouping. Numbers may carry a Kubernetes quantity suffix such as Gi, M or m.
+// A trailing % is ignored, so analyzers that expose percentages can be compared against e.g. 90%.
+// Numbers are compared numerically, but strings that look like versions, such as "18.10" or
+// "5.4.0-42-generic", are compared as versions, even to a number, so that "18.10" > 18.9. Other
+// strings that parse as a quantity are compared numerically.
+//
+// Lists of values, such as the results of a JSONPath query, can be compared with the contains, in,
+// matches, any and all operators:
+//
+//	capabilities contains "NET_ADMIN" && phase in ["Pending", "Failed"]
+//	all versions >= 1.22.0 && any messages matches "^error"
+//
+// contains checks for a substring of a string, an element of a list or a key of a map, and matches
+// checks a string against a regular expression. A comparison prefixed with any or all is done for
+// each element of the list on its left, and does not hold for an empty list.
+package expression
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Resolver returns the value of the variable with the given dotted name, e.g. "cpu.logical".
+// The second return value is false if the variable is not defined.
+type Resolver func(name string) (interface{}, bool, error)
+
+// UndefinedVariableError is returned when an expression refers to a variable that the resolver
+// does not define.
+type UndefinedVariableError struct {
+	Name string
+}
+
+func (e UndefinedVariableError) Error() string {
+	return fmt.Sprintf("undefined variable %s", e.Name)
+}
+
+// IsUndefinedVariable reports whether err was caused by an expression referring to an undefined
+// variable.
+func IsUndefinedVariable(err error) bool {
+	_, ok := errors.Cause(err).(UndefinedVariableError)
+	return ok
+}
+
+// IsExpression reports whether when uses syntax that only the expression language understands,
+// i.e. boolean operators, including the and, or and not keywords, or parentheses. Analyzers use
+// this to decide whether to bypass their own `when` parsers.
+func IsExpression(when string) bool {
+	when = strings.TrimSpace(when)
+	if strings.Contains(when, "&&") ||
+		strings.Contains(when, "||") ||
+		(strings.HasPrefix(when, "!") && !strings.HasPrefix(when, "!=")) ||
+		strings.HasPrefix(when, "(") ||
+		strings.HasPrefix(when, "not ") {
+		return true
+	}
+
+	tokens, err := tokenize(when)
+	if err != nil {
+		return false
+	}
+	for i, t := range tokens {
+		// "and" and "or" are keywords between two operands, anywhere else they are plain words
+		if t.kind == tokenIdent && (t.text == "and" || t.text == "or") && i > 0 && i < len(tokens)-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate parses and evaluates expr, resolving variables with resolve.
+func Evaluate(expr string, resolve Resolver) (bool, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", expr)
+	}
+
+	p := &parser{tokens: tokens, resolve: resolve}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate %q", expr)
+	}
+	if !p.done() {
+		return false, errors.Errorf("failed to parse %q: unexpected %q", expr, p.peek().text)
+	}
+
+	b, err := toBool(result)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate %q", expr)
+	}
+	return b, nil
+}
+
+// MapResolver resolves dotted variable names by walking nested maps. Elements of lists are selected
+// by their index, e.g. "checks.0.status".
+func MapResolver(vars map[string]interface{}) Resolver {
+	return func(name string) (interface{}, bool, error) {
+		var current interface{} = vars
+		for _, part := range strings.Split(name, ".") {
+			switch c := current.(type) {
+			case map[string]interface{}:
+				value, ok := c[part]
+				if !ok {
+					return nil, false, nil
+				}
+				current = value
+			case []interface{}:
+				index, err := strconv.Atoi(part)
+				if err != nil || index < 0 || index >= len(c) {
+					return nil, false, nil
+				}
+				current = c[index]
+			default:
+				return nil, false, nil
+			}
+		}
+		return current, true, nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end])})
+			i = end + 1
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) && expectsOperand(tokens)):
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == '%') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:end])})
+			i = end
+
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.' || runes[end] == '-' || runes[end] == '/') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[i:end])})
+			i = end
+
+		default:
+			op := ""
+			for _, candidate := range []string{"===", "==", "!=", "<=", ">=", "&&", "||", "=", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// expectsOperand reports whether the next token must be an operand, which is how a leading "-"
+// is told apart from an operator.
+func expectsOperand(tokens []token) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind == tokenOperator && last.text != ")" && last.text != "]"
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	resolve Resolver
+	// skip is non-zero while parsing operands whose value can't change the result
+	skip int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is one of the given operators or keywords.
+func (p *parser) accept(ops ...string) (string, bool) {
+	if p.done() {
+		return "", false
+	}
+	t := p.tokens[p.pos]
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		l, err := p.toBool(left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseShortCircuit(l, p.parseAnd)
+		if err != nil {
+			return nil, err
+		}
+		if l {
+			left = l
+			continue
+		}
+		r, err := p.toBool(right)
+		if err != nil {
+			return nil, err
+		}
+		left = l || r
+	}
+}
+
+func (p *parser) parseAnd() (interface{}, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		l, err := p.toBool(left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseShortCircuit(!l, p.parseNot)
+		if err != nil {
+			return nil, err
+		}
+		if !l {
+			left = l
+			continue
+		}
+		r, err := p.toBool(right)
+		if err != nil {
+			return nil, err
+		}
+		left = l && r
+	}
+}
+
+// parseShortCircuit parses the right hand side of a boolean operator. If the left hand side
+// already decided the result, the right hand side is only parsed, not evaluated, so that e.g.
+// "count > 0 && age < 10" does not fail when age is undefined.
+func (p *parser) parseShortCircuit(decided bool, parse func() (interface{}, error)) (interface{}, error) {
+	if !decided {
+		return parse()
+	}
+	p.skip++
+	defer func() { p.skip-- }()
+	return parse()
+}
+
+func (p *parser) parseNot() (interface{}, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		value, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		b, err := p.toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return !b, nil
+	}
+	return p.parseComparison()
+}
+
+// toBool converts value to a boolean, unless the value is not being evaluated.
+func (p *parser) toBool(value interface{}) (bool, error) {
+	if p.skip > 0 {
+		return false, nil
+	}
+	return toBool(value)
+}
+
+func (p *parser) parseComparison() (interface{}, error) {
+	quantifier := ""
+	if p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].kind != tokenOperator {
+		// any and all are only quantifiers when followed by an operand, otherwise they're variables
+		quantifier, _ = p.accept("any", "all")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.accept("===", "==", "=", "!=", "<=", ">=", "<", ">", "contains", "in", "matches")
+	if !ok {
+		if quantifier != "" {
+			return nil, errors.Errorf("%s requires a comparison", quantifier)
+		}
+		return left, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip > 0 {
+		return false, nil
+	}
+	if quantifier == "" {
+		return compare(left, op, right)
+	}
+
+	items, ok := left.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("%s requires a list, not %s", quantifier, toString(left))
+	}
+	for _, item := range items {
+		isMatch, err := compare(item, op, right)
+		if err != nil {
+			return nil, err
+		}
+		if quantifier == "any" && isMatch {
+			return true, nil
+		}
+		if quantifier == "all" && !isMatch {
+			return false, nil
+		}
+	}
+	return quantifier == "all" && len(items) > 0, nil
+}
+
+func (p *parser) parseOperand() (interface{}, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	if _, ok := p.accept("("); ok {
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		return value, nil
+	}
+
+	if _, ok := p.accept("["); ok {
+		items := []interface{}{}
+		if _, ok := p.accept("]"); ok {
+			return items, nil
+		}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if _, ok := p.accept("]"); ok {
+				return items, nil
+			}
+			if _, ok := p.accept(","); !ok {
+				return nil, errors.New("missing closing bracket")
+			}
+		}
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenNumber:
+		q, err := resource.ParseQuantity(strings.TrimSuffix(t.text, "%"))
+		if err != nil {
+			// not a number or quantity, e.g. a version like 1.16.0
+			return t.text, nil
+		}
+		return numberLiteral{text: t.text, quantity: q}, nil
+	case tokenString:
+		return t.text, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if p.skip > 0 {
+			return nil, nil
+		}
+		value, ok, err := p.resolve(t.text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve %s", t.text)
+		}
+		if !ok {
+			return nil, UndefinedVariableError{Name: t.text}
+		}
+		return value, nil
+	}
+
+	return nil, errors.Errorf("unexpected %q", t.text)
+}
+
+// numberLiteral is a number in an expression. The text is kept for comparisons with versions, which
+// the quantity would lose, e.g. 18.10 is the quantity 18.1.
+type numberLiteral struct {
+	text     string
+	quantity resource.Quantity
+}
+
+func compare(left interface{}, op string, right interface{}) (bool, error) {
+	switch op {
+	case "contains":
+		return contains(left, right)
+	case "in":
+		items, ok := right.([]interface{})
+		if !ok {
+			return false, errors.Errorf("in requires a list, not %s", toString(right))
+		}
+		for _, item := range items {
+			if isEqual, err := compare(left, "==", item); err == nil && isEqual {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "matches":
+		re, err := regexp.Compile(toString(right))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to compile regex %s", toString(right))
+		}
+		return re.MatchString(toString(left)), nil
+	}
+
+	if l, ok := toNumber(left); ok {
+		if r, ok := toNumber(right); ok {
+			return compareOrdered(l, op, r)
+		}
+	}
+
+	_, leftIsString := left.(string)
+	_, rightIsString := right.(string)
+	if leftIsString || rightIsString {
+		l, r := toString(left), toString(right)
+		if isVersion(l) && isVersion(r) {
+			return compareOrdered(compareVersions(l, r), op, 0)
+		}
+		if l, ok := parseQuantity(l); ok {
+			if r, ok := parseQuantity(r); ok {
+				return compareOrdered(l, op, r)
+			}
+		}
+	}
+
+	if l, ok := left.(bool); ok {
+		r, err := toBool(right)
+		if err != nil {
+			return false, err
+		}
+		switch op {
+		case "=", "==", "===":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+		return false, errors.Errorf("operator %s is not supported for booleans", op)
+	}
+
+	l, r := toString(left), toString(right)
+	switch op {
+	case "=", "==", "===":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+	return false, errors.Errorf("operator %s is not supported for %q and %q", op, l, r)
+}
+
+// contains reports whether a string contains a substring, a list contains an element or a map
+// contains a key.
+func contains(container interface{}, value interface{}) (bool, error) {
+	switch c := container.(type) {
+	case string:
+		return strings.Contains(c, toString(value)), nil
+	case []interface{}:
+		for _, item := range c {
+			if isEqual, err := compare(item, "==", value); err == nil && isEqual {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		_, ok := c[toString(value)]
+		return ok, nil
+	case map[interface{}]interface{}:
+		_, ok := c[toString(value)]
+		return ok, nil
+	case nil:
+		return false, nil
+	}
+	return false, errors.Errorf("contains is not supported for %s", toString(container))
+}
+
+func compareOrdered(l float64, op string, r float64) (bool, error) {
+	switch op {
+	case "=", "==", "===":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	}
+	return false, errors.Errorf("unknown operator %s", op)
+}
+
+// toNumber converts numbers and quantities to a float. Strings are not numbers, even if they look
+// like one, see compare.
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case numberLiteral:
+		return v.quantity.AsApproximateFloat64(), true
+	case resource.Quantity:
+		return v.AsApproximateFloat64(), true
+	case *resource.Quantity:
+		return v.AsApproximateFloat64(), true
+	case string:
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, errors.Errorf("%q is not a boolean", v)
+		}
+		return b, nil
+	}
+	return false, errors.Errorf("%s is not a boolean", toString(value))
+}
+
+func parseQuantity(s string) (float64, bool) {
+	q, err := resource.ParseQuantity(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	if err != nil {
+		return 0, false
+	}
+	return q.AsApproximateFloat64(), true
+}
+
+var versionRegexp = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+)*([-+~_][0-9A-Za-z.\-+~_]*)?$`)
+
+// isVersion reports whether s is a number or a version, such as 18.04, v1.24.3 or 5.4.0-42-generic
+func isVersion(s string) bool {
+	return versionRegexp.MatchString(strings.TrimSpace(s))
+}
+
+// compareVersions returns -1, 0 or 1 if version a is lower than, equal to or higher than b. Versions
+// are compared part by part, numeric parts numerically, so 1.10 > 1.9 and 18.10 != 18.1. Missing
+// numeric parts are 0, so 1.16 == 1.16.0.
+func compareVersions(a string, b string) float64 {
+	aParts, bParts := versionParts(a), versionParts(b)
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		} else if !isDigits(bParts[i]) {
+			return 1
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		} else if !isDigits(aParts[i]) {
+			return -1
+		}
+
+		aNum, aErr := strconv.ParseUint(aPart, 10, 64)
+		bNum, bErr := strconv.ParseUint(bPart, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			// numbers come before words, e.g. 1.0.1 > 1.0-rc1
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aPart, bPart); c != 0 {
+				return float64(c)
+			}
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version into its runs of digits and of letters, e.g. 5.4.0-42-generic is
+// 5, 4, 0, 42 and generic
+func versionParts(version string) []string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	parts := []string{}
+	current := []rune{}
+	for _, r := range version {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				parts = append(parts, string(current))
+			}
+			current = current[:0]
+			continue
+		}
+		if len(current) > 0 && unicode.IsDigit(r) != unicode.IsDigit(current[0]) {
+			parts = append(parts, string(current))
+			current = current[:0]
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
+	return parts
+}
+
+func isDigits(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case numberLiteral:
+		return v.text
+	case string:
+		return v
+	case resource.Quantity:
+		return v.String()
+	case *resource.Quantity:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,271 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]interface{}{
+		"cpu": map[string]interface{}{
+			"logical":  8,
+			"physical": 4,
+		},
+		"memory": map[string]interface{}{
+			"total": uint64(17179869184),
+		},
+		"os": map[string]interface{}{
+			"platform":        "ubuntu",
+			"platformVersion": "20.04",
+			"kernelVersion":   "5.4.0-42-generic",
+		},
+		"version": "18.10",
+		"ready":   "3",
+		"requests": map[string]interface{}{
+			"cpu": 92.5,
+		},
+		"count/deployments": map[string]interface{}{
+			"apps": 10,
+		},
+		"enabled":  true,
+		"replicas": []interface{}{float64(3), float64(5)},
+		"pod": map[string]interface{}{
+			"phase":        "Running",
+			"capabilities": []interface{}{"NET_ADMIN", "SYS_TIME"},
+			"message":      "connection error: timeout",
+			"images":       []interface{}{"v1.24.3", "v1.21.0"},
+		},
+		"secret": map[string]interface{}{
+			"ca.crt":  "",
+			"tls.key": "",
+		},
+		"empty": []interface{}{},
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "single comparison",
+			expr: "cpu.logical >= 4",
+			want: true,
+		},
+		{
+			name: "and with quantity",
+			expr: "cpu.logical >= 4 && memory.total >= 16Gi",
+			want: true,
+		},
+		{
+			name: "and with quantity, not enough memory",
+			expr: "cpu.logical >= 4 && memory.total > 16Gi",
+			want: false,
+		},
+		{
+			name: "or",
+			expr: "cpu.physical > 4 || cpu.logical > 4",
+			want: true,
+		},
+		{
+			name: "keywords",
+			expr: "not (cpu.physical > 4) and memory.total == 16Gi",
+			want: true,
+		},
+		{
+			name: "string equality",
+			expr: `os.platform == "ubuntu" && os.platformVersion != '18.04'`,
+			want: true,
+		},
+		{
+			name: "numeric string",
+			expr: "ready = 3",
+			want: true,
+		},
+		{
+			name: "negative number",
+			expr: "ready > -1",
+			want: true,
+		},
+		{
+			name: "version is not a decimal",
+			expr: "version == 18.1",
+			want: false,
+		},
+		{
+			name: "version minor compared numerically",
+			expr: `version > 18.9 && "1.10" > "1.9"`,
+			want: true,
+		},
+		{
+			name: "version with leading zero",
+			expr: "os.platformVersion >= 18.04 && os.platformVersion < 22.04",
+			want: true,
+		},
+		{
+			name: "missing version parts are zero",
+			expr: `"1.16" == "1.16.0" && "v1.24.3" > 1.24`,
+			want: true,
+		},
+		{
+			name: "kernel version",
+			expr: `os.kernelVersion >= "5.4.0" && os.kernelVersion < "5.4.0-100-generic"`,
+			want: true,
+		},
+		{
+			name: "contains substring",
+			expr: `pod.message contains "error"`,
+			want: true,
+		},
+		{
+			name: "contains list element",
+			expr: `pod.capabilities contains "NET_ADMIN"`,
+			want: true,
+		},
+		{
+			name: "contains map key",
+			expr: `secret contains "tls.crt"`,
+			want: false,
+		},
+		{
+			name: "in list",
+			expr: `pod.phase in ["Pending", "Failed"] || requests.cpu in [92.5, 100]`,
+			want: true,
+		},
+		{
+			name: "matches regex",
+			expr: `all pod.images matches "^v1\.2[0-9]\."`,
+			want: true,
+		},
+		{
+			name: "all elements",
+			expr: "all replicas > 3",
+			want: false,
+		},
+		{
+			name: "any element",
+			expr: "any replicas > 3 && all replicas >= 3",
+			want: true,
+		},
+		{
+			name: "list index",
+			expr: "replicas.1 == 5",
+			want: true,
+		},
+		{
+			name: "any of nothing",
+			expr: "any empty == 0",
+			want: false,
+		},
+		{
+			name:    "quantifier of a value",
+			expr:    "any ready == 3",
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			expr:    `pod.phase matches "["`,
+			wantErr: true,
+		},
+		{
+			name:    "unterminated list",
+			expr:    `pod.phase in ["Pending"`,
+			wantErr: true,
+		},
+		{
+			name: "bare boolean",
+			expr: "!enabled",
+			want: false,
+		},
+		{
+			name: "precedence",
+			expr: "cpu.logical < 4 && cpu.physical < 4 || enabled",
+			want: true,
+		},
+		{
+			name: "percentage",
+			expr: "requests.cpu > 90% && count/deployments.apps <= 100%",
+			want: true,
+		},
+		{
+			name: "short circuit and",
+			expr: "cpu.logical > 16 && cpu.sockets > 1",
+			want: false,
+		},
+		{
+			name: "short circuit or",
+			expr: "enabled || cpu.sockets > 1",
+			want: true,
+		},
+		{
+			name:    "undefined variable",
+			expr:    "cpu.sockets > 1",
+			wantErr: true,
+		},
+		{
+			name:    "ordering strings",
+			expr:    `os.platform > "centos"`,
+			wantErr: true,
+		},
+		{
+			name:    "not a boolean",
+			expr:    "cpu.logical",
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced parentheses",
+			expr:    "(cpu.logical > 4",
+			wantErr: true,
+		},
+		{
+			name:    "trailing tokens",
+			expr:    "cpu.logical > 4 4",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			got, err := Evaluate(test.expr, MapResolver(vars))
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	assert.True(t, IsExpression("a > 1 && b < 2"))
+	assert.True(t, IsExpression("a > 1 || b < 2"))
+	assert.True(t, IsExpression("!enabled"))
+	assert.True(t, IsExpression("(a > 1)"))
+	assert.True(t, IsExpression("a > 1 and b < 2"))
+	assert.True(t, IsExpression("a > 1 or b < 2"))
+	assert.False(t, IsExpression("== and"))
+	assert.False(t, IsExpression(">= 1.16.0"))
+	assert.False(t, IsExpression("!= Bound"))
+	assert.False(t, IsExpression("logical > 4"))
+	assert.False(t, IsExpression("cpu.logical > 4"))
+}
+
+func TestIsUndefinedVariable(t *testing.T) {
+	_, err := Evaluate("cpu.logical > 4 && cpu.sockets > 1", MapResolver(map[string]interface{}{
+		"cpu": map[string]interface{}{"logical": 8},
+	}))
+	require.Error(t, err)
+	assert.True(t, IsUndefinedVariable(err))
+	assert.Contains(t, err.Error(), "undefined variable cpu.sockets")
+
+	_, err = Evaluate("enabled > 4", MapResolver(map[string]interface{}{"enabled": true}))
+	require.Error(t, err)
+	assert.False(t, IsUndefinedVariable(err))
+}
@@ -8,17 +8,16 @@ import (
 
 	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
-	"github.com/replicatedhq/troubleshoot/pkg/logger"
 )
 
 // Analyze runs the analyze phase of preflight checks
 func (c ClusterCollectResult) Analyze() []*analyze.AnalyzeResult {
-	return doAnalyze(c.AllCollectedData, c.Spec.Spec.Analyzers, nil, "")
+	return doAnalyze(c.AllCollectedData, c.Spec.Spec.Analyzers, nil, "", c.AnalyzerConcurrency)
 }
 
 // Analyze runs the analysze phase of host preflight checks
 func (c HostCollectResult) Analyze() []*analyze.AnalyzeResult {
-	return doAnalyze(c.AllCollectedData, nil, c.Spec.Spec.Analyzers, "")
+	return doAnalyze(c.AllCollectedData, nil, c.Spec.Spec.Analyzers, "", 0)
 }
 
 // Analyze runs the analysze phase of host preflight checks.
@@ -30,9 +29,10 @@ func (c RemoteCollectResult) Analyze() []*analyze.AnalyzeResult {
 		var strResult = make(map[string]string)
 		if err := json.Unmarshal(nodeResult, &strResult); err != nil {
 			analyzeResult := &analyze.AnalyzeResult{
-				IsFail:  true,
+				IsError: true,
 				Title:   "Remote Result Parser Failed",
 				Message: err.Error(),
+				Error:   err.Error(),
 			}
 			results = append(results, analyzeResult)
 			continue
@@ -43,12 +43,12 @@ func (c RemoteCollectResult) Analyze() []*analyze.AnalyzeResult {
 			byteResult[k] = []byte(v)
 
 		}
-		results = append(results, doAnalyze(byteResult, nil, c.Spec.Spec.Analyzers, nodeName)...)
+		results = append(results, doAnalyze(byteResult, nil, c.Spec.Spec.Analyzers, nodeName, 0)...)
 	}
 	return results
 }
 
-func doAnalyze(allCollectedData map[string][]byte, analyzers []*troubleshootv1beta2.Analyze, hostAnalyzers []*troubleshootv1beta2.HostAnalyze, nodeName string) []*analyze.AnalyzeResult {
+func doAnalyze(allCollectedData map[string][]byte, analyzers []*troubleshootv1beta2.Analyze, hostAnalyzers []*troubleshootv1beta2.HostAnalyze, nodeName string, concurrency int) []*analyze.AnalyzeResult {
 	getCollectedFileContents := func(fileName string) ([]byte, error) {
 		contents, ok := allCollectedData[fileName]
 		if !ok {
@@ -74,23 +74,13 @@ func doAnalyze(allCollectedData map[string][]byte, analyzers []*troubleshootv1be
 		return matching, nil
 	}
 
+	results, errs := analyze.AnalyzeAll(analyzers, getCollectedFileContents, getChildCollectedFileContents, analyze.AnalyzerConcurrencyOrDefault(concurrency))
+
 	analyzeResults := []*analyze.AnalyzeResult{}
-	for _, analyzer := range analyzers {
-		analyzeResult, err := analyze.Analyze(analyzer, getCollectedFileContents, getChildCollectedFileContents)
+	for i, analyzer := range analyzers {
+		analyzeResult, err := results[i], errs[i]
 		if err != nil {
-			strict, strictErr := HasStrictAnalyzer(analyzer)
-			if strictErr != nil {
-				logger.Printf("failed to determine if analyzer %v is strict: %s", analyzer, strictErr)
-			}
-
-			analyzeResult = []*analyze.AnalyzeResult{
-				{
-					Strict:  strict,
-					IsFail:  true,
-					Title:   "Analyzer Failed",
-					Message: err.Error(),
-				},
-			}
+			analyzeResult = analyze.NewAnalyzerErrorResult(analyzer, err)
 		}
 
 		if analyzeResult != nil {
@@ -98,8 +88,7 @@ func doAnalyze(allCollectedData map[string][]byte, analyzers []*troubleshootv1be
 		}
 	}
 
-	for _, hostAnalyzer := range hostAnalyzers {
-		analyzeResult := analyze.HostAnalyze(hostAnalyzer, getCollectedFileContents, getChildCollectedFileContents)
+	for _, analyzeResult := range analyze.HostAnalyzeAll(hostAnalyzers, getCollectedFileContents, getChildCollectedFileContents) {
 		analyzeResults = append(analyzeResults, analyzeResult...)
 	}
 
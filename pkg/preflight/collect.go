@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -23,6 +24,12 @@ type CollectOpts struct {
 	LabelSelector          string
 	Timeout                time.Duration
 	ProgressChan           chan interface{}
+	// Concurrency is the maximum number of in-cluster collectors run at the same time. Values less than 1 run them one at a time.
+	Concurrency int
+	// CollectorTimeout bounds how long each in-cluster collector is allowed to run. Zero means no limit.
+	CollectorTimeout time.Duration
+	// AnalyzerConcurrency is the maximum number of analyzers run at the same time. Zero uses the number of CPUs.
+	AnalyzerConcurrency int
 }
 
 type CollectProgress struct {
@@ -47,11 +54,12 @@ type CollectResult interface {
 }
 
 type ClusterCollectResult struct {
-	AllCollectedData map[string][]byte
-	Collectors       collect.Collectors
-	RemoteCollectors collect.RemoteCollectors
-	isRBACAllowed    bool
-	Spec             *troubleshootv1beta2.Preflight
+	AllCollectedData    map[string][]byte
+	Collectors          collect.Collectors
+	RemoteCollectors    collect.RemoteCollectors
+	isRBACAllowed       bool
+	Spec                *troubleshootv1beta2.Preflight
+	AnalyzerConcurrency int
 }
 
 func (cr ClusterCollectResult) IsRBACAllowed() bool {
@@ -135,13 +143,15 @@ func Collect(opts CollectOpts, p *troubleshootv1beta2.Preflight) (CollectResult,
 			Collect:      desiredCollector,
 			ClientConfig: opts.KubernetesRestConfig,
 			Namespace:    opts.Namespace,
+			Timeout:      opts.CollectorTimeout,
 		}
 		collectors = append(collectors, &collector)
 	}
 
 	collectResult := ClusterCollectResult{
-		Collectors: collectors,
-		Spec:       p,
+		Collectors:          collectors,
+		Spec:                p,
+		AnalyzerConcurrency: opts.AnalyzerConcurrency,
 	}
 
 	k8sClient, err := kubernetes.NewForConfig(opts.KubernetesRestConfig)
@@ -174,69 +184,64 @@ func Collect(opts CollectOpts, p *troubleshootv1beta2.Preflight) (CollectResult,
 		}
 	}
 
-	// Run preflights collectors synchronously
-	for i, collector := range collectors {
+	// collectors may run concurrently, so status updates and results are guarded by a single mutex
+	var mut sync.Mutex
+	completedCount := 0
+	sendProgress := func(collector *collect.Collector, status string) {
+		collectorList[collector.GetDisplayName()] = CollectorStatus{
+			Status: status,
+		}
+		if status != "running" {
+			completedCount++
+		}
+
+		// send a copy so that the receiver does not see later updates
+		collectorListCopy := make(map[string]CollectorStatus, len(collectorList))
+		for k, v := range collectorList {
+			collectorListCopy[k] = v
+		}
+		opts.ProgressChan <- CollectProgress{
+			CurrentName:    collector.GetDisplayName(),
+			CurrentStatus:  status,
+			CompletedCount: completedCount,
+			TotalCount:     len(collectors),
+			Collectors:     collectorListCopy,
+		}
+	}
+
+	collectors.RunConcurrently(opts.Concurrency, func(collector *collect.Collector) {
 		if len(collector.RBACErrors) > 0 {
 			// don't skip clusterResources collector due to RBAC issues
 			if collector.Collect.ClusterResources == nil {
-				collectorList[collector.GetDisplayName()] = CollectorStatus{
-					Status: "skipped",
-				}
+				mut.Lock()
+				defer mut.Unlock()
 				collectResult.isRBACAllowed = false // not failing, but going to report this
 				opts.ProgressChan <- fmt.Sprintf("skipping collector %s with insufficient RBAC permissions", collector.GetDisplayName())
-				opts.ProgressChan <- CollectProgress{
-					CurrentName:    collector.GetDisplayName(),
-					CurrentStatus:  "skipped",
-					CompletedCount: i + 1,
-					TotalCount:     len(collectors),
-					Collectors:     collectorList,
-				}
-				continue
+				sendProgress(collector, "skipped")
+				return
 			}
 		}
 
-		collectorList[collector.GetDisplayName()] = CollectorStatus{
-			Status: "running",
-		}
-		opts.ProgressChan <- CollectProgress{
-			CurrentName:    collector.GetDisplayName(),
-			CurrentStatus:  "running",
-			CompletedCount: i,
-			TotalCount:     len(collectors),
-			Collectors:     collectorList,
-		}
+		mut.Lock()
+		sendProgress(collector, "running")
+		mut.Unlock()
 
 		result, err := collector.RunCollectorSync(opts.KubernetesRestConfig, k8sClient, nil)
+
+		mut.Lock()
+		defer mut.Unlock()
 		if err != nil {
-			collectorList[collector.GetDisplayName()] = CollectorStatus{
-				Status: "failed",
-			}
 			opts.ProgressChan <- errors.Errorf("failed to run collector %s: %v\n", collector.GetDisplayName(), err)
-			opts.ProgressChan <- CollectProgress{
-				CurrentName:    collector.GetDisplayName(),
-				CurrentStatus:  "failed",
-				CompletedCount: i + 1,
-				TotalCount:     len(collectors),
-				Collectors:     collectorList,
-			}
-			continue
+			sendProgress(collector, "failed")
+			return
 		}
 
-		collectorList[collector.GetDisplayName()] = CollectorStatus{
-			Status: "completed",
-		}
-		opts.ProgressChan <- CollectProgress{
-			CurrentName:    collector.GetDisplayName(),
-			CurrentStatus:  "completed",
-			CompletedCount: i + 1,
-			TotalCount:     len(collectors),
-			Collectors:     collectorList,
-		}
+		sendProgress(collector, "completed")
 
 		for k, v := range result {
 			allCollectedData[k] = v
 		}
-	}
+	})
 
 	collectResult.AllCollectedData = allCollectedData
 	return collectResult, nil
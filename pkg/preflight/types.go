@@ -1,14 +1,17 @@
 package preflight
 
 type UploadPreflightResult struct {
-	Strict bool `json:"strict,omitempty"`
-	IsFail bool `json:"isFail,omitempty"`
-	IsWarn bool `json:"isWarn,omitempty"`
-	IsPass bool `json:"isPass,omitempty"`
+	Strict    bool `json:"strict,omitempty"`
+	IsFail    bool `json:"isFail,omitempty"`
+	IsWarn    bool `json:"isWarn,omitempty"`
+	IsPass    bool `json:"isPass,omitempty"`
+	IsError   bool `json:"isError,omitempty"`
+	IsSkipped bool `json:"isSkipped,omitempty"`
 
 	Title   string `json:"title"`
 	Message string `json:"message"`
 	URI     string `json:"uri,omitempty"`
+	Error   string `json:"error,omitempty"`
 }
 
 type UploadPreflightError struct {
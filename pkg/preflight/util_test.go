@@ -349,6 +349,30 @@ func TestHasStrictAnalyzerFailed(t *testing.T) {
 				},
 			},
 			want: true,
+		}, {
+			name: "expect false when preflightResult.Results has result with strict false, IsError true",
+			preflightResult: &UploadPreflightResults{
+				Results: []*UploadPreflightResult{
+					{Strict: false, IsError: true},
+				},
+			},
+			want: false,
+		}, {
+			name: "expect true when preflightResult.Results has result with strict true, IsError true",
+			preflightResult: &UploadPreflightResults{
+				Results: []*UploadPreflightResult{
+					{Strict: true, IsError: true},
+				},
+			},
+			want: true,
+		}, {
+			name: "expect false when preflightResult.Results has result with strict true, IsSkipped true",
+			preflightResult: &UploadPreflightResults{
+				Results: []*UploadPreflightResult{
+					{Strict: true, IsSkipped: true},
+				},
+			},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
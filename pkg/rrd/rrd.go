@@ -0,0 +1,235 @@
+// Package rrd reads the round robin database files written by RRDtool, e.g. by collectd's rrdtool
+// plugin.
+//
+// RRD files are a dump of RRDtool's in-memory structures, so their layout depends on the platform
+// that wrote them. Files written on 64 bit platforms are supported, in either byte order.
+package rrd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// floatCookie is written to the header of every RRD file so that readers can tell whether the
+// file is compatible with their platform.
+const floatCookie = 8.642135e130
+
+// sizes of the structures in files written on 64 bit platforms, including padding
+const (
+	statHeadSize = 128
+	dsDefSize    = 120
+	rraDefSize   = 120
+	pdpPrepSize  = 112
+	cdpPrepSize  = 80
+)
+
+// File is a parsed RRD file.
+type File struct {
+	Version     string
+	Step        time.Duration
+	LastUpdate  time.Time
+	DataSources []DataSource
+	Archives    []Archive
+}
+
+// DataSource is a data source of an RRD file, e.g. "value" of type "GAUGE".
+type DataSource struct {
+	Name string
+	Type string
+}
+
+// Archive is a round robin archive of consolidated data points. Rows are in chronological order,
+// and have a value for every data source of the file, which is NaN when it is unknown.
+type Archive struct {
+	ConsolidationFunction string
+	Step                  time.Duration
+	Rows                  []Row
+}
+
+// Row is a consolidated data point of every data source, for the step ending at Time.
+type Row struct {
+	Time   time.Time
+	Values []float64
+}
+
+// DataSourceIndex returns the index of the named data source in rows, or -1 if there is no data
+// source with the name.
+func (f *File) DataSourceIndex(name string) int {
+	for i, ds := range f.DataSources {
+		if ds.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Parse parses the contents of an RRD file.
+func Parse(b []byte) (*File, error) {
+	if len(b) < statHeadSize || !bytes.Equal(b[:4], []byte("RRD\x00")) {
+		return nil, errors.New("not an rrd file")
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case math.Float64frombits(binary.LittleEndian.Uint64(b[16:24])) == floatCookie:
+		order = binary.LittleEndian
+	case math.Float64frombits(binary.BigEndian.Uint64(b[16:24])) == floatCookie:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("unsupported rrd file, only files written on 64 bit platforms are supported")
+	}
+
+	r := &reader{b: b, order: order}
+
+	f := &File{
+		Version: cString(b[4:9]),
+	}
+	liveHeadSize := 16
+	switch f.Version {
+	case "0001", "0002":
+		// the microseconds of the last update were added in version 0003
+		liveHeadSize = 8
+	case "0003", "0004":
+	default:
+		return nil, errors.Errorf("unsupported rrd version %q", f.Version)
+	}
+
+	r.offset = 24
+	dsCount := r.uint64()
+	rraCount := r.uint64()
+	pdpStep := r.uint64()
+	f.Step = time.Duration(pdpStep) * time.Second
+
+	// guard against allocating huge slices for corrupt files
+	if dsCount > uint64(len(b))/dsDefSize || rraCount > uint64(len(b))/rraDefSize {
+		return nil, errors.New("rrd file is truncated")
+	}
+
+	r.offset = statHeadSize
+	for i := uint64(0); i < dsCount; i++ {
+		def := r.bytes(dsDefSize)
+		if def == nil {
+			return nil, errors.New("rrd file is truncated")
+		}
+		f.DataSources = append(f.DataSources, DataSource{
+			Name: cString(def[0:20]),
+			Type: cString(def[20:40]),
+		})
+	}
+
+	type rraDef struct {
+		cf     string
+		rows   uint64
+		pdpCnt uint64
+	}
+	rraDefs := []rraDef{}
+	for i := uint64(0); i < rraCount; i++ {
+		start := r.offset
+		def := r.bytes(rraDefSize)
+		if def == nil {
+			return nil, errors.New("rrd file is truncated")
+		}
+		rraDefs = append(rraDefs, rraDef{
+			cf:     cString(def[0:20]),
+			rows:   order.Uint64(b[start+24 : start+32]),
+			pdpCnt: order.Uint64(b[start+32 : start+40]),
+		})
+	}
+
+	lastUp := r.int64()
+	if liveHeadSize == 16 {
+		lastUpUsec := r.int64()
+		f.LastUpdate = time.Unix(lastUp, lastUpUsec*1000).UTC()
+	} else {
+		f.LastUpdate = time.Unix(lastUp, 0).UTC()
+	}
+
+	r.offset += int(dsCount) * pdpPrepSize
+	r.offset += int(rraCount*dsCount) * cdpPrepSize
+
+	curRows := []uint64{}
+	for i := uint64(0); i < rraCount; i++ {
+		curRows = append(curRows, r.uint64())
+	}
+
+	for i, def := range rraDefs {
+		step := int64(pdpStep * def.pdpCnt)
+		if step <= 0 || def.rows == 0 {
+			return nil, errors.Errorf("invalid archive %d", i)
+		}
+		if curRows[i] >= def.rows {
+			return nil, errors.Errorf("invalid current row of archive %d", i)
+		}
+		if def.rows > uint64(len(b))/8 {
+			return nil, errors.New("rrd file is truncated")
+		}
+
+		data := r.bytes(int(def.rows*dsCount) * 8)
+		if data == nil {
+			return nil, errors.New("rrd file is truncated")
+		}
+
+		archive := Archive{
+			ConsolidationFunction: def.cf,
+			Step:                  time.Duration(step) * time.Second,
+		}
+
+		// the current row is the most recent one, and holds the last complete step before the last update
+		lastRowTime := lastUp - lastUp%step
+		for k := uint64(0); k < def.rows; k++ {
+			index := (curRows[i] + 1 + k) % def.rows
+			row := Row{
+				Time:   time.Unix(lastRowTime-int64(def.rows-1-k)*step, 0).UTC(),
+				Values: make([]float64, dsCount),
+			}
+			for d := uint64(0); d < dsCount; d++ {
+				offset := (index*dsCount + d) * 8
+				row.Values[d] = math.Float64frombits(order.Uint64(data[offset : offset+8]))
+			}
+			archive.Rows = append(archive.Rows, row)
+		}
+
+		f.Archives = append(f.Archives, archive)
+	}
+
+	return f, nil
+}
+
+type reader struct {
+	b      []byte
+	offset int
+	order  binary.ByteOrder
+}
+
+func (r *reader) bytes(n int) []byte {
+	if n < 0 || r.offset+n > len(r.b) {
+		r.offset = len(r.b) + 1
+		return nil
+	}
+	b := r.b[r.offset : r.offset+n]
+	r.offset += n
+	return b
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return r.order.Uint64(b)
+}
+
+func (r *reader) int64() int64 {
+	return int64(r.uint64())
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
@@ -0,0 +1,217 @@
+package rrd
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testArchive struct {
+	cf     string
+	pdpCnt uint64
+	curRow uint64
+	// rows as they are stored, i.e. rotated by curRow
+	rows [][]float64
+}
+
+// encodeTestFile writes an RRD file the way RRDtool does on 64 bit platforms.
+func encodeTestFile(order binary.ByteOrder, version string, step uint64, lastUp int64, dataSources []DataSource, archives []testArchive) []byte {
+	b := []byte{}
+	putUint64 := func(v uint64) {
+		buf := make([]byte, 8)
+		order.PutUint64(buf, v)
+		b = append(b, buf...)
+	}
+	putString := func(s string, size int) {
+		buf := make([]byte, size)
+		copy(buf, s)
+		b = append(b, buf...)
+	}
+
+	putString("RRD", 4)
+	putString(version, 5)
+	b = append(b, make([]byte, 7)...)
+	putUint64(math.Float64bits(floatCookie))
+	putUint64(uint64(len(dataSources)))
+	putUint64(uint64(len(archives)))
+	putUint64(step)
+	b = append(b, make([]byte, 80)...)
+
+	for _, ds := range dataSources {
+		putString(ds.Name, 20)
+		putString(ds.Type, 20)
+		b = append(b, make([]byte, 80)...)
+	}
+
+	for _, archive := range archives {
+		putString(archive.cf, 24)
+		putUint64(uint64(len(archive.rows)))
+		putUint64(archive.pdpCnt)
+		b = append(b, make([]byte, 80)...)
+	}
+
+	putUint64(uint64(lastUp))
+	if version != "0001" && version != "0002" {
+		putUint64(0)
+	}
+
+	b = append(b, make([]byte, len(dataSources)*pdpPrepSize)...)
+	b = append(b, make([]byte, len(archives)*len(dataSources)*cdpPrepSize)...)
+
+	for _, archive := range archives {
+		putUint64(archive.curRow)
+	}
+
+	for _, archive := range archives {
+		for _, row := range archive.rows {
+			for _, value := range row {
+				putUint64(math.Float64bits(value))
+			}
+		}
+	}
+
+	return b
+}
+
+func TestParse(t *testing.T) {
+	lastUp := time.Date(2022, 3, 1, 12, 0, 25, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		contents []byte
+		expected *File
+		wantErr  bool
+	}{
+		{
+			name: "little endian",
+			contents: encodeTestFile(binary.LittleEndian, "0003", 10, lastUp.Unix(),
+				[]DataSource{{Name: "shortterm", Type: "GAUGE"}, {Name: "midterm", Type: "GAUGE"}},
+				[]testArchive{
+					{
+						cf:     "AVERAGE",
+						pdpCnt: 1,
+						curRow: 1,
+						rows:   [][]float64{{3, 30}, {4, 40}, {1, 10}, {2, 20}},
+					},
+					{
+						cf:     "MAX",
+						pdpCnt: 6,
+						curRow: 1,
+						rows:   [][]float64{{5, 50}, {math.NaN(), 60}},
+					},
+				},
+			),
+			expected: &File{
+				Version:    "0003",
+				Step:       10 * time.Second,
+				LastUpdate: lastUp,
+				DataSources: []DataSource{
+					{Name: "shortterm", Type: "GAUGE"},
+					{Name: "midterm", Type: "GAUGE"},
+				},
+				Archives: []Archive{
+					{
+						ConsolidationFunction: "AVERAGE",
+						Step:                  10 * time.Second,
+						Rows: []Row{
+							{Time: lastUp.Add(-35 * time.Second), Values: []float64{1, 10}},
+							{Time: lastUp.Add(-25 * time.Second), Values: []float64{2, 20}},
+							{Time: lastUp.Add(-15 * time.Second), Values: []float64{3, 30}},
+							{Time: lastUp.Add(-5 * time.Second), Values: []float64{4, 40}},
+						},
+					},
+					{
+						ConsolidationFunction: "MAX",
+						Step:                  time.Minute,
+						Rows: []Row{
+							{Time: lastUp.Add(-85 * time.Second), Values: []float64{5, 50}},
+							{Time: lastUp.Add(-25 * time.Second), Values: []float64{math.NaN(), 60}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "big endian version 0001",
+			contents: encodeTestFile(binary.BigEndian, "0001", 60, lastUp.Unix(),
+				[]DataSource{{Name: "value", Type: "DERIVE"}},
+				[]testArchive{
+					{
+						cf:     "AVERAGE",
+						pdpCnt: 1,
+						curRow: 2,
+						rows:   [][]float64{{1}, {2}, {3}},
+					},
+				},
+			),
+			expected: &File{
+				Version:     "0001",
+				Step:        time.Minute,
+				LastUpdate:  lastUp,
+				DataSources: []DataSource{{Name: "value", Type: "DERIVE"}},
+				Archives: []Archive{
+					{
+						ConsolidationFunction: "AVERAGE",
+						Step:                  time.Minute,
+						Rows: []Row{
+							{Time: lastUp.Add(-145 * time.Second), Values: []float64{1}},
+							{Time: lastUp.Add(-85 * time.Second), Values: []float64{2}},
+							{Time: lastUp.Add(-25 * time.Second), Values: []float64{3}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "not an rrd file",
+			contents: []byte("this is not an rrd file, but it is long enough to have a header. this is not an rrd file, but it is long enough to have a header."),
+			wantErr:  true,
+		},
+		{
+			name: "truncated",
+			contents: encodeTestFile(binary.LittleEndian, "0003", 10, lastUp.Unix(),
+				[]DataSource{{Name: "value", Type: "GAUGE"}},
+				[]testArchive{{cf: "AVERAGE", pdpCnt: 1, rows: [][]float64{{1}, {2}}}},
+			)[:400],
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			actual, err := Parse(test.contents)
+			if test.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			// NaN is never equal to itself, so compare the unknown values separately
+			for i, archive := range actual.Archives {
+				for j, row := range archive.Rows {
+					for k, value := range row.Values {
+						expected := test.expected.Archives[i].Rows[j].Values[k]
+						assert.Equal(t, math.IsNaN(expected), math.IsNaN(value))
+						if math.IsNaN(value) {
+							row.Values[k], test.expected.Archives[i].Rows[j].Values[k] = 0, 0
+						}
+					}
+				}
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFile_DataSourceIndex(t *testing.T) {
+	f := &File{DataSources: []DataSource{{Name: "read"}, {Name: "write"}}}
+
+	assert.Equal(t, 1, f.DataSourceIndex("write"))
+	assert.Equal(t, -1, f.DataSourceIndex("value"))
+}
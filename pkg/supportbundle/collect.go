@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -73,6 +74,7 @@ func runCollectors(collectors []*troubleshootv1beta2.Collect, additionalRedactor
 			ClientConfig: opts.KubernetesRestConfig,
 			Namespace:    opts.Namespace,
 			BundlePath:   bundlePath,
+			Timeout:      opts.CollectorTimeout,
 		}
 		cleanedCollectors = append(cleanedCollectors, &collector)
 	}
@@ -108,30 +110,40 @@ func runCollectors(collectors []*troubleshootv1beta2.Collect, additionalRedactor
 	}
 
 	result := collect.NewResult()
+	var resultMut sync.Mutex
 
-	// Run preflights collectors synchronously
-	for _, collector := range cleanedCollectors {
+	// collectors run concurrently, but the callback is not expected to be safe for concurrent use
+	var progressMut sync.Mutex
+	collectorProgress := func(msg string) {
+		progressMut.Lock()
+		defer progressMut.Unlock()
+		opts.CollectorProgressCallback(opts.ProgressChan, msg)
+	}
+
+	cleanedCollectors.RunConcurrently(opts.Concurrency, func(collector *collect.Collector) {
 		if len(collector.RBACErrors) > 0 {
 			// don't skip clusterResources collector due to RBAC issues
 			if collector.Collect.ClusterResources == nil {
 				msg := fmt.Sprintf("skipping collector %s with insufficient RBAC permissions", collector.GetDisplayName())
-				opts.CollectorProgressCallback(opts.ProgressChan, msg)
-				continue
+				collectorProgress(msg)
+				return
 			}
 		}
 
-		opts.CollectorProgressCallback(opts.ProgressChan, collector.GetDisplayName())
+		collectorProgress(collector.GetDisplayName())
 
 		files, err := collector.RunCollectorSync(opts.KubernetesRestConfig, k8sClient, globalRedactors)
 		if err != nil {
 			opts.ProgressChan <- fmt.Errorf("failed to run collector %q: %v", collector.GetDisplayName(), err)
-			continue
+			return
 		}
 
+		resultMut.Lock()
+		defer resultMut.Unlock()
 		for k, v := range files {
 			result[k] = v
 		}
-	}
+	})
 
 	return result, nil
 }
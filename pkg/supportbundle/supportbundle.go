@@ -30,6 +30,12 @@ type SupportBundleCreateOpts struct {
 	OutputPath                string
 	Redact                    bool
 	FromCLI                   bool
+	// Concurrency is the maximum number of collectors run at the same time. Values less than 1 run them one at a time.
+	Concurrency int
+	// CollectorTimeout bounds how long each collector is allowed to run. Zero means no limit.
+	CollectorTimeout time.Duration
+	// AnalyzerConcurrency is the maximum number of analyzers run at the same time. Zero uses the number of CPUs.
+	AnalyzerConcurrency int
 }
 
 type SupportBundleResponse struct {
@@ -112,7 +118,7 @@ func CollectSupportBundleFromSpec(spec *troubleshootv1beta2.SupportBundleSpec, a
 	}
 
 	// Run Analyzers
-	analyzeResults, err := AnalyzeSupportBundle(spec, bundlePath)
+	analyzeResults, err := AnalyzeSupportBundleWithConcurrency(spec, bundlePath, opts.AnalyzerConcurrency)
 	if err != nil {
 		if opts.FromCLI {
 			c := color.New(color.FgHiRed)
@@ -201,10 +207,16 @@ func ProcessSupportBundleAfterCollection(spec *troubleshootv1beta2.SupportBundle
 // AnalyzeSupportBundle performs analysis on a support bundle using the support bundle spec and an already unpacked support
 // bundle on disk
 func AnalyzeSupportBundle(spec *troubleshootv1beta2.SupportBundleSpec, tmpDir string) ([]*analyzer.AnalyzeResult, error) {
+	return AnalyzeSupportBundleWithConcurrency(spec, tmpDir, 0)
+}
+
+// AnalyzeSupportBundleWithConcurrency is AnalyzeSupportBundle running at most concurrency analyzers
+// at the same time. A concurrency of 0 uses the number of CPUs.
+func AnalyzeSupportBundleWithConcurrency(spec *troubleshootv1beta2.SupportBundleSpec, tmpDir string, concurrency int) ([]*analyzer.AnalyzeResult, error) {
 	if len(spec.Analyzers) == 0 {
 		return nil, nil
 	}
-	analyzeResults, err := analyzer.AnalyzeLocal(tmpDir, spec.Analyzers)
+	analyzeResults, err := analyzer.AnalyzeLocalWithConcurrency(tmpDir, spec.Analyzers, concurrency)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to analyze support bundle")
 	}